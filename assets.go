@@ -0,0 +1,20 @@
+// Package kbudailymenu embeds the files the server needs at runtime, so the binary can run from any directory.
+package kbudailymenu
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+)
+
+//go:embed migrations/*.sql templates/*.html web
+var embedded embed.FS
+
+// Assets returns the migrations, templates and web files rooted at the repository layout.
+// A non-empty dir serves them from disk instead, so edits show up without rebuilding.
+func Assets(dir string) fs.FS {
+	if dir != "" {
+		return os.DirFS(dir)
+	}
+	return embedded
+}
@@ -0,0 +1,47 @@
+package kbudailymenu
+
+import (
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEmbeddedAssets(t *testing.T) {
+	assets := Assets("")
+
+	migrations, err := fs.Glob(assets, "migrations/*.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("embedded migrations = %v, %v", migrations, err)
+	}
+	onDisk, err := filepath.Glob(filepath.Join("migrations", "*.sql"))
+	if err != nil || len(onDisk) != len(migrations) {
+		t.Fatalf("embedded %d migrations, repository has %d", len(migrations), len(onDisk))
+	}
+
+	if _, err := template.ParseFS(assets, "templates/*.html"); err != nil {
+		t.Fatalf("parse embedded templates: %v", err)
+	}
+
+	for _, name := range []string{"web/static/styles.css", "web/static/stickers/kto.webp"} {
+		if _, err := fs.Stat(assets, name); err != nil {
+			t.Errorf("embedded %s: %v", name, err)
+		}
+	}
+}
+
+func TestAssetsOverrideDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "templates"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "templates", "index.html"), []byte("draft"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	content, err := fs.ReadFile(Assets(dir), "templates/index.html")
+	if err != nil || string(content) != "draft" {
+		t.Fatalf("override index.html = %q, %v", content, err)
+	}
+}
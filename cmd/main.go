@@ -3,16 +3,21 @@ package main
 import (
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
 	"os/signal"
 	"syscall"
 
+	kbudailymenu "github.com/artyom-kalman/kbu-daily-menu"
 	"github.com/artyom-kalman/kbu-daily-menu/internal/ai"
 	"github.com/artyom-kalman/kbu-daily-menu/internal/bot"
 	"github.com/artyom-kalman/kbu-daily-menu/internal/config"
 	"github.com/artyom-kalman/kbu-daily-menu/internal/database"
 	"github.com/artyom-kalman/kbu-daily-menu/internal/http"
 	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menucard"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/rating"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/snapshot"
 	"github.com/artyom-kalman/kbu-daily-menu/pkg/logger"
 )
 
@@ -25,8 +30,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	assets := kbudailymenu.Assets(cfg.AssetsDir)
+	if cfg.AssetsDir != "" {
+		slog.Info("Serving assets from disk", "dir", cfg.AssetsDir)
+	}
+
 	// Initialize database
-	db, err := database.Init(cfg.DatabasePath, cfg.MigrationPath)
+	db, err := database.Init(cfg.DatabasePath, assets, cfg.MigrationPath)
 	if err != nil {
 		slog.Error("Failed to initialize database", "err", err)
 		os.Exit(1)
@@ -37,6 +47,9 @@ func main() {
 	menuClock := menu.NewKSTClock()
 	peonyFetcher := menu.NewMenuFetcherService(cfg.PeonyURL, gptService, menuClock)
 	azileaFetcher := menu.NewMenuFetcherService(cfg.AzileaURL, gptService, menuClock)
+	snapshots := snapshot.NewRepository(db)
+	peonyFetcher.EnableSnapshots(snapshots)
+	azileaFetcher.EnableSnapshots(snapshots)
 
 	menuRepo := menu.NewMenuRepository(db)
 	persistenceService := menu.NewMenuPersistenceService(menuRepo, menuClock)
@@ -46,8 +59,23 @@ func main() {
 		menu.AZILEA: azileaFetcher,
 	})
 
-	updater := menu.NewMenuUpdater(menuService)
+	updater := menu.NewMenuUpdater(menuService, menuClock)
+	if cfg.MenuPollInterval > 0 {
+		updater.EnablePolling(cfg.MenuPollInterval, cfg.MenuPollCutoff)
+	}
 	scheduler := menu.NewMenuScheduler(updater, menuClock)
+	for cafeteria, schedule := range map[menu.Cafeteria][]string{
+		menu.PEONY:  cfg.PeonySchedule,
+		menu.AZILEA: cfg.AzileaSchedule,
+	} {
+		if len(schedule) == 0 {
+			continue
+		}
+		if err := scheduler.SetSchedule(cafeteria, schedule); err != nil {
+			slog.Error("Invalid menu schedule", "error", err)
+			os.Exit(1)
+		}
+	}
 
 	if err := scheduler.Start(); err != nil {
 		slog.Error("Failed to start scheduler", "error", err)
@@ -61,7 +89,36 @@ func main() {
 		os.Exit(1)
 	}
 
+	if len(cfg.AdminChatIDs) > 0 {
+		botInstance.EnableAdminCommands(cfg.AdminChatIDs, menuService)
+	}
+
+	menuCards := menucard.NewRenderer(assets, "web/static/stickers/kto.webp")
+	botInstance.EnableMenuCards(menuCards)
+
+	ratingRepo := rating.NewRepository(db)
+	ratings := rating.NewService(ratingRepo, menuService, menuClock)
+	botInstance.EnableRatings(ratings)
+	botInstance.EnableRecommendations(rating.NewRecommender(ratingRepo, persistenceService, menuClock))
+
+	menuService.OnMenuChange(botInstance.HandleMenuChange)
+	updater.OnUpdate(botInstance.CheckWatches)
+
 	server := http.NewServer(scheduler, menuService)
+	server.EnableMenuCards(menuService, menuCards, menuClock)
+	server.EnableRatings(ratings, menuClock)
+	server.EnableMenuHistory(menuService, menuClock)
+	if cfg.TelegramMode == config.TelegramModeWebhook {
+		webhookURL, err := url.Parse(cfg.TelegramWebhookURL)
+		if err != nil {
+			slog.Error("Invalid Telegram webhook URL", "err", err)
+			os.Exit(1)
+		}
+
+		botInstance.UseWebhook(cfg.TelegramWebhookURL, cfg.TelegramWebhookSecret)
+		server.EnableTelegramWebhook(webhookURL.Path, cfg.TelegramWebhookSecret, botInstance)
+	}
+	server.SetAssets(assets)
 	server.SetupRouter()
 
 	errChan := make(chan error, 1)
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	kbudailymenu "github.com/artyom-kalman/kbu-daily-menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/config"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database"
+)
+
+func defaultDatabasePath() string {
+	return config.GetEnvWithDefault("DATABASE_PATH", "./database/daily-menu.db")
+}
+
+// openDatabase opens an existing database; unlike the server it neither creates the file nor migrates it.
+func openDatabase(path string) (*database.Database, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("open database: %w (run menuctl migrate up to create it)", err)
+	}
+
+	db, err := database.NewDatabase(path)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	return db, nil
+}
+
+func runMigrate(args []string, stdout io.Writer) error {
+	const usage = "usage: menuctl migrate (up | down [--steps N] | status) [--db path] [--migrations dir]"
+
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	var (
+		dbPath        = flags.String("db", defaultDatabasePath(), "database file")
+		migrationsDir = flags.String("migrations", "", "directory with migration files (default the migrations built into menuctl)")
+		steps         = flags.Int("steps", 1, "number of migrations to roll back with down")
+	)
+	if err := flags.Parse(args[1:]); err != nil {
+		return fmt.Errorf("migrate: %w\n\n%s", err, usage)
+	}
+
+	var (
+		db  *database.Database
+		err error
+	)
+	switch action {
+	case "up":
+		db, err = database.NewDatabase(*dbPath)
+	case "down", "status":
+		db, err = openDatabase(*dbPath)
+	default:
+		return fmt.Errorf("migrate: unknown action %q\n\n%s", action, usage)
+	}
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	defer db.Close()
+
+	migrationFS, migrationPath := kbudailymenu.Assets(os.Getenv("ASSETS_DIR")), config.GetEnvWithDefault("MIGRATION_PATH", "migrations")
+	if *migrationsDir != "" {
+		migrationFS, migrationPath = os.DirFS(*migrationsDir), "."
+	}
+
+	migrator := database.NewMigrator(db)
+	if err := migrator.LoadMigrationsFromFS(migrationFS, migrationPath); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+
+	switch action {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		err = migrator.Down(*steps)
+	}
+	if err != nil {
+		return fmt.Errorf("migrate %s: %w", action, err)
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "VERSION\tSTATUS\tAPPLIED AT\tDOWN")
+	for _, status := range statuses {
+		appliedAt := ""
+		if status.Applied {
+			appliedAt = status.AppliedAt.Format(time.DateTime)
+		}
+		reversible := "no"
+		if status.Reversible {
+			reversible = "yes"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", status.Version, migrationState(status), appliedAt, reversible)
+	}
+	return writer.Flush()
+}
+
+func migrationState(status database.MigrationStatus) string {
+	switch {
+	case status.Missing:
+		return "applied, file missing"
+	case status.Modified:
+		return "applied, file modified"
+	case status.Applied:
+		return "applied"
+	default:
+		return "pending"
+	}
+}
+
+// runBackup copies the database with VACUUM INTO, which yields a consistent file while the server keeps running.
+func runBackup(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	dbPath := flags.String("db", defaultDatabasePath(), "database file")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("backup: %w\n\nusage: menuctl backup [--db path] [target.db]", err)
+	}
+	if flags.NArg() > 1 {
+		return errors.New("backup: pass at most one target file")
+	}
+
+	target := flags.Arg(0)
+	if target == "" {
+		target = *dbPath + ".backup-" + time.Now().Format("20060102-150405")
+	}
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("backup: %s already exists", target)
+	}
+
+	db, err := openDatabase(*dbPath)
+	if err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Conn.Exec("VACUUM INTO ?", target); err != nil {
+		return fmt.Errorf("backup: copy %s to %s: %w", *dbPath, target, err)
+	}
+
+	fmt.Fprintln(stdout, target)
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runCommand(t *testing.T, args ...string) string {
+	t.Helper()
+
+	var stdout bytes.Buffer
+	if err := run(args, &stdout); err != nil {
+		t.Fatalf("menuctl %s: %v", strings.Join(args, " "), err)
+	}
+	return stdout.String()
+}
+
+// newTestDatabase creates a migrated database file the way an operator would.
+func newTestDatabase(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "menu.db")
+	runCommand(t, "migrate", "up", "--db", path)
+	return path
+}
+
+func TestMigrateStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "menu.db")
+
+	if err := run([]string{"migrate", "status", "--db", path}, &bytes.Buffer{}); err == nil {
+		t.Fatalf("migrate status created a missing database")
+	}
+
+	up := runCommand(t, "migrate", "up", "--db", path)
+	if strings.Contains(up, "pending") || !strings.Contains(up, "001_create_menu_table") {
+		t.Fatalf("migrate up output:\n%s", up)
+	}
+
+	// Versions already applied stay listed even when their files are not loaded.
+	migrations := t.TempDir()
+	if err := os.WriteFile(filepath.Join(migrations, "099_add_later.sql"), []byte("CREATE TABLE later (id INTEGER);"), 0o644); err != nil {
+		t.Fatalf("write migration: %v", err)
+	}
+
+	status := strings.Join(strings.Fields(runCommand(t, "migrate", "status", "--db", path, "--migrations", migrations)), " ")
+	for _, want := range []string{"001_create_menu_table applied", "010_create_page_snapshots applied, file missing", "099_add_later pending"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("status missing %q: %s", want, status)
+		}
+	}
+}
+
+func TestBackup(t *testing.T) {
+	path := newTestDatabase(t)
+	runCommand(t, "subscribers", "import", "--db", path, filepath.Join("testdata", "subscribers.csv"))
+
+	target := filepath.Join(t.TempDir(), "copy.db")
+	if got := strings.TrimSpace(runCommand(t, "backup", "--db", path, target)); got != target {
+		t.Fatalf("backup printed %q, want %q", got, target)
+	}
+
+	list := runCommand(t, "subscribers", "list", "--db", target)
+	if !strings.Contains(list, "3 active subscribers") {
+		t.Fatalf("backup lost subscribers:\n%s", list)
+	}
+
+	if err := run([]string{"backup", "--db", path, target}, &bytes.Buffer{}); err == nil {
+		t.Fatalf("backup overwrote an existing file")
+	}
+}
@@ -0,0 +1,86 @@
+// Command menuctl is an operator tool for the menu pipeline.
+//
+//	menuctl replay [flags] <page.html>
+//	menuctl replay [flags] --snapshot <id>
+//	menuctl refresh <cafeteria> [--date YYYY-MM-DD]
+//	menuctl show <cafeteria> [--date YYYY-MM-DD]
+//	menuctl edit-dish <cafeteria> --dish N [--name name] [--description text]
+//	menuctl subscribers (list | export | import <file.csv>)
+//	menuctl migrate (up | down [--steps N] | status)
+//	menuctl backup [target.db]
+//
+// Commands that touch the database take --db, which defaults to DATABASE_PATH.
+// Nothing here starts the bot or the HTTP server.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+var errUsage = errors.New("usage: menuctl <command> [flags]\n\ncommands:\n" +
+	"  replay       run a saved menu page through the parser and AI pipeline\n" +
+	"  refresh      fetch a cafeteria menu and store it\n" +
+	"  show         print a stored menu\n" +
+	"  edit-dish    correct a dish in today's stored menu\n" +
+	"  subscribers  list, export or import bot subscribers\n" +
+	"  migrate      apply or roll back migrations, or show their status\n" +
+	"  backup       copy the database to a file")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "replay":
+		return runReplay(args[1:], stdout)
+	case "refresh":
+		return runRefresh(args[1:], stdout)
+	case "show":
+		return runShow(args[1:], stdout)
+	case "edit-dish":
+		return runEditDish(args[1:], stdout)
+	case "subscribers":
+		return runSubscribers(args[1:], stdout)
+	case "migrate":
+		return runMigrate(args[1:], stdout)
+	case "backup":
+		return runBackup(args[1:], stdout)
+	case "help", "-h", "--help":
+		fmt.Fprintln(stdout, errUsage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n\n%w", args[0], errUsage)
+	}
+}
+
+// fixedClock pins the pipeline to the replayed day.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// After fires at once: a fixed clock never moves, so there is nothing to wait for.
+func (c fixedClock) After(time.Duration) <-chan time.Time {
+	fired := make(chan time.Time, 1)
+	fired <- time.Time(c)
+	return fired
+}
+
+func kstLocation() *time.Location {
+	location, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		return time.FixedZone("KST", 9*60*60)
+	}
+	return location
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/config"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+var cafeteriaURLEnv = map[menu.Cafeteria]string{
+	menu.PEONY:  "PEONY_URL",
+	menu.AZILEA: "AZILEA_URL",
+}
+
+func parseCafeteria(name string) (menu.Cafeteria, error) {
+	cafeteria := menu.Cafeteria(name)
+	if _, ok := cafeteriaURLEnv[cafeteria]; !ok {
+		return "", fmt.Errorf("unknown cafeteria %q, expected peony or azilea", name)
+	}
+	return cafeteria, nil
+}
+
+// cafeteriaArgs splits the leading cafeteria name from the flags that follow it.
+func cafeteriaArgs(args []string) (menu.Cafeteria, []string, error) {
+	if len(args) == 0 {
+		return "", nil, errors.New("missing cafeteria: peony or azilea")
+	}
+	cafeteria, err := parseCafeteria(args[0])
+	return cafeteria, args[1:], err
+}
+
+// newMenuService wires a MenuService without the bot, so no change notifications are sent.
+// An empty cafeteria configures no fetcher, for commands that only touch stored menus.
+func newMenuService(db *database.Database, cafeteria menu.Cafeteria, aiService menu.AIService, clock menu.Clock) (*menu.MenuService, error) {
+	fetchers := map[menu.Cafeteria]*menu.MenuFetcherService{}
+	if cafeteria != "" {
+		url, err := config.GetEnv(cafeteriaURLEnv[cafeteria])
+		if err != nil {
+			return nil, err
+		}
+		fetchers[cafeteria] = menu.NewMenuFetcherService(url, aiService, clock)
+	}
+
+	persistence := menu.NewMenuPersistenceService(menu.NewMenuRepository(db), clock)
+	return menu.NewMenuService(persistence, fetchers), nil
+}
+
+func runRefresh(args []string, stdout io.Writer) error {
+	const usage = "usage: menuctl refresh <peony|azilea> [--date 2025-10-22] [--no-ai | --fake-ai] [--db path]"
+
+	cafeteria, args, err := cafeteriaArgs(args)
+	if err != nil {
+		return fmt.Errorf("refresh: %w\n\n%s", err, usage)
+	}
+
+	flags := flag.NewFlagSet("refresh", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	var (
+		dateFlag = flags.String("date", "", "menu date as YYYY-MM-DD (default today)")
+		dbPath   = flags.String("db", defaultDatabasePath(), "database file")
+		noAI     = flags.Bool("no-ai", false, "skip AI validation and enrichment")
+		fakeAI   = flags.Bool("fake-ai", false, "answer AI requests locally with placeholder data")
+	)
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("refresh: %w\n\n%s", err, usage)
+	}
+	if *noAI && *fakeAI {
+		return errors.New("refresh: --no-ai and --fake-ai are mutually exclusive")
+	}
+
+	date, err := replayDate("", *dateFlag, time.Now().In(kstLocation()))
+	if err != nil {
+		return fmt.Errorf("refresh: %w", err)
+	}
+
+	var aiService menu.AIService
+	switch {
+	case *noAI:
+	case *fakeAI:
+		aiService = fakeAIService{}
+	default:
+		aiService, err = realAIService()
+		if err != nil {
+			return fmt.Errorf("refresh: %w (use --no-ai or --fake-ai to skip the AI)", err)
+		}
+	}
+
+	db, err := openDatabase(*dbPath)
+	if err != nil {
+		return fmt.Errorf("refresh: %w", err)
+	}
+	defer db.Close()
+
+	service, err := newMenuService(db, cafeteria, aiService, menu.NewKSTClock())
+	if err != nil {
+		return fmt.Errorf("refresh: %w", err)
+	}
+
+	refreshed, err := service.RefreshMenuForDate(context.Background(), cafeteria, date)
+	if err != nil {
+		return fmt.Errorf("refresh: %w", err)
+	}
+
+	return printMenu(stdout, cafeteria, date, refreshed, false)
+}
+
+func runShow(args []string, stdout io.Writer) error {
+	const usage = "usage: menuctl show <peony|azilea> [--date 2025-10-22] [--json] [--db path]"
+
+	cafeteria, args, err := cafeteriaArgs(args)
+	if err != nil {
+		return fmt.Errorf("show: %w\n\n%s", err, usage)
+	}
+
+	flags := flag.NewFlagSet("show", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	var (
+		dateFlag = flags.String("date", "", "menu date as YYYY-MM-DD (default today)")
+		dbPath   = flags.String("db", defaultDatabasePath(), "database file")
+		asJSON   = flags.Bool("json", false, "print the stored menu as JSON")
+	)
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("show: %w\n\n%s", err, usage)
+	}
+
+	date, err := replayDate("", *dateFlag, time.Now().In(kstLocation()))
+	if err != nil {
+		return fmt.Errorf("show: %w", err)
+	}
+
+	db, err := openDatabase(*dbPath)
+	if err != nil {
+		return fmt.Errorf("show: %w", err)
+	}
+	defer db.Close()
+
+	// Only what is stored is shown; a missing menu is never fetched from the cafeteria page.
+	persistence := menu.NewMenuPersistenceService(menu.NewMenuRepository(db), menu.NewKSTClock())
+	stored, err := persistence.LoadMenuForDate(cafeteria, date)
+	if err != nil {
+		return fmt.Errorf("show: %w", err)
+	}
+	if stored == nil {
+		return fmt.Errorf("show: no stored menu for %s on %s", cafeteria, date.Format("2006-01-02"))
+	}
+
+	return printMenu(stdout, cafeteria, date, stored, *asJSON)
+}
+
+func runEditDish(args []string, stdout io.Writer) error {
+	const usage = "usage: menuctl edit-dish <peony|azilea> --dish N [--name name] [--description text] [--db path]"
+
+	cafeteria, args, err := cafeteriaArgs(args)
+	if err != nil {
+		return fmt.Errorf("edit-dish: %w\n\n%s", err, usage)
+	}
+
+	flags := flag.NewFlagSet("edit-dish", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	var (
+		position    = flags.Int("dish", 0, "1-based position of the dish in today's menu")
+		name        = flags.String("name", "", "new dish name")
+		description = flags.String("description", "", "new dish description")
+		dbPath      = flags.String("db", defaultDatabasePath(), "database file")
+	)
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("edit-dish: %w\n\n%s", err, usage)
+	}
+	if *position == 0 || (*name == "" && *description == "") {
+		return errors.New(usage)
+	}
+
+	db, err := openDatabase(*dbPath)
+	if err != nil {
+		return fmt.Errorf("edit-dish: %w", err)
+	}
+	defer db.Close()
+
+	clock := menu.NewKSTClock()
+	service, err := newMenuService(db, "", nil, clock)
+	if err != nil {
+		return fmt.Errorf("edit-dish: %w", err)
+	}
+
+	edited, err := service.EditDish(context.Background(), cafeteria, *position, *name, *description)
+	if err != nil {
+		return fmt.Errorf("edit-dish: %w", err)
+	}
+
+	return printMenu(stdout, cafeteria, clock.Now(), edited, false)
+}
+
+func printMenu(stdout io.Writer, cafeteria menu.Cafeteria, date time.Time, dishes *menu.Menu, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(dishes)
+	}
+
+	fmt.Fprintf(stdout, "%s %s\n", cafeteria, date.Format("2006-01-02"))
+	for i, item := range dishes.Items {
+		if item.Description == "" {
+			fmt.Fprintf(stdout, "%d. %s\n", i+1, item.Name)
+			continue
+		}
+		fmt.Fprintf(stdout, "%d. %s — %s\n", i+1, item.Name, item.Description)
+	}
+	return nil
+}
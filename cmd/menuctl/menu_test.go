@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+func TestRefreshAndShow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, filepath.Join("testdata", "weekly_menu.html"))
+	}))
+	defer server.Close()
+	t.Setenv("PEONY_URL", server.URL)
+
+	path := newTestDatabase(t)
+
+	// The page only covers the current week; its second list is Tuesday.
+	tuesday := menu.StartOfWeek(time.Now().In(kstLocation())).AddDate(0, 0, 1).Format("2006-01-02")
+
+	if err := run([]string{"show", "peony", "--date", tuesday, "--db", path}, &bytes.Buffer{}); err == nil {
+		t.Fatalf("show printed a menu that was never stored")
+	}
+
+	refreshed := runCommand(t, "refresh", "peony", "--date", tuesday, "--no-ai", "--db", path)
+	if !strings.HasPrefix(refreshed, "peony "+tuesday+"\n1. 된장국 — ") {
+		t.Fatalf("refresh output:\n%s", refreshed)
+	}
+
+	if shown := runCommand(t, "show", "peony", "--date", tuesday, "--db", path); shown != refreshed {
+		t.Fatalf("show = %q, want the refreshed menu %q", shown, refreshed)
+	}
+
+	db, err := database.NewDatabase(path)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	var stored string
+	if err := db.Conn.QueryRow("SELECT date FROM menu WHERE cafeteria = 'peony'").Scan(&stored); err != nil {
+		t.Fatalf("query stored menu: %v", err)
+	}
+	if !strings.HasPrefix(stored, tuesday) {
+		t.Fatalf("refresh stored the menu under %q, want %s", stored, tuesday)
+	}
+}
+
+func TestEditDish(t *testing.T) {
+	path := newTestDatabase(t)
+
+	db, err := database.NewDatabase(path)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	persistence := menu.NewMenuPersistenceService(menu.NewMenuRepository(db), menu.NewKSTClock())
+	stored := menu.NewMenu([]*menu.MenuItem{{Name: "김치찌개"}, {Name: "밥", Description: "Рис"}}, nil)
+	if err := persistence.SaveMenu(menu.AZILEA, stored); err != nil {
+		t.Fatalf("save menu: %v", err)
+	}
+	db.Close()
+
+	edited := runCommand(t, "edit-dish", "azilea", "--dish", "1", "--description", "Острое рагу с кимчи", "--db", path)
+	if !strings.Contains(edited, "1. 김치찌개 — Острое рагу с кимчи\n2. 밥 — Рис\n") {
+		t.Fatalf("edit-dish output:\n%s", edited)
+	}
+
+	tests := [][]string{
+		{"edit-dish", "azilea", "--dish", "3", "--name", "X"},
+		{"edit-dish", "azilea", "--dish", "1"},
+		{"edit-dish", "canteen", "--dish", "1", "--name", "X"},
+	}
+	for _, args := range tests {
+		if err := run(append(args, "--db", path), &bytes.Buffer{}); err == nil {
+			t.Errorf("menuctl %s succeeded", strings.Join(args, " "))
+		}
+	}
+}
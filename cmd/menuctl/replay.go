@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/ai"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/config"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/http/fetcher"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/snapshot"
+)
+
+var weekdays = map[string]time.Weekday{
+	"mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+}
+
+func runReplay(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	var (
+		weekday    = flags.String("weekday", "", "day of the weekly page to parse: mon..sun or 1..7 (default today)")
+		dateFlag   = flags.String("date", "", "menu date as YYYY-MM-DD instead of --weekday")
+		snapshotID = flags.Int64("snapshot", 0, "replay a stored page snapshot instead of a file")
+		dbPath     = flags.String("db", config.GetEnvWithDefault("DATABASE_PATH", "./database/daily-menu.db"), "database with page snapshots")
+		noAI       = flags.Bool("no-ai", false, "skip AI validation and enrichment")
+		fakeAI     = flags.Bool("fake-ai", false, "answer AI requests locally with placeholder data")
+	)
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("replay: %w\n\nusage: menuctl replay [--weekday mon | --date 2025-10-22] [--no-ai | --fake-ai] (<page.html> | --snapshot <id> [--db path])", err)
+	}
+	if *noAI && *fakeAI {
+		return errors.New("replay: --no-ai and --fake-ai are mutually exclusive")
+	}
+
+	date, err := replayDate(*weekday, *dateFlag, time.Now().In(kstLocation()))
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+
+	body, err := loadPage(flags.Args(), *snapshotID, *dbPath)
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+
+	var aiService menu.AIService
+	switch {
+	case *noAI:
+	case *fakeAI:
+		aiService = fakeAIService{}
+	default:
+		aiService, err = realAIService()
+		if err != nil {
+			return fmt.Errorf("replay: %w (use --no-ai or --fake-ai to run offline)", err)
+		}
+	}
+
+	clock := fixedClock(date)
+	menuFetcher := menu.NewAIMenuFetcher(menu.NewHTMLMenuSource(body, clock), aiService, clock)
+
+	result, err := menuFetcher.FetchMenuForDate(context.Background(), date)
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// replayDate picks the menu day: an explicit date, or the given weekday of the current week.
+func replayDate(weekday, date string, now time.Time) (time.Time, error) {
+	if date != "" {
+		if weekday != "" {
+			return time.Time{}, errors.New("--weekday and --date are mutually exclusive")
+		}
+		parsed, err := time.ParseInLocation("2006-01-02", date, now.Location())
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --date %q: %w", date, err)
+		}
+		return parsed, nil
+	}
+
+	if weekday == "" {
+		return now, nil
+	}
+
+	// Lowercasing can change the length, so the prefix is cut from the lowercased name.
+	name := strings.ToLower(weekday)
+	target, ok := weekdays[name[:min(3, len(name))]]
+	if number, err := strconv.Atoi(weekday); err == nil && number >= 1 && number <= 7 {
+		target, ok = time.Weekday(number%7), true
+	}
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid --weekday %q, expected mon..sun or 1..7", weekday)
+	}
+
+	// Weeks start on Monday, like the cafeteria pages.
+	offset := (int(target)+6)%7 - (int(now.Weekday())+6)%7
+	return now.AddDate(0, 0, offset), nil
+}
+
+func loadPage(args []string, snapshotID int64, dbPath string) (string, error) {
+	switch {
+	case snapshotID != 0 && len(args) > 0:
+		return "", errors.New("pass either a file or --snapshot, not both")
+	case snapshotID != 0:
+		db, err := database.NewDatabase(dbPath)
+		if err != nil {
+			return "", fmt.Errorf("open database: %w", err)
+		}
+		defer db.Close()
+
+		stored, err := snapshot.NewRepository(db).Get(snapshotID)
+		if err != nil {
+			return "", err
+		}
+		if stored == nil {
+			return "", fmt.Errorf("snapshot %d not found", snapshotID)
+		}
+		return stored.Body, nil
+	case len(args) == 1:
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return "", err
+		}
+		// Pages saved from the browser keep the cafeteria's encoding; the <meta> tag tells which.
+		return fetcher.DecodeBody(data, "")
+	default:
+		return "", errors.New("pass one saved page file or --snapshot <id>")
+	}
+}
+
+func realAIService() (menu.AIService, error) {
+	token, err := config.GetEnv("GPT_TOKEN")
+	if err != nil {
+		return nil, err
+	}
+	url, err := config.GetEnv("GPT_URL")
+	if err != nil {
+		return nil, err
+	}
+	return ai.NewGptService(token, url), nil
+}
+
+// fakeAIService approves every menu and describes dishes with placeholders, so the whole pipeline
+// can run without network access. One answer serves both prompts; each reads only its own fields.
+type fakeAIService struct{}
+
+func (fakeAIService) SendRequest(ctx context.Context, messages []*ai.Message) (any, error) {
+	// Prompts end with "...: <dish>"; without that suffix the whole prompt stands in for the dish.
+	dish := messages[len(messages)-1].Content
+	if i := strings.LastIndex(dish, ": "); i >= 0 {
+		dish = dish[i+2:]
+	}
+
+	answer, err := json.Marshal(map[string]any{
+		"is_valid":    true,
+		"reason":      "fake AI",
+		"description": "Описание для " + dish,
+		"spiciness":   0,
+		"category":    "горячее",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return string(answer), nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/ai"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/snapshot"
+)
+
+func TestReplayDate(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	// A Wednesday.
+	now := time.Date(2025, time.October, 22, 11, 0, 0, 0, kst)
+
+	tests := []struct {
+		weekday, date string
+		want          time.Time
+		wantErr       bool
+	}{
+		{want: now},
+		{weekday: "mon", want: now.AddDate(0, 0, -2)},
+		{weekday: "Friday", want: now.AddDate(0, 0, 2)},
+		{weekday: "7", want: now.AddDate(0, 0, 4)},
+		{date: "2025-10-20", want: time.Date(2025, time.October, 20, 0, 0, 0, 0, kst)},
+		{weekday: "someday", wantErr: true},
+		// The Kelvin sign lowercases to a shorter "k".
+		{weekday: "\u212A", wantErr: true},
+		{weekday: "mon", date: "2025-10-20", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := replayDate(tt.weekday, tt.date, now)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && !got.Equal(tt.want)) {
+			t.Errorf("replayDate(%q, %q) = %v, %v; want %v", tt.weekday, tt.date, got, err, tt.want)
+		}
+	}
+}
+
+func TestFakeAIServiceDescribesDish(t *testing.T) {
+	tests := []struct {
+		prompt string
+		want   string
+	}{
+		{prompt: "Опиши блюдо: 김치찌개", want: "Описание для 김치찌개"},
+		{prompt: "김치찌개", want: "Описание для 김치찌개"},
+		{prompt: "", want: "Описание для "},
+	}
+
+	for _, tt := range tests {
+		answer, err := fakeAIService{}.SendRequest(context.Background(), []*ai.Message{{Content: tt.prompt}})
+		if err != nil {
+			t.Fatalf("SendRequest(%q) error = %v", tt.prompt, err)
+		}
+
+		var got struct {
+			Description string `json:"description"`
+		}
+		if err := json.Unmarshal([]byte(answer.(string)), &got); err != nil || got.Description != tt.want {
+			t.Errorf("SendRequest(%q) description = %q, %v; want %q", tt.prompt, got.Description, err, tt.want)
+		}
+	}
+}
+
+func replay(t *testing.T, args ...string) menu.Menu {
+	t.Helper()
+
+	var stdout bytes.Buffer
+	if err := run(append([]string{"replay"}, args...), &stdout); err != nil {
+		t.Fatalf("replay %q: %v", args, err)
+	}
+
+	var result menu.Menu
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout.String())
+	}
+	return result
+}
+
+func TestReplayFile(t *testing.T) {
+	page := filepath.Join("testdata", "weekly_menu.html")
+
+	plain := replay(t, "--weekday", "tue", "--no-ai", page)
+	if len(plain.Items) != 3 || plain.Items[1].Name != "제육볶음" || plain.Items[1].Description != "TODO" {
+		t.Errorf("--no-ai replay = %+v, want Tuesday's dishes without descriptions", plain.Items)
+	}
+
+	enriched := replay(t, "--date", "2025-10-24", "--fake-ai", page)
+	if len(enriched.Items) != 3 || enriched.Items[0].Name != "불고기" || enriched.Items[0].Description != "Описание для 불고기" {
+		t.Errorf("--fake-ai replay = %+v, want Friday's dishes with fake descriptions", enriched.Items)
+	}
+}
+
+func TestReplayLegacyEncodedFile(t *testing.T) {
+	result := replay(t, "--weekday", "mon", "--no-ai", filepath.Join("testdata", "weekly_menu_euckr.html"))
+	if len(result.Items) != 3 || result.Items[0].Name != "김치찌개" {
+		t.Errorf("replay of an EUC-KR page = %+v, want Monday's dishes in UTF-8", result.Items)
+	}
+}
+
+func TestReplaySnapshot(t *testing.T) {
+	dbPath := newTestDatabase(t)
+	db, err := database.NewDatabase(dbPath)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	page, err := os.ReadFile(filepath.Join("testdata", "weekly_menu.html"))
+	if err != nil {
+		t.Fatalf("read page: %v", err)
+	}
+	repo := snapshot.NewRepository(db)
+	if err := repo.SaveSnapshot("https://peony.example.com", "hash", string(page), time.Now()); err != nil {
+		t.Fatalf("save snapshot: %v", err)
+	}
+	stored, err := repo.List("", 1)
+	if err != nil || len(stored) != 1 {
+		t.Fatalf("list snapshots: %v", err)
+	}
+
+	result := replay(t, "--weekday", "mon", "--no-ai", "--db", dbPath, "--snapshot", strconv.FormatInt(stored[0].ID, 10))
+	if len(result.Items) != 3 || result.Items[0].Name != "김치찌개" {
+		t.Errorf("snapshot replay = %+v, want Monday's dishes", result.Items)
+	}
+
+	if err := run([]string{"replay", "--no-ai", "--db", dbPath, "--snapshot", "999"}, &bytes.Buffer{}); err == nil {
+		t.Error("replaying a missing snapshot succeeded")
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/bot"
+)
+
+var subscriberHeader = []string{"chat_id", "thread_id", "format"}
+
+func runSubscribers(args []string, stdout io.Writer) error {
+	const usage = "usage: menuctl subscribers (list | export [--output file] | import <file.csv | ->) [--db path]"
+
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("subscribers", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	var (
+		dbPath = flags.String("db", defaultDatabasePath(), "database file")
+		output = flags.String("output", "", "export to a file instead of stdout")
+	)
+	if err := flags.Parse(args[1:]); err != nil {
+		return fmt.Errorf("subscribers: %w\n\n%s", err, usage)
+	}
+
+	db, err := openDatabase(*dbPath)
+	if err != nil {
+		return fmt.Errorf("subscribers: %w", err)
+	}
+	defer db.Close()
+	repo := bot.NewSubscriptionRepository(db)
+
+	switch action {
+	case "list":
+		err = listSubscribers(repo, stdout)
+	case "export":
+		err = exportSubscribers(repo, *output, stdout)
+	case "import":
+		if flags.NArg() != 1 {
+			return errors.New(usage)
+		}
+		err = importSubscribers(repo, flags.Arg(0), stdout)
+	default:
+		return fmt.Errorf("subscribers: unknown action %q\n\n%s", action, usage)
+	}
+	if err != nil {
+		return fmt.Errorf("subscribers %s: %w", action, err)
+	}
+	return nil
+}
+
+func listSubscribers(repo *bot.SubscriptionRepository, stdout io.Writer) error {
+	subscriptions, err := repo.LoadSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "CHAT ID\tTHREAD\tFORMAT")
+	for _, subscription := range subscriptions {
+		fmt.Fprintf(writer, "%d\t%d\t%s\n", subscription.ChatID, subscription.ThreadID, subscription.Format)
+	}
+	fmt.Fprintf(writer, "\n%d active subscribers\n", len(subscriptions))
+	return writer.Flush()
+}
+
+// exportSubscribers writes active subscriptions as CSV that importSubscribers reads back.
+func exportSubscribers(repo *bot.SubscriptionRepository, output string, stdout io.Writer) error {
+	subscriptions, err := repo.LoadSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	destination := stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		destination = file
+	}
+
+	writer := csv.NewWriter(destination)
+	if err := writer.Write(subscriberHeader); err != nil {
+		return err
+	}
+	for _, subscription := range subscriptions {
+		record := []string{
+			strconv.FormatInt(subscription.ChatID, 10),
+			strconv.Itoa(subscription.ThreadID),
+			subscription.Format,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// importSubscribers subscribes every chat in the CSV; chats that already exist are reactivated and updated.
+func importSubscribers(repo *bot.SubscriptionRepository, path string, stdout io.Writer) error {
+	source := io.Reader(os.Stdin)
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		source = file
+	}
+
+	records, err := csv.NewReader(source).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) > 0 && records[0][0] == subscriberHeader[0] {
+		records = records[1:]
+	}
+
+	subscriptions := make([]bot.Subscription, 0, len(records))
+	for i, record := range records {
+		subscription, err := parseSubscriber(record)
+		if err != nil {
+			return fmt.Errorf("record %d: %w", i+1, err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	for _, subscription := range subscriptions {
+		if err := repo.Subscribe(subscription.ChatID, subscription.ThreadID); err != nil {
+			return err
+		}
+		if subscription.Format == "" {
+			continue
+		}
+		if err := repo.SetMenuFormat(subscription.ChatID, subscription.Format); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(stdout, "imported %d subscribers\n", len(subscriptions))
+	return nil
+}
+
+func parseSubscriber(record []string) (bot.Subscription, error) {
+	if len(record) != len(subscriberHeader) {
+		return bot.Subscription{}, fmt.Errorf("expected %d fields, got %d", len(subscriberHeader), len(record))
+	}
+
+	chatID, err := strconv.ParseInt(record[0], 10, 64)
+	if err != nil {
+		return bot.Subscription{}, fmt.Errorf("invalid chat_id %q", record[0])
+	}
+	threadID := 0
+	if record[1] != "" {
+		threadID, err = strconv.Atoi(record[1])
+		if err != nil {
+			return bot.Subscription{}, fmt.Errorf("invalid thread_id %q", record[1])
+		}
+	}
+
+	switch record[2] {
+	case "", "text", "image":
+	default:
+		return bot.Subscription{}, fmt.Errorf("invalid format %q, expected text or image", record[2])
+	}
+
+	return bot.Subscription{ChatID: chatID, ThreadID: threadID, Format: record[2]}, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSubscribersRoundTrip(t *testing.T) {
+	source := newTestDatabase(t)
+	if got := runCommand(t, "subscribers", "import", "--db", source, filepath.Join("testdata", "subscribers.csv")); got != "imported 3 subscribers\n" {
+		t.Fatalf("import output = %q", got)
+	}
+
+	exported := filepath.Join(t.TempDir(), "subscribers.csv")
+	runCommand(t, "subscribers", "export", "--db", source, "--output", exported)
+
+	target := newTestDatabase(t)
+	runCommand(t, "subscribers", "import", "--db", target, exported)
+
+	list := strings.Join(strings.Fields(runCommand(t, "subscribers", "list", "--db", target)), " ")
+	for _, want := range []string{"101 0 text", "-100200 7 image", "303 0 text", "3 active subscribers"} {
+		if !strings.Contains(list, want) {
+			t.Errorf("list missing %q: %s", want, list)
+		}
+	}
+}
+
+func TestSubscribersImportRejectsBadRecords(t *testing.T) {
+	path := newTestDatabase(t)
+
+	tests := map[string]string{
+		"chat id": "chat_id,thread_id,format\nabc,0,text\n",
+		"format":  "chat_id,thread_id,format\n101,0,pdf\n",
+		"fields":  "101,0\n",
+	}
+	for name, content := range tests {
+		file := filepath.Join(t.TempDir(), "subscribers.csv")
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		if err := run([]string{"subscribers", "import", "--db", path, file}, &bytes.Buffer{}); err == nil {
+			t.Errorf("%s: import accepted %q", name, content)
+		}
+	}
+
+	// Nothing is subscribed when any record is invalid.
+	if list := runCommand(t, "subscribers", "list", "--db", path); !strings.Contains(list, "0 active subscribers") {
+		t.Errorf("partial import:\n%s", list)
+	}
+}
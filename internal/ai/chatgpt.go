@@ -7,19 +7,41 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/retry"
+)
+
+const (
+	requestAttempts = 3
+	retryDelay      = time.Second
+	maxRetryDelay   = 8 * time.Second
 )
 
 type GptService struct {
 	apiKey string
 	url    string
 	client *http.Client
+	retry  retry.Policy
 }
 
-func NewGptService(apiKey string, url string) *GptService {
+func NewGptService(apiKey string, apiURL string) *GptService {
+	host := apiURL
+	if parsed, err := url.Parse(apiURL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+
 	return &GptService{
 		apiKey: apiKey,
-		url:    url,
+		url:    apiURL,
+		retry: retry.Policy{
+			Name:      "AI request",
+			Attempts:  requestAttempts,
+			BaseDelay: retryDelay,
+			MaxDelay:  maxRetryDelay,
+			Breaker:   retry.BreakerFor(host),
+		},
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 			Transport: &http.Transport{
@@ -41,6 +63,20 @@ func (gpt *GptService) SendRequest(ctx context.Context, messages []*Message) (an
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	var response string
+	err = retry.Do(ctx, gpt.retry, func(ctx context.Context) error {
+		var err error
+		response, err = gpt.sendAttempt(ctx, reqBodyJson)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (gpt *GptService) sendAttempt(ctx context.Context, reqBodyJson []byte) (string, error) {
 	req, err := http.NewRequestWithContext(
 		ctx,
 		"POST",
@@ -48,7 +84,7 @@ func (gpt *GptService) SendRequest(ctx context.Context, messages []*Message) (an
 		bytes.NewBuffer(reqBodyJson),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return "", retry.Mark(retry.Client, fmt.Errorf("failed to create request: %w", err))
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -56,31 +92,31 @@ func (gpt *GptService) SendRequest(ctx context.Context, messages []*Message) (an
 
 	res, err := gpt.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return "", retry.Mark(retry.Network, fmt.Errorf("failed to send request: %w", err))
 	}
 	defer res.Body.Close()
 
 	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
 		body, err := io.ReadAll(res.Body)
 		if err != nil {
-			return nil, fmt.Errorf("request failed with status %d and couldn't read response", res.StatusCode)
+			return "", retry.HTTPStatus(res.StatusCode, fmt.Errorf("request failed with status %d and couldn't read response", res.StatusCode))
 		}
-		return nil, fmt.Errorf("request failed with status %d: %s", res.StatusCode, string(body))
+		return "", retry.HTTPStatus(res.StatusCode, fmt.Errorf("request failed with status %d: %s", res.StatusCode, string(body)))
 	}
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return "", retry.Mark(retry.Network, fmt.Errorf("failed to read response body: %w", err))
 	}
 
 	var response Response
 	err = json.Unmarshal(body, &response)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return "", retry.Mark(retry.AI, fmt.Errorf("failed to unmarshal response: %w", err))
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("AI request failed: %v", response.Errors)
+		return "", retry.Mark(retry.AI, fmt.Errorf("AI request failed: %v", response.Errors))
 	}
 
 	return response.Result.Response, nil
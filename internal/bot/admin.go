@@ -0,0 +1,218 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+const adminEditUsage = "Использование:\n/admin_edit <peony|azilea> <номер> <название> | <описание>\n\nПустая часть оставляет прежнее значение, например:\n/admin_edit peony 2 | Новое описание"
+
+var adminCommands = []tgbotapi.BotCommand{
+	{Command: "admin_refresh", Description: "Перезагрузить меню столовой"},
+	{Command: "admin_stats", Description: "Статистика подписок и рассылок"},
+	{Command: "admin_broadcast", Description: "Отправить объявление подписчикам"},
+	{Command: "admin_edit", Description: "Исправить блюдо в сегодняшнем меню"},
+}
+
+// MenuAdmin is the part of the menu service that admin commands use to correct today's menu.
+type MenuAdmin interface {
+	RefreshMenuWithContext(ctx context.Context, cafeteria menu.Cafeteria) (*menu.Menu, error)
+	EditDish(ctx context.Context, cafeteria menu.Cafeteria, position int, name, description string) (*menu.Menu, error)
+}
+
+type adminState struct {
+	chatIDs []int64
+	menus   MenuAdmin
+
+	mu           sync.Mutex
+	lastDispatch *dispatchReport
+}
+
+type dispatchReport struct {
+	At     time.Time
+	Result broadcastResult
+}
+
+// EnableAdminCommands allows the listed chats to run the /admin_* commands.
+func (b *Bot) EnableAdminCommands(chatIDs []int64, menus MenuAdmin) {
+	b.admin = &adminState{
+		chatIDs: slices.Clone(chatIDs),
+		menus:   menus,
+	}
+}
+
+func (b *Bot) isAdminChat(chatID int64) bool {
+	return b.admin != nil && slices.Contains(b.admin.chatIDs, chatID)
+}
+
+// registerAdminCommands shows the admin commands only in the admin chats' command menus.
+func (b *Bot) registerAdminCommands() {
+	if b.admin == nil {
+		return
+	}
+
+	commands := append(slices.Clone(botCommands), adminCommands...)
+	for _, chatID := range b.admin.chatIDs {
+		config := tgbotapi.NewSetMyCommandsWithScope(tgbotapi.NewBotCommandScopeChat(chatID), commands...)
+		if err := b.messenger.Request(config); err != nil {
+			slog.Warn("Failed to register admin commands", "chat_id", chatID, "error", err)
+		}
+	}
+}
+
+func (b *Bot) recordDispatch(result broadcastResult) {
+	if b.admin == nil {
+		return
+	}
+
+	b.admin.mu.Lock()
+	defer b.admin.mu.Unlock()
+
+	b.admin.lastDispatch = &dispatchReport{At: b.clock.Now(), Result: result}
+}
+
+// handleAdminCommand runs /admin_* commands; in other chats they are treated as unknown commands.
+func (b *Bot) handleAdminCommand(chatID int64, threadID int, command, args string) (bool, error) {
+	if !strings.HasPrefix(command, "admin_") || !b.isAdminChat(chatID) {
+		return false, nil
+	}
+
+	slog.Info("Admin command received", "chat_id", chatID, "command", command)
+
+	switch command {
+	case "admin_refresh":
+		return true, b.adminRefresh(chatID, threadID, args)
+	case "admin_stats":
+		return true, b.adminStats(chatID, threadID)
+	case "admin_broadcast":
+		return true, b.adminBroadcast(chatID, threadID, args)
+	case "admin_edit":
+		return true, b.adminEdit(chatID, threadID, args)
+	default:
+		return false, nil
+	}
+}
+
+func (b *Bot) adminRefresh(chatID int64, threadID int, args string) error {
+	cafeterias := []menu.Cafeteria{menu.PEONY, menu.AZILEA}
+	if strings.TrimSpace(args) != "" {
+		cafeteria := parseCafeteria(args)
+		if cafeteria == "" {
+			return b.SendMessage(chatID, threadID, "Использование: /admin_refresh [peony|azilea]")
+		}
+		cafeterias = []menu.Cafeteria{cafeteria}
+	}
+
+	var report strings.Builder
+	report.WriteString("🔄 Обновление меню:\n")
+	for _, cafeteria := range cafeterias {
+		refreshed, err := b.admin.menus.RefreshMenuWithContext(b.context(), cafeteria)
+		if err != nil {
+			slog.Error("Admin refresh failed", "cafeteria", string(cafeteria), "error", err)
+			report.WriteString(fmt.Sprintf("%s: ошибка — %v\n", cafeteriaTitle(cafeteria), err))
+			continue
+		}
+		report.WriteString(fmt.Sprintf("%s: %d блюд\n", cafeteriaTitle(cafeteria), len(refreshed.Items)))
+	}
+
+	return b.SendMessage(chatID, threadID, report.String())
+}
+
+func (b *Bot) adminStats(chatID int64, threadID int) error {
+	stats, err := b.repo.LoadStats(b.clock.Now())
+	if err != nil {
+		slog.Error("Failed to load bot stats", "error", err)
+		return b.SendMessage(chatID, threadID, "Не удалось загрузить статистику.")
+	}
+
+	var message strings.Builder
+	message.WriteString("📊 Статистика\n\n")
+	message.WriteString(fmt.Sprintf("Активные подписки: %d (группы: %d)\n", stats.ActiveSubscribers, stats.GroupSubscribers))
+	message.WriteString(fmt.Sprintf("Отписались: %d\n", stats.InactiveSubscribers))
+	message.WriteString(fmt.Sprintf("Отслеживаемые блюда: %d\n", stats.Watches))
+	message.WriteString(fmt.Sprintf("Доставлено сегодня: %d\n", stats.DeliveredToday))
+
+	b.admin.mu.Lock()
+	lastDispatch := b.admin.lastDispatch
+	b.admin.mu.Unlock()
+
+	if lastDispatch != nil {
+		message.WriteString(fmt.Sprintf("\nПоследняя рассылка %s: доставлено %d, ошибок %d",
+			lastDispatch.At.Format("02.01 15:04"), lastDispatch.Result.Delivered, lastDispatch.Result.Failed))
+	} else {
+		message.WriteString("\nРассылок с момента запуска не было")
+	}
+
+	return b.SendMessage(chatID, threadID, message.String())
+}
+
+func (b *Bot) adminBroadcast(chatID int64, threadID int, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return b.SendMessage(chatID, threadID, "Использование: /admin_broadcast <текст объявления>")
+	}
+
+	subscribers, err := b.repo.LoadSubscriptions()
+	if err != nil {
+		slog.Error("Failed to load subscribers for announcement", "error", err)
+		return b.SendMessage(chatID, threadID, "Не удалось загрузить подписчиков.")
+	}
+
+	chatIDs, targets := subscriptionTargets(subscribers)
+
+	announcement := "📢 " + text
+	result := b.broadcast(b.context(), "announcement", chatIDs, func(subscriberID int64) error {
+		_, err := b.send(tgbotapi.NewMessage(subscriberID, announcement), targets[subscriberID].ThreadID)
+		return err
+	})
+
+	return b.SendMessage(chatID, threadID, fmt.Sprintf("📢 Объявление отправлено: доставлено %d, ошибок %d", result.Delivered, result.Failed))
+}
+
+func (b *Bot) adminEdit(chatID int64, threadID int, args string) error {
+	cafeteria, position, name, description, ok := parseAdminEdit(args)
+	if !ok {
+		return b.SendMessage(chatID, threadID, adminEditUsage)
+	}
+
+	edited, err := b.admin.menus.EditDish(b.context(), cafeteria, position, name, description)
+	if err != nil {
+		return b.SendMessage(chatID, threadID, fmt.Sprintf("Не удалось исправить блюдо: %v", err))
+	}
+
+	item := edited.Items[position-1]
+	return b.SendMessage(chatID, threadID, fmt.Sprintf("✏️ %s, блюдо %d:\n%s - %s", cafeteriaTitle(cafeteria), position, item.Name, item.Description))
+}
+
+// parseAdminEdit splits "peony 2 Новое название | Новое описание".
+func parseAdminEdit(args string) (cafeteria menu.Cafeteria, position int, name, description string, ok bool) {
+	fields := strings.SplitN(strings.TrimSpace(args), " ", 3)
+	if len(fields) < 3 {
+		return "", 0, "", "", false
+	}
+
+	cafeteria = parseCafeteria(fields[0])
+	position, err := strconv.Atoi(fields[1])
+	if cafeteria == "" || err != nil {
+		return "", 0, "", "", false
+	}
+
+	name, description, _ = strings.Cut(fields[2], "|")
+	name = strings.TrimSpace(name)
+	description = strings.TrimSpace(description)
+	if name == "" && description == "" {
+		return "", 0, "", "", false
+	}
+
+	return cafeteria, position, name, description, true
+}
@@ -0,0 +1,171 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/bot/bottest"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+const adminChatID = 777
+
+type editCall struct {
+	cafeteria   menu.Cafeteria
+	position    int
+	name        string
+	description string
+}
+
+func (s *stubMenuService) RefreshMenuWithContext(ctx context.Context, cafeteria menu.Cafeteria) (*menu.Menu, error) {
+	s.refreshed = append(s.refreshed, cafeteria)
+	if cafeteria == menu.PEONY {
+		return s.peony, nil
+	}
+	return s.azilea, nil
+}
+
+func (s *stubMenuService) EditDish(ctx context.Context, cafeteria menu.Cafeteria, position int, name, description string) (*menu.Menu, error) {
+	s.edits = append(s.edits, editCall{cafeteria, position, name, description})
+
+	edited := s.peony
+	if cafeteria == menu.AZILEA {
+		edited = s.azilea
+	}
+	if position < 1 || position > len(edited.Items) {
+		return nil, fmt.Errorf("dish %d is out of range", position)
+	}
+	if name != "" {
+		edited.Items[position-1].Name = name
+	}
+	if description != "" {
+		edited.Items[position-1].Description = description
+	}
+	return edited, nil
+}
+
+func newAdminTestBot(t *testing.T) (*Bot, *bottest.FakeMessenger, *stubMenuService) {
+	t.Helper()
+
+	b, messenger, _ := newTestBot(t)
+	menus := b.menuService.(*stubMenuService)
+	b.EnableAdminCommands([]int64{adminChatID}, menus)
+	return b, messenger, menus
+}
+
+func TestAdminCommandsIgnoredOutsideAdminChats(t *testing.T) {
+	b, messenger, _ := newAdminTestBot(t)
+
+	if err := b.handleCommand(commandUpdate(100, "/admin_stats")); err != nil {
+		t.Fatalf("handleCommand() error: %v", err)
+	}
+
+	if got := lastMessage(t, messenger, 100).Text; strings.Contains(got, "Статистика") {
+		t.Errorf("non-admin chat received admin stats: %q", got)
+	}
+}
+
+func TestAdminStats(t *testing.T) {
+	b, messenger, _ := newAdminTestBot(t)
+	mustSubscribe(t, b.repo, 1)
+	mustSubscribe(t, b.repo, -1002)
+	if err := b.dispatchDailyMenu(); err != nil {
+		t.Fatalf("dispatchDailyMenu() error: %v", err)
+	}
+	messenger.Reset()
+
+	if err := b.handleCommand(commandUpdate(adminChatID, "/admin_stats")); err != nil {
+		t.Fatalf("handleCommand() error: %v", err)
+	}
+
+	got := lastMessage(t, messenger, adminChatID).Text
+	for _, want := range []string{"Активные подписки: 2 (группы: 1)", "Доставлено сегодня: 2", "доставлено 2, ошибок 0"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("stats = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestAdminBroadcast(t *testing.T) {
+	b, messenger, _ := newAdminTestBot(t)
+	mustSubscribe(t, b.repo, 1)
+	mustSubscribe(t, b.repo, 2)
+
+	if err := b.handleCommand(commandUpdate(adminChatID, "/admin_broadcast Столовая закрыта в пятницу")); err != nil {
+		t.Fatalf("handleCommand() error: %v", err)
+	}
+
+	for _, chatID := range []int64{1, 2} {
+		if got := lastMessage(t, messenger, chatID).Text; got != "📢 Столовая закрыта в пятницу" {
+			t.Errorf("chat %d got %q", chatID, got)
+		}
+	}
+	if got := lastMessage(t, messenger, adminChatID).Text; !strings.Contains(got, "доставлено 2, ошибок 0") {
+		t.Errorf("admin report = %q", got)
+	}
+}
+
+func TestAdminRefresh(t *testing.T) {
+	b, _, menus := newAdminTestBot(t)
+
+	if err := b.handleCommand(commandUpdate(adminChatID, "/admin_refresh azilea")); err != nil {
+		t.Fatalf("handleCommand() error: %v", err)
+	}
+
+	if len(menus.refreshed) != 1 || menus.refreshed[0] != menu.AZILEA {
+		t.Errorf("refreshed = %v, want [azilea]", menus.refreshed)
+	}
+}
+
+func TestAdminEdit(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     string
+		want     *editCall
+		wantText string
+	}{
+		{
+			name:     "name and description",
+			args:     "peony 2 Тонкацу | Свиная отбивная",
+			want:     &editCall{menu.PEONY, 2, "Тонкацу", "Свиная отбивная"},
+			wantText: "Тонкацу - Свиная отбивная",
+		},
+		{
+			name:     "description only",
+			args:     "azilea 1 | Рис с овощами",
+			want:     &editCall{menu.AZILEA, 1, "", "Рис с овощами"},
+			wantText: "비빔밥 - Рис с овощами",
+		},
+		{name: "missing position", args: "peony Тонкацу", wantText: "Использование"},
+		{name: "unknown cafeteria", args: "cafe 1 Суп", wantText: "Использование"},
+		{
+			name:     "out of range",
+			args:     "peony 9 Суп",
+			want:     &editCall{menu.PEONY, 9, "Суп", ""},
+			wantText: "Не удалось исправить блюдо",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, messenger, menus := newAdminTestBot(t)
+
+			if err := b.handleCommand(commandUpdate(adminChatID, "/admin_edit "+tt.args)); err != nil {
+				t.Fatalf("handleCommand() error: %v", err)
+			}
+
+			switch {
+			case tt.want == nil && len(menus.edits) != 0:
+				t.Errorf("edits = %+v, want none", menus.edits)
+			case tt.want != nil && (len(menus.edits) != 1 || menus.edits[0] != *tt.want):
+				t.Errorf("edits = %+v, want %+v", menus.edits, *tt.want)
+			}
+
+			if got := lastMessage(t, messenger, adminChatID).Text; !strings.Contains(got, tt.wantText) {
+				t.Errorf("reply = %q, want it to contain %q", got, tt.wantText)
+			}
+		})
+	}
+}
@@ -2,8 +2,12 @@ package bot
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"log/slog"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -11,19 +15,38 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menucard"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/telegram"
 )
 
 type Bot struct {
-	bot         *tgbotapi.BotAPI
+	messenger   Messenger
 	repo        *SubscriptionRepository
 	wg          sync.WaitGroup
 	menuService MenuService
+	clock       menu.Clock
+	webhook     *webhookConfig
+	admin       *adminState
+	cards       *menucard.Renderer
+	ratings     RatingService
+	recommender Recommender
 	ctx         context.Context
 	cancel      context.CancelFunc
+	seenUpdates updateLog
+
+	// mu guards stopping, which Stop sets before waiting on wg so that no background work is added meanwhile.
+	mu       sync.Mutex
+	stopping bool
 }
 
+// errStopping rejects work that arrives while the bot shuts down.
+var errStopping = errors.New("bot is stopping")
+
 type MenuService interface {
 	GetMenus() (*menu.Menu, *menu.Menu, error)
+	GetMenusForDate(ctx context.Context, date time.Time) (*menu.Menu, *menu.Menu, error)
+	GetMenuForDate(ctx context.Context, cafeteria menu.Cafeteria, date time.Time) (*menu.Menu, error)
+	GetStoredMenuForDate(cafeteria menu.Cafeteria, date time.Time) (*menu.Menu, error)
 }
 
 func NewBot(token string, repo *SubscriptionRepository, menuService MenuService) (*Bot, error) {
@@ -32,18 +55,26 @@ func NewBot(token string, repo *SubscriptionRepository, menuService MenuService)
 		return nil, err
 	}
 
+	return NewBotWithAPI(bot, repo, menuService), nil
+}
+
+// NewBotWithAPI wraps an already configured Telegram client, e.g. one pointed at a custom API endpoint.
+func NewBotWithAPI(api *tgbotapi.BotAPI, repo *SubscriptionRepository, menuService MenuService) *Bot {
+	return NewBotWithMessenger(NewTelegramMessenger(api), repo, menuService)
+}
+
+func NewBotWithMessenger(messenger Messenger, repo *SubscriptionRepository, menuService MenuService) *Bot {
 	return &Bot{
-		bot:         bot,
+		messenger:   messenger,
 		repo:        repo,
 		menuService: menuService,
-	}, nil
+		clock:       menu.NewKSTClock(),
+	}
 }
 
-func (b *Bot) SendMessage(chatId int, text string) error {
-	message := tgbotapi.NewMessage(int64(chatId), text)
-
-	_, err := b.bot.Send(message)
-
+// SendMessage sends plain text to a chat; a non-zero threadID keeps a reply in the forum topic it answers.
+func (b *Bot) SendMessage(chatID int64, threadID int, text string) error {
+	_, err := b.send(tgbotapi.NewMessage(chatID, text), threadID)
 	return err
 }
 
@@ -109,7 +140,7 @@ func (b *Bot) runDailyScheduler(ctx context.Context) {
 }
 
 func (b *Bot) dispatchDailyMenu() error {
-	subscribers, err := b.loadSubscribers()
+	subscribers, err := b.repo.LoadSubscriptions()
 	if err != nil {
 		return fmt.Errorf("load subscribers: %w", err)
 	}
@@ -119,32 +150,271 @@ func (b *Bot) dispatchDailyMenu() error {
 		return nil
 	}
 
-	message, err := b.buildMenuMessage()
+	message, keyboard, err := b.buildMenuMessage()
 	if err != nil {
 		return fmt.Errorf("build menu message: %w", err)
 	}
 
-	return b.sendDailyMenu(subscribers, message)
+	return b.sendDailyMenu(subscribers, message, keyboard, b.personalNotes(subscribers))
 }
 
-func (b *Bot) sendDailyMenu(subscribers []int64, message string) error {
-	var wg sync.WaitGroup
+// sendDailyMenu delivers the menu to every subscriber, wrapping it in the chat's personal notes.
+func (b *Bot) sendDailyMenu(subscribers []Subscription, message string, keyboard tgbotapi.InlineKeyboardMarkup, notes map[int64]personalNote) error {
+	menuDate := b.clock.Now()
 
-	for _, chatID := range subscribers {
-		wg.Add(1)
-		go func(chatID int64) {
-			defer wg.Done()
-			if err := b.sendMenuWithButtons(chatID, message); err != nil {
-				slog.Error("Failed to send daily menu", "chat_id", chatID, "error", err)
-			}
-		}(chatID)
+	chatIDs, targets := subscriptionTargets(subscribers)
+	card := b.dailyMenuCard(subscribers, menuDate)
+
+	result := b.broadcast(b.ctx, "daily_menu", chatIDs, func(chatID int64) error {
+		target := targets[chatID]
+		note := notes[chatID]
+
+		var (
+			sent   []tgbotapi.Message
+			format = menuFormatText
+			err    error
+		)
+		if target.Format == menuFormatImage && card != nil {
+			format = menuFormatImage
+			var photo tgbotapi.Message
+			photo, err = b.sendMenuCard(chatID, target.ThreadID, card, note.wrap(menuCardCaption(todayLabel)))
+			sent = []tgbotapi.Message{photo}
+		} else {
+			sent, err = b.sendMenuToThread(chatID, target.ThreadID, note.wrap(message), keyboard)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := b.repo.SaveSentMessage(chatID, messageIDs(sent), menuDate, format); err != nil {
+			slog.Warn("Failed to remember sent menu message", "chat_id", chatID, "error", err)
+		}
+		return nil
+	})
+	b.recordDispatch(result)
+
+	return nil
+}
+
+// dailyMenuCard renders the card once per dispatch when some subscriber wants images.
+// Without a card those subscribers get the text menu.
+func (b *Bot) dailyMenuCard(subscribers []Subscription, menuDate time.Time) []byte {
+	wantsCard := slices.ContainsFunc(subscribers, func(s Subscription) bool {
+		return s.Format == menuFormatImage
+	})
+	if !wantsCard || b.cards == nil {
+		return nil
+	}
+
+	card, err := b.renderMenuCard(menuDate)
+	if err != nil {
+		slog.Error("Failed to render menu card, falling back to text", "error", err)
+		return nil
+	}
+	return card
+}
+
+// menuChange describes a refresh that changed one cafeteria's dishes.
+type menuChange struct {
+	cafeteria menu.Cafeteria
+	// published is set when the cafeteria had no menu before.
+	published bool
+	summary   string
+}
+
+func newMenuChange(cafeteria menu.Cafeteria, previous, current *menu.Menu) menuChange {
+	if previous == nil || len(previous.Items) <= 1 {
+		return menuChange{cafeteria: cafeteria, published: true}
 	}
 
-	wg.Wait()
+	added, removed := menu.DiffDishes(previous.Items, current.Items)
+	return menuChange{cafeteria: cafeteria, summary: menu.MenuRevision{Added: added, Removed: removed}.Summary()}
+}
+
+// marker heads an edited menu with what changed and when.
+func (c menuChange) marker(at time.Time) string {
+	switch {
+	case c.published:
+		return fmt.Sprintf("🔄 <i>Меню %s опубликовано в %s</i>", cafeteriaTitle(c.cafeteria), at.Format("15:04"))
+	case c.summary == "":
+		return fmt.Sprintf("🔄 <i>Меню %s обновлено в %s</i>", cafeteriaTitle(c.cafeteria), at.Format("15:04"))
+	}
+	return fmt.Sprintf("🔄 <i>Меню %s обновлено в %s: %s</i>", cafeteriaTitle(c.cafeteria), at.Format("15:04"), html.EscapeString(c.summary))
+}
+
+// HandleMenuChange edits today's already delivered menu messages after a refresh changed a cafeteria's dishes.
+// A refresh that lost the menu leaves the delivered messages alone: the dishes already sent help more than a placeholder.
+// The edits are rate limited and can take minutes, so they run in the background instead of holding up
+// the refresh that noticed the change; they use the bot's context because the refresh's may end first.
+func (b *Bot) HandleMenuChange(_ context.Context, cafeteria menu.Cafeteria, previous, current *menu.Menu) {
+	if current == nil || len(current.Items) <= 1 {
+		slog.Info("Menu is no longer available, keeping delivered messages", "cafeteria", string(cafeteria))
+		return
+	}
+
+	change := newMenuChange(cafeteria, previous, current)
+	if err := b.goBackground(func() { b.updateDeliveredMenus(b.context(), change) }); err != nil {
+		slog.Warn("Skipped editing delivered menus", "cafeteria", string(cafeteria), "error", err)
+	}
+}
+
+// goBackground runs task on the bot's wait group unless Stop has begun.
+func (b *Bot) goBackground(task func()) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 
+	if b.stopping {
+		return errStopping
+	}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		task()
+	}()
 	return nil
 }
 
+func (b *Bot) updateDeliveredMenus(ctx context.Context, change menuChange) {
+	menuDate := b.clock.Now()
+
+	sentMessages, err := b.repo.LoadSentMessages(menuDate)
+	if err != nil {
+		slog.Error("Failed to load sent messages for menu update", "error", err)
+		return
+	}
+
+	if len(sentMessages) == 0 {
+		slog.Debug("No delivered messages to update", "cafeteria", string(change.cafeteria))
+		return
+	}
+
+	subscribers, err := b.repo.LoadSubscriptions()
+	if err != nil {
+		slog.Error("Failed to load subscribers for menu update", "error", err)
+		return
+	}
+	_, targets := subscriptionTargets(subscribers)
+	notes := b.personalNotes(subscribers)
+
+	message, keyboard, err := b.buildMenuMessage()
+	if err != nil {
+		slog.Error("Failed to build updated menu message", "error", err)
+		return
+	}
+	marker := change.marker(menuDate)
+
+	messages := make(map[int64]SentMessage, len(sentMessages))
+	chatIDs := make([]int64, 0, len(sentMessages))
+	for _, sent := range sentMessages {
+		messages[sent.ChatID] = sent
+		chatIDs = append(chatIDs, sent.ChatID)
+	}
+
+	var card []byte
+	if b.cards != nil && slices.ContainsFunc(sentMessages, func(sent SentMessage) bool { return sent.Format == menuFormatImage }) {
+		if card, err = b.renderMenuCard(menuDate); err != nil {
+			slog.Error("Failed to render updated menu card", "error", err)
+		}
+	}
+
+	slog.Info("Updating delivered menu messages",
+		"cafeteria", string(change.cafeteria),
+		"message_count", len(chatIDs))
+
+	b.broadcast(ctx, "menu_update", chatIDs, func(chatID int64) error {
+		sent := messages[chatID]
+		note := notes[chatID]
+		if sent.Format == menuFormatImage {
+			if card == nil {
+				return fmt.Errorf("no menu card to update message %d", sent.MessageIDs[0])
+			}
+			if err := b.editMenuCard(sent, card, marker+"\n"+note.wrap(menuCardCaption(todayLabel))); err != nil {
+				return err
+			}
+			return b.repo.MarkMessageUpdated(chatID, sent.MessageIDs, menuDate)
+		}
+
+		parts := splitMessage(marker+"\n\n"+note.wrap(message), telegramMessageLimit)
+		messageIDs, err := b.editHTML(chatID, targets[chatID].ThreadID, sent.MessageIDs, parts, keyboard)
+		if len(messageIDs) > 0 {
+			if err := b.repo.MarkMessageUpdated(chatID, messageIDs, menuDate); err != nil {
+				slog.Warn("Failed to remember updated menu message", "chat_id", chatID, "error", err)
+			}
+		}
+		return err
+	})
+}
+
+// editHTML replaces the parts of a delivered HTML message with new parts, keeping the keyboard on the last one.
+// Extra parts are sent as new messages and parts no longer needed are deleted. It returns the messages the
+// text now takes, including the edited ones before a failure.
+func (b *Bot) editHTML(chatID int64, threadID int, messageIDs []int, parts []string, keyboard tgbotapi.InlineKeyboardMarkup) ([]int, error) {
+	updated := make([]int, 0, len(parts))
+	for i, part := range parts {
+		last := i == len(parts)-1
+
+		if i < len(messageIDs) {
+			// An edit without a keyboard removes the one a former last part carried.
+			edit := tgbotapi.NewEditMessageText(chatID, messageIDs[i], part)
+			edit.ParseMode = tgbotapi.ModeHTML
+			if last {
+				edit.ReplyMarkup = &keyboard
+			}
+			if err := b.messenger.Edit(edit); err != nil {
+				return updated, err
+			}
+			updated = append(updated, messageIDs[i])
+			continue
+		}
+
+		msg := tgbotapi.NewMessage(chatID, part)
+		msg.ParseMode = tgbotapi.ModeHTML
+		if last {
+			msg.ReplyMarkup = keyboard
+		}
+		sent, err := b.send(msg, threadID)
+		if err != nil {
+			return updated, err
+		}
+		updated = append(updated, sent.MessageID)
+	}
+
+	for _, messageID := range messageIDs[min(len(parts), len(messageIDs)):] {
+		if err := b.messenger.Request(tgbotapi.NewDeleteMessage(chatID, messageID)); err != nil {
+			slog.Warn("Failed to delete outdated menu part", "chat_id", chatID, "message_id", messageID, "error", err)
+		}
+	}
+	return updated, nil
+}
+
+func (b *Bot) editMenuCard(sent SentMessage, card []byte, caption string) error {
+	media := tgbotapi.NewInputMediaPhoto(menuCardFile(card))
+	media.Caption = caption
+	media.ParseMode = tgbotapi.ModeHTML
+
+	keyboard := menuKeyboard()
+	edit := tgbotapi.EditMessageMediaConfig{
+		BaseEdit: tgbotapi.BaseEdit{
+			ChatID:      sent.ChatID,
+			MessageID:   sent.MessageIDs[0],
+			ReplyMarkup: &keyboard,
+		},
+		Media: media,
+	}
+
+	_, err := b.messenger.Send(edit)
+	return err
+}
+
+// messageIDs lists the IDs of sent messages in order.
+func messageIDs(sent []tgbotapi.Message) []int {
+	ids := make([]int, len(sent))
+	for i, message := range sent {
+		ids[i] = message.MessageID
+	}
+	return ids
+}
+
 func (b *Bot) getNextRunTime(kst *time.Location) time.Time {
 	now := time.Now().In(kst)
 
@@ -159,8 +429,8 @@ func (b *Bot) getNextRunTime(kst *time.Location) time.Time {
 	return today10AM
 }
 
-func (b *Bot) subscribeChat(chatID int64) error {
-	return b.repo.Subscribe(chatID)
+func (b *Bot) subscribeChat(chatID int64, threadID int) error {
+	return b.repo.Subscribe(chatID, threadID)
 }
 
 func (b *Bot) unsubscribeChat(chatID int64) error {
@@ -171,7 +441,7 @@ func (b *Bot) getSubscriptionStatus(chatID int64) (bool, error) {
 	return b.repo.GetStatus(chatID)
 }
 
-func (b *Bot) sendStartMessage(chatID int64) error {
+func (b *Bot) sendStartMessage(chatID int64, threadID int) error {
 	msg := tgbotapi.NewMessage(chatID, "🍽️ Добро пожаловать в бот ежедневного меню нашего универа!\n\nПолучайте обновления меню каждый день в 10:00.\nНажмите кнопку ниже, чтобы подписаться:")
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
@@ -181,12 +451,16 @@ func (b *Bot) sendStartMessage(chatID int64) error {
 	)
 
 	msg.ReplyMarkup = keyboard
-	_, err := b.bot.Send(msg)
+	_, err := b.send(msg, threadID)
 	return err
 }
 
-func (b *Bot) sendSubscriptionConfirmation(chatID int64) error {
-	msg := tgbotapi.NewMessage(chatID, "✅ Вы подписаны на ежедневные обновления меню в 10:00!\n\nВы будете получать меню каждый день в 10:00.")
+func (b *Bot) sendSubscriptionConfirmation(chatID int64, threadID int) error {
+	text := "✅ Вы подписаны на ежедневные обновления меню в 10:00!\n\nВы будете получать меню каждый день в 10:00."
+	if threadID != 0 {
+		text += "\nМеню будет приходить в эту тему."
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
@@ -195,33 +469,40 @@ func (b *Bot) sendSubscriptionConfirmation(chatID int64) error {
 	)
 
 	msg.ReplyMarkup = keyboard
-	_, err := b.bot.Send(msg)
+	_, err := b.send(msg, threadID)
 	return err
 }
 
-func (b *Bot) sendMenuWithButtons(chatID int64, menuText string) error {
-	msg := tgbotapi.NewMessage(chatID, menuText)
+// send posts into a forum topic when threadID is set and into the main chat otherwise.
+func (b *Bot) send(msg tgbotapi.MessageConfig, threadID int) (tgbotapi.Message, error) {
+	if threadID != 0 {
+		return b.messenger.SendToThread(msg, threadID)
+	}
+	return b.messenger.Send(msg)
+}
 
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+func menuKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("❌ Отписаться", "unsubscribe_confirm"),
 		),
 	)
+}
 
-	msg.ReplyMarkup = keyboard
-	_, err := b.bot.Send(msg)
-	return err
+func (b *Bot) sendMenuToThread(chatID int64, threadID int, menuText string, keyboard tgbotapi.InlineKeyboardMarkup) ([]tgbotapi.Message, error) {
+	return b.sendHTML(chatID, threadID, menuText, keyboard)
 }
 
-func (b *Bot) sendLatestMenu(chatID int64) error {
-	message, err := b.buildMenuMessage()
+func (b *Bot) sendLatestMenu(chatID int64, threadID int) error {
+	message, keyboard, err := b.buildMenuMessage()
 	if err != nil {
 		return fmt.Errorf("build menu message: %w", err)
 	}
-	return b.sendMenuWithButtons(chatID, message)
+	_, err = b.sendMenuToThread(chatID, threadID, message, keyboard)
+	return err
 }
 
-func (b *Bot) sendUnsubscribeConfirmation(chatID int64) error {
+func (b *Bot) sendUnsubscribeConfirmation(chatID int64, threadID int) error {
 	msg := tgbotapi.NewMessage(chatID, "Вы уверены, что хотите отписаться от ежедневных обновлений меню?")
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
@@ -232,27 +513,31 @@ func (b *Bot) sendUnsubscribeConfirmation(chatID int64) error {
 	)
 
 	msg.ReplyMarkup = keyboard
-	_, err := b.bot.Send(msg)
+	_, err := b.send(msg, threadID)
 	return err
 }
 
-func (b *Bot) handleCallbackQuery(callback *tgbotapi.CallbackQuery) error {
+func (b *Bot) handleCallbackQuery(callback *tgbotapi.CallbackQuery, threadID int) error {
 	chatID := callback.Message.Chat.ID
 	action := callback.Data
 
+	if err := b.authorizeCallback(callback); err != nil {
+		return err
+	}
+
 	switch action {
 	case "subscribe":
-		if err := b.subscribeChat(chatID); err != nil {
-			return b.SendMessage(int(chatID), "Failed to subscribe. Please try again later.")
+		if err := b.subscribeChat(chatID, threadID); err != nil {
+			return b.SendMessage(chatID, threadID, "Failed to subscribe. Please try again later.")
 		}
-		return b.sendSubscriptionConfirmation(chatID)
+		return b.sendSubscriptionConfirmation(chatID, threadID)
 
 	case "unsubscribe_confirm":
-		return b.sendUnsubscribeConfirmation(chatID)
+		return b.sendUnsubscribeConfirmation(chatID, threadID)
 
 	case "unsubscribe_yes":
 		if err := b.unsubscribeChat(chatID); err != nil {
-			return b.SendMessage(int(chatID), "Не удалось отписаться. Попробуйте позже.")
+			return b.SendMessage(chatID, threadID, "Не удалось отписаться. Попробуйте позже.")
 		}
 		msg := tgbotapi.NewMessage(chatID, "❌ Вы отписались от ежедневных обновлений меню.\n\nИспользуйте /start чтобы подписаться снова.")
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
@@ -261,9 +546,15 @@ func (b *Bot) handleCallbackQuery(callback *tgbotapi.CallbackQuery) error {
 			),
 		)
 		msg.ReplyMarkup = keyboard
-		_, err := b.bot.Send(msg)
+		_, err := b.send(msg, threadID)
 		return err
 
+	case "format_text":
+		return b.setMenuFormat(chatID, threadID, menuFormatText)
+
+	case "format_image":
+		return b.setMenuFormat(chatID, threadID, menuFormatImage)
+
 	case "unsubscribe_cancel":
 		msg := tgbotapi.NewMessage(chatID, "❌ Отписка отменена.\nВы продолжите получать ежедневные обновления меню.")
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
@@ -272,48 +563,79 @@ func (b *Bot) handleCallbackQuery(callback *tgbotapi.CallbackQuery) error {
 			),
 		)
 		msg.ReplyMarkup = keyboard
-		_, err := b.bot.Send(msg)
+		_, err := b.send(msg, threadID)
 		return err
 
 	default:
-		return b.SendMessage(int(chatID), "Неизвестное действие. Попробуйте еще раз.")
+		return b.SendMessage(chatID, threadID, "Неизвестное действие. Попробуйте еще раз.")
 	}
 }
 
-func (b *Bot) handleCommand(update tgbotapi.Update) error {
-	chatID := update.Message.Chat.ID
+func (b *Bot) handleCommand(update telegram.Update) error {
+	chatID, threadID := update.Message.Chat.ID, update.MessageThreadID
 	command := update.Message.Command()
 
+	if err := b.authorizeCommand(update.Message); err != nil {
+		if errors.Is(err, errNotChatAdmin) {
+			return b.SendMessage(chatID, threadID, notChatAdminMessage)
+		}
+		return err
+	}
+
 	switch command {
 	case "start":
-		return b.sendStartMessage(chatID)
+		return b.sendStartMessage(chatID, threadID)
 
 	case "subscribe":
-		if err := b.subscribeChat(chatID); err != nil {
-			return b.SendMessage(int(chatID), "Не удалось подписаться. Попробуйте позже.")
+		if err := b.subscribeChat(chatID, threadID); err != nil {
+			return b.SendMessage(chatID, threadID, "Не удалось подписаться. Попробуйте позже.")
 		}
-		return b.sendSubscriptionConfirmation(chatID)
+		return b.sendSubscriptionConfirmation(chatID, threadID)
 
 	case "unsubscribe":
 		if err := b.unsubscribeChat(chatID); err != nil {
-			return b.SendMessage(int(chatID), "Не удалось отписаться. Попробуйте позже.")
+			return b.SendMessage(chatID, threadID, "Не удалось отписаться. Попробуйте позже.")
 		}
-		return b.SendMessage(int(chatID), "❌ Вы отписались от ежедневных обновлений меню.")
+		return b.SendMessage(chatID, threadID, "❌ Вы отписались от ежедневных обновлений меню.")
 
 	case "status":
 		isActive, err := b.getSubscriptionStatus(chatID)
 		if err != nil {
-			return b.SendMessage(int(chatID), "Не удалось проверить статус подписки. Попробуйте позже.")
+			return b.SendMessage(chatID, threadID, "Не удалось проверить статус подписки. Попробуйте позже.")
 		}
 
 		status := "❌ Не подписан"
 		if isActive {
 			status = "✅ Подписан"
 		}
-		return b.SendMessage(int(chatID), fmt.Sprintf("Статус подписки: %s\nЕжедневные обновления меню в 10:00", status))
+		return b.SendMessage(chatID, threadID, fmt.Sprintf("Статус подписки: %s\nЕжедневные обновления меню в 10:00", status))
+
+	case "watch":
+		return b.addWatch(chatID, threadID, update.Message.CommandArguments())
+
+	case "unwatch":
+		return b.removeWatch(chatID, threadID, update.Message.CommandArguments())
+
+	case "watches":
+		return b.listWatches(chatID, threadID)
+
+	case "format":
+		return b.handleFormatCommand(chatID, threadID, update.Message.CommandArguments())
+
+	case "top":
+		return b.sendTopDishes(chatID, threadID)
+
+	case "prefs":
+		return b.handlePrefsCommand(chatID, threadID, update.Message.CommandArguments())
 
 	default:
-		return b.sendLatestMenu(chatID)
+		if handled, err := b.handleAdminCommand(chatID, threadID, command, update.Message.CommandArguments()); handled {
+			return err
+		}
+		if handled, err := b.handleMenuCommand(chatID, threadID, command, update.Message.CommandArguments()); handled {
+			return err
+		}
+		return b.sendLatestMenu(chatID, threadID)
 	}
 }
 
@@ -321,7 +643,7 @@ func (b *Bot) HandleMessages() error {
 	updateConf := tgbotapi.NewUpdate(0)
 	updateConf.Timeout = 60
 
-	updates := b.bot.GetUpdatesChan(updateConf)
+	updates := b.messenger.Updates(updateConf)
 	for {
 		select {
 		case <-b.ctx.Done():
@@ -332,73 +654,101 @@ func (b *Bot) HandleMessages() error {
 				return nil
 			}
 
-			if update.Message != nil {
-				if update.Message.IsCommand() {
-					if err := b.handleCommand(update); err != nil {
-						slog.Error("Failed to handle command", "error", err)
-					}
-				} else {
-					if err := b.sendLatestMenu(update.Message.Chat.ID); err != nil {
-						slog.Error("Failed to send menu for message", "chat_id", update.Message.Chat.ID, "error", err)
-						if sendErr := b.SendMessage(int(update.Message.Chat.ID), "Не удалось получить меню. Попробуйте позже."); sendErr != nil {
-							slog.Error("Failed to send fallback message", "chat_id", update.Message.Chat.ID, "error", sendErr)
-						}
-					}
-				}
-			} else if update.CallbackQuery != nil {
-				if err := b.handleCallbackQuery(update.CallbackQuery); err != nil {
-					slog.Error("Failed to handle callback query", "error", err)
-				}
-
-				callbackCfg := tgbotapi.NewCallback(update.CallbackQuery.ID, "")
-				if _, err := b.bot.Request(callbackCfg); err != nil {
-					slog.Error("Failed to answer callback query", "error", err)
-				}
-			}
+			b.HandleUpdate(update)
 		}
 	}
 }
 
-func (b *Bot) buildMenuMessage() (string, error) {
-	peony, azilea, err := b.menuService.GetMenus()
-	if err != nil {
-		return "", fmt.Errorf("get menus: %w", err)
+// HandleWebhookUpdate decodes an update delivered to the webhook and dispatches it in the background,
+// so Telegram gets its answer before slow menu fetches run out its timeout and it resends the update.
+// Updates already seen, such as those resends, are dropped. Once Stop has begun updates are rejected
+// without being marked as seen, so Telegram can deliver them again after a restart.
+func (b *Bot) HandleWebhookUpdate(payload []byte) error {
+	var update telegram.Update
+	if err := json.Unmarshal(payload, &update); err != nil {
+		return fmt.Errorf("decode update: %w", err)
 	}
 
-	return FormatMenuMessage(peony, azilea), nil
-}
+	b.mu.Lock()
+	defer b.mu.Unlock()
 
-func FormatMenuMessage(peony, azilea *menu.Menu) string {
-	var message strings.Builder
-	message.WriteString("🍽️ Меню на сегодня.\n\n")
+	if b.stopping {
+		return errStopping
+	}
+	if !b.seenUpdates.firstSeen(update.UpdateID) {
+		slog.Info("Dropped repeated Telegram update", "update_id", update.UpdateID)
+		return nil
+	}
 
-	message.WriteString("🌸 Peony (верхняя столовая):\n")
-	if len(peony.Items) <= 1 {
-		message.WriteString("Сегодня выходной\n")
-	} else {
-		for i, item := range peony.Items {
-			message.WriteString(fmt.Sprintf("%d) %s", i+1, item.Name))
-			if item.Description != "" && item.Description != "TODO" {
-				message.WriteString(fmt.Sprintf(" - %s", item.Description))
-			}
-			message.WriteString("\n")
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.HandleUpdate(update)
+	}()
+	return nil
+}
+
+// HandleUpdate dispatches a single Telegram update, whether it came from polling or a webhook.
+func (b *Bot) HandleUpdate(update telegram.Update) {
+	if update.Message != nil && isGroupChat(update.Message.Chat) {
+		if update.Message.IsCommand() && b.isAddressedToOtherBot(update.Message) {
+			return
+		}
+		if !update.Message.IsCommand() && !b.isAddressedToBot(update.Message) {
+			return
 		}
 	}
 
-	message.WriteString("\n🌺 Azilea (нижняя столовая):\n")
-	if len(azilea.Items) <= 1 {
-		message.WriteString("Сегодня выходной\n")
-	} else {
-		for i, item := range azilea.Items {
-			message.WriteString(fmt.Sprintf("%d) %s", i+1, item.Name))
-			if item.Description != "" && item.Description != "TODO" {
-				message.WriteString(fmt.Sprintf(" - %s", item.Description))
+	if update.Message != nil {
+		if update.Message.IsCommand() {
+			if err := b.handleCommand(update); err != nil {
+				slog.Error("Failed to handle command", "error", err)
+			}
+		} else {
+			if err := b.handleText(update.Message.Chat.ID, update.MessageThreadID, update.Message.Text); err != nil {
+				slog.Error("Failed to send menu for message", "chat_id", update.Message.Chat.ID, "error", err)
+				if sendErr := b.SendMessage(update.Message.Chat.ID, update.MessageThreadID, "Не удалось получить меню. Попробуйте позже."); sendErr != nil {
+					slog.Error("Failed to send fallback message", "chat_id", update.Message.Chat.ID, "error", sendErr)
+				}
+			}
+		}
+	} else if update.InlineQuery != nil {
+		if err := b.handleInlineQuery(update.InlineQuery); err != nil {
+			slog.Error("Failed to handle inline query", "error", err)
+		}
+	} else if update.CallbackQuery != nil {
+		var (
+			answer string
+			err    error
+		)
+		if strings.HasPrefix(update.CallbackQuery.Data, ratingCallbackPrefix) {
+			answer, err = b.handleRatingCallback(update.CallbackQuery, update.MessageThreadID)
+		} else {
+			err = b.handleCallbackQuery(update.CallbackQuery, update.MessageThreadID)
+		}
+		if err != nil {
+			if errors.Is(err, errNotChatAdmin) {
+				answer = notChatAdminMessage
+			} else {
+				slog.Error("Failed to handle callback query", "error", err)
 			}
-			message.WriteString("\n")
 		}
+
+		if err := b.messenger.AnswerCallback(update.CallbackQuery.ID, answer); err != nil {
+			slog.Error("Failed to answer callback query", "error", err)
+		}
+	}
+}
+
+// buildMenuMessage renders today's menu together with its keyboard, which carries the rating buttons.
+func (b *Bot) buildMenuMessage() (string, tgbotapi.InlineKeyboardMarkup, error) {
+	peony, azilea, err := b.menuService.GetMenus()
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, fmt.Errorf("get menus: %w", err)
 	}
 
-	return message.String()
+	keyboard := b.ratedMenuKeyboard(b.clock.Now(), peony, azilea)
+	return FormatMenuMessage(b.withRatings(peony), b.withRatings(azilea)), keyboard, nil
 }
 
 func (b *Bot) Run() error {
@@ -406,16 +756,34 @@ func (b *Bot) Run() error {
 		return fmt.Errorf("bot already running")
 	}
 
-	if _, err := b.buildMenuMessage(); err != nil {
+	if _, _, err := b.buildMenuMessage(); err != nil {
 		return fmt.Errorf("initialize menu message: %w", err)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	b.ctx = ctx
 	b.cancel = cancel
+	b.setStopping(false)
+
+	if b.webhook != nil {
+		if err := b.setWebhook(); err != nil {
+			cancel()
+			b.cancel = nil
+			b.ctx = nil
+			return fmt.Errorf("set webhook: %w", err)
+		}
+	} else {
+		b.deleteWebhook()
+	}
 
+	b.registerCommands()
+	b.registerAdminCommands()
 	b.scheduleDailyMessages(ctx)
 
+	if b.webhook != nil {
+		return nil
+	}
+
 	b.wg.Add(1)
 	go func() {
 		defer b.wg.Done()
@@ -427,13 +795,20 @@ func (b *Bot) Run() error {
 	return nil
 }
 
+// Stop ends polling and the daily schedule and waits for updates that are still being handled.
+// Webhook updates and menu edits arriving from then on are rejected.
 func (b *Bot) Stop() error {
+	b.setStopping(true)
+
 	if b.cancel == nil {
+		b.wg.Wait()
 		return nil
 	}
 
 	b.cancel()
-	b.bot.StopReceivingUpdates()
+	if b.webhook == nil {
+		b.messenger.StopUpdates()
+	}
 	b.wg.Wait()
 
 	b.cancel = nil
@@ -442,3 +817,10 @@ func (b *Bot) Stop() error {
 	slog.Info("Telegram bot shutdown complete")
 	return nil
 }
+
+func (b *Bot) setStopping(stopping bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stopping = stopping
+}
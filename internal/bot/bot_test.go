@@ -0,0 +1,457 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/bot/bottest"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database/databasetest"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/telegram"
+)
+
+var testNow = time.Date(2025, time.October, 22, 11, 30, 0, 0, time.FixedZone("KST", 9*60*60))
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+// After fires at once: a fixed clock never moves, so there is nothing to wait for.
+func (c fixedClock) After(time.Duration) <-chan time.Time {
+	fired := make(chan time.Time, 1)
+	fired <- c.now
+	return fired
+}
+
+type stubMenuService struct {
+	peony  *menu.Menu
+	azilea *menu.Menu
+
+	fetched   []time.Time
+	refreshed []menu.Cafeteria
+	edits     []editCall
+}
+
+func (s *stubMenuService) GetMenus() (*menu.Menu, *menu.Menu, error) {
+	return s.peony, s.azilea, nil
+}
+
+func (s *stubMenuService) GetMenusForDate(ctx context.Context, date time.Time) (*menu.Menu, *menu.Menu, error) {
+	s.fetched = append(s.fetched, date)
+	return s.peony, s.azilea, nil
+}
+
+func (s *stubMenuService) GetMenuForDate(ctx context.Context, cafeteria menu.Cafeteria, date time.Time) (*menu.Menu, error) {
+	s.fetched = append(s.fetched, date)
+	return s.menuOf(cafeteria), nil
+}
+
+func (s *stubMenuService) menuOf(cafeteria menu.Cafeteria) *menu.Menu {
+	if cafeteria == menu.PEONY {
+		return s.peony
+	}
+	return s.azilea
+}
+
+// GetStoredMenuForDate only knows the menus of the day they are dated.
+func (s *stubMenuService) GetStoredMenuForDate(cafeteria menu.Cafeteria, date time.Time) (*menu.Menu, error) {
+	stored := s.menuOf(cafeteria)
+	if stored == nil || stored.Time == nil || stored.Time.Format("2006-01-02") != date.In(stored.Time.Location()).Format("2006-01-02") {
+		return nil, nil
+	}
+	return stored, nil
+}
+
+func newTestBot(t *testing.T) (*Bot, *bottest.FakeMessenger, *SubscriptionRepository) {
+	t.Helper()
+
+	messenger := bottest.NewFakeMessenger()
+	repo := NewSubscriptionRepository(databasetest.New(t))
+	menus := &stubMenuService{
+		peony:  menu.NewMenu([]*menu.MenuItem{{Name: "김치찌개", Description: "Острый суп", Spiciness: 4}, {Name: "돈까스"}}, &testNow),
+		azilea: menu.NewMenu([]*menu.MenuItem{{Name: "비빔밥"}, {Name: "된장국"}}, &testNow),
+	}
+
+	b := NewBotWithMessenger(messenger, repo, menus)
+	b.clock = fixedClock{now: testNow}
+
+	return b, messenger, repo
+}
+
+func commandUpdate(chatID int64, text string) telegram.Update {
+	command, _, _ := strings.Cut(text, " ")
+	return telegram.Update{Update: tgbotapi.Update{
+		Message: &tgbotapi.Message{
+			MessageID: 1,
+			Chat:      &tgbotapi.Chat{ID: chatID, Type: "private"},
+			Text:      text,
+			Entities:  []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}},
+		},
+	}}
+}
+
+func callbackQuery(chatID int64, data string) *tgbotapi.CallbackQuery {
+	return &tgbotapi.CallbackQuery{
+		ID:      "callback-1",
+		Data:    data,
+		Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: chatID, Type: "private"}},
+	}
+}
+
+func lastMessage(t *testing.T, messenger *bottest.FakeMessenger, chatID int64) tgbotapi.MessageConfig {
+	t.Helper()
+
+	messages := messenger.MessagesTo(chatID)
+	if len(messages) == 0 {
+		t.Fatalf("no messages sent to chat %d", chatID)
+	}
+	return messages[len(messages)-1]
+}
+
+func TestHandleCommand(t *testing.T) {
+	const chatID = 100
+
+	tests := []struct {
+		name       string
+		prepare    func(t *testing.T, repo *SubscriptionRepository)
+		command    string
+		wantText   string
+		wantActive *bool
+	}{
+		{name: "start", command: "/start", wantText: "Добро пожаловать"},
+		{name: "subscribe", command: "/subscribe", wantText: "Вы подписаны", wantActive: ptr(true)},
+		{
+			name:     "status subscribed",
+			prepare:  func(t *testing.T, repo *SubscriptionRepository) { mustSubscribe(t, repo, chatID) },
+			command:  "/status",
+			wantText: "✅ Подписан",
+		},
+		{name: "status not subscribed", command: "/status", wantText: "❌ Не подписан"},
+		{
+			name:       "unsubscribe",
+			prepare:    func(t *testing.T, repo *SubscriptionRepository) { mustSubscribe(t, repo, chatID) },
+			command:    "/unsubscribe",
+			wantText:   "Вы отписались",
+			wantActive: ptr(false),
+		},
+		{name: "today", command: "/today", wantText: "Меню на сегодня"},
+		{name: "tomorrow", command: "/tomorrow", wantText: "Меню на завтра"},
+		{name: "cafeteria", command: "/azilea", wantText: "비빔밥"},
+		{name: "watch", command: "/watch 돈까스", wantText: "Буду сообщать"},
+		{name: "unknown command falls back to menu", command: "/menu", wantText: "김치찌개"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, messenger, repo := newTestBot(t)
+			if tt.prepare != nil {
+				tt.prepare(t, repo)
+			}
+
+			if err := b.handleCommand(commandUpdate(chatID, tt.command)); err != nil {
+				t.Fatalf("handleCommand(%q) error: %v", tt.command, err)
+			}
+
+			if got := lastMessage(t, messenger, chatID).Text; !strings.Contains(got, tt.wantText) {
+				t.Errorf("reply to %q = %q, want it to contain %q", tt.command, got, tt.wantText)
+			}
+
+			if tt.wantActive != nil {
+				active, err := repo.GetStatus(chatID)
+				if err != nil {
+					t.Fatalf("GetStatus error: %v", err)
+				}
+				if active != *tt.wantActive {
+					t.Errorf("subscription active = %v, want %v", active, *tt.wantActive)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleCallbackQuery(t *testing.T) {
+	const chatID = 200
+
+	tests := []struct {
+		name       string
+		data       string
+		wantText   string
+		wantButton string
+		wantActive bool
+	}{
+		{name: "subscribe", data: "subscribe", wantText: "Вы подписаны", wantButton: "unsubscribe_confirm", wantActive: true},
+		{name: "confirm unsubscribe", data: "unsubscribe_confirm", wantText: "Вы уверены", wantButton: "unsubscribe_yes", wantActive: true},
+		{name: "unsubscribe", data: "unsubscribe_yes", wantText: "Вы отписались", wantButton: "subscribe", wantActive: false},
+		{name: "cancel unsubscribe", data: "unsubscribe_cancel", wantText: "Отписка отменена", wantButton: "unsubscribe_confirm", wantActive: true},
+		{name: "unknown action", data: "bogus", wantText: "Неизвестное действие", wantActive: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, messenger, repo := newTestBot(t)
+			if tt.data != "subscribe" {
+				mustSubscribe(t, repo, chatID)
+			}
+
+			if err := b.handleCallbackQuery(callbackQuery(chatID, tt.data), 0); err != nil {
+				t.Fatalf("handleCallbackQuery(%q) error: %v", tt.data, err)
+			}
+
+			reply := lastMessage(t, messenger, chatID)
+			if !strings.Contains(reply.Text, tt.wantText) {
+				t.Errorf("reply = %q, want it to contain %q", reply.Text, tt.wantText)
+			}
+			if tt.wantButton != "" && !hasButton(reply, tt.wantButton) {
+				t.Errorf("reply has no %q button: %+v", tt.wantButton, reply.ReplyMarkup)
+			}
+
+			active, err := repo.GetStatus(chatID)
+			if err != nil {
+				t.Fatalf("GetStatus error: %v", err)
+			}
+			if active != tt.wantActive {
+				t.Errorf("subscription active = %v, want %v", active, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestHandleUpdateAnswersCallbacks(t *testing.T) {
+	b, messenger, _ := newTestBot(t)
+
+	b.HandleUpdate(telegram.Update{Update: tgbotapi.Update{CallbackQuery: callbackQuery(300, "subscribe")}})
+
+	callbacks := messenger.Callbacks()
+	if len(callbacks) != 1 || callbacks[0].ID != "callback-1" {
+		t.Errorf("answered callbacks = %+v, want callback-1", callbacks)
+	}
+}
+
+func TestDispatchDailyMenu(t *testing.T) {
+	b, messenger, repo := newTestBot(t)
+
+	mustSubscribe(t, repo, 1)
+	mustSubscribe(t, repo, 2)
+	mustSubscribe(t, repo, 3)
+	if err := repo.Unsubscribe(3); err != nil {
+		t.Fatalf("Unsubscribe error: %v", err)
+	}
+
+	if err := b.dispatchDailyMenu(); err != nil {
+		t.Fatalf("dispatchDailyMenu error: %v", err)
+	}
+
+	for _, chatID := range []int64{1, 2} {
+		messages := messenger.MessagesTo(chatID)
+		if len(messages) != 1 {
+			t.Fatalf("chat %d received %d messages, want 1", chatID, len(messages))
+		}
+		if !strings.Contains(messages[0].Text, "김치찌개") || !strings.Contains(messages[0].Text, "비빔밥") {
+			t.Errorf("chat %d got incomplete menu: %q", chatID, messages[0].Text)
+		}
+	}
+	if messages := messenger.MessagesTo(3); len(messages) != 0 {
+		t.Errorf("unsubscribed chat received %d messages", len(messages))
+	}
+
+	sent, err := repo.LoadSentMessages(testNow)
+	if err != nil {
+		t.Fatalf("LoadSentMessages error: %v", err)
+	}
+	if len(sent) != 2 {
+		t.Errorf("recorded %d sent messages, want 2", len(sent))
+	}
+
+	previous := menu.NewMenuFromDishes([]string{"김치찌개", "제육볶음"}, &testNow)
+	current := menu.NewMenuFromDishes([]string{"김치찌개", "돈까스"}, &testNow)
+	b.HandleMenuChange(context.Background(), menu.PEONY, previous, current)
+	b.Stop()
+
+	edits := messenger.Edits()
+	if len(edits) != 2 {
+		t.Fatalf("edited %d messages, want 2", len(edits))
+	}
+	if !strings.Contains(edits[0].Text, "Меню 🌸 Peony обновлено в 11:30: +돈까스, −제육볶음") {
+		t.Errorf("edited text = %q, want update marker", edits[0].Text)
+	}
+}
+
+func TestMenuChangeEditsDeliveredMenus(t *testing.T) {
+	db := databasetest.New(t)
+	clock := fixedClock{now: testNow}
+	persistence := menu.NewMenuPersistenceService(menu.NewMenuRepository(db), clock)
+	for cafeteria, dishes := range map[menu.Cafeteria][]string{
+		menu.PEONY:  {"김치찌개", "돈까스"},
+		menu.AZILEA: {"비빔밥", "된장국"},
+	} {
+		if err := persistence.SaveMenu(cafeteria, menu.NewMenuFromDishes(dishes, nil)); err != nil {
+			t.Fatalf("SaveMenu(%s) error: %v", cafeteria, err)
+		}
+	}
+	menus := menu.NewMenuService(persistence, nil)
+
+	messenger := bottest.NewFakeMessenger()
+	repo := NewSubscriptionRepository(db)
+	b := NewBotWithMessenger(messenger, repo, menus)
+	b.clock = clock
+	menus.OnMenuChange(b.HandleMenuChange)
+
+	mustSubscribe(t, repo, 1)
+	if err := b.dispatchDailyMenu(); err != nil {
+		t.Fatalf("dispatchDailyMenu error: %v", err)
+	}
+
+	if _, err := menus.EditDish(context.Background(), menu.PEONY, 2, "치즈돈까스", ""); err != nil {
+		t.Fatalf("EditDish error: %v", err)
+	}
+	b.Stop()
+
+	delivered, err := repo.LoadSentMessages(testNow)
+	if err != nil || len(delivered) != 1 {
+		t.Fatalf("LoadSentMessages = %v, %v, want the one delivered menu", delivered, err)
+	}
+
+	edits := messenger.Edits()
+	if len(edits) != 1 {
+		t.Fatalf("edited %d messages, want 1", len(edits))
+	}
+	if edits[0].ChatID != 1 || !slices.Equal(delivered[0].MessageIDs, []int{edits[0].MessageID}) {
+		t.Errorf("edited message %d in chat %d, want messages %v in chat 1", edits[0].MessageID, edits[0].ChatID, delivered[0].MessageIDs)
+	}
+	if !strings.Contains(edits[0].Text, "치즈돈까스") {
+		t.Errorf("edited text = %q, want the corrected dish", edits[0].Text)
+	}
+}
+
+func TestMenuChangeWithoutMenuKeepsMessages(t *testing.T) {
+	b, messenger, repo := newTestBot(t)
+
+	mustSubscribe(t, repo, 1)
+	if err := b.dispatchDailyMenu(); err != nil {
+		t.Fatalf("dispatchDailyMenu error: %v", err)
+	}
+
+	previous := menu.NewMenuFromDishes([]string{"김치찌개", "돈까스"}, &testNow)
+	b.HandleMenuChange(context.Background(), menu.PEONY, previous, menu.NewMenuFromDishes([]string{"Сегодня меню недоступно"}, &testNow))
+	b.Stop()
+
+	if edits := messenger.Edits(); len(edits) != 0 {
+		t.Errorf("edited %d messages after the menu disappeared, want none", len(edits))
+	}
+}
+
+func TestMenuChangeEditsEveryPart(t *testing.T) {
+	longMenu := func(dishes int) *menu.Menu {
+		items := make([]*menu.MenuItem, dishes)
+		for i := range items {
+			items[i] = &menu.MenuItem{Name: fmt.Sprintf("김치찌개 %d", i), Description: strings.Repeat("Острый суп. ", 10)}
+		}
+		return menu.NewMenu(items, &testNow)
+	}
+
+	// kept is the number of delivered parts the new menu still takes, zero for all of them.
+	tests := []struct {
+		name   string
+		dishes int
+		kept   int
+	}{
+		{name: "same length", dishes: 60},
+		{name: "shorter", dishes: 2, kept: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, messenger, repo := newTestBot(t)
+			menus := b.menuService.(*stubMenuService)
+			menus.peony = longMenu(60)
+
+			mustSubscribe(t, repo, 1)
+			if err := b.dispatchDailyMenu(); err != nil {
+				t.Fatalf("dispatchDailyMenu error: %v", err)
+			}
+			sent, err := repo.LoadSentMessages(testNow)
+			if err != nil || len(sent) != 1 || len(sent[0].MessageIDs) < 2 {
+				t.Fatalf("LoadSentMessages = %+v, %v; want one menu split into parts", sent, err)
+			}
+			parts := sent[0].MessageIDs
+			kept := parts
+			if tt.kept > 0 {
+				kept = parts[:tt.kept]
+			}
+
+			previous := menus.peony
+			menus.peony = longMenu(tt.dishes)
+			b.HandleMenuChange(context.Background(), menu.PEONY, previous, menus.peony)
+			b.Stop()
+
+			edits := messenger.Edits()
+			if len(edits) != len(kept) {
+				t.Fatalf("edited %d messages, want %d", len(edits), len(kept))
+			}
+			for i, edit := range edits {
+				if edit.MessageID != parts[i] {
+					t.Errorf("edit %d changed message %d, want %d", i+1, edit.MessageID, parts[i])
+				}
+				if hasKeyboard, last := edit.ReplyMarkup != nil, i == len(edits)-1; hasKeyboard != last {
+					t.Errorf("edit %d has keyboard %v, want it only on the last part", i+1, hasKeyboard)
+				}
+			}
+			if got := len(messenger.Requests()); got != len(parts)-len(kept) {
+				t.Errorf("deleted %d outdated parts, want %d", got, len(parts)-len(kept))
+			}
+
+			updated, err := repo.LoadSentMessages(testNow)
+			if err != nil || len(updated) != 1 || !slices.Equal(updated[0].MessageIDs, kept) {
+				t.Errorf("LoadSentMessages after edit = %+v, %v; want parts %v", updated, err, kept)
+			}
+		})
+	}
+}
+
+func TestDispatchDailyMenuWithoutSubscribers(t *testing.T) {
+	b, messenger, _ := newTestBot(t)
+
+	if err := b.dispatchDailyMenu(); err != nil {
+		t.Fatalf("dispatchDailyMenu error: %v", err)
+	}
+	if sent := messenger.Sent(); len(sent) != 0 {
+		t.Errorf("sent %d messages without subscribers", len(sent))
+	}
+}
+
+func mustSubscribe(t *testing.T, repo *SubscriptionRepository, chatID int64) {
+	t.Helper()
+
+	if err := repo.Subscribe(chatID, 0); err != nil {
+		t.Fatalf("Subscribe(%d) error: %v", chatID, err)
+	}
+}
+
+func hasButton(message tgbotapi.MessageConfig, data string) bool {
+	markup, ok := message.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	if !ok {
+		return false
+	}
+	for _, row := range markup.InlineKeyboard {
+		for _, button := range row {
+			if button.CallbackData != nil && *button.CallbackData == data {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,280 @@
+// Package bottest provides an in-memory Telegram messenger for exercising the bot offline.
+package bottest
+
+import (
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/telegram"
+)
+
+// BotUsername is the username the fake reports for the bot itself.
+const BotUsername = "kbu_menu_bot"
+
+type Callback struct {
+	ID   string
+	Text string
+}
+
+type ThreadMessage struct {
+	Message  tgbotapi.Chattable
+	ThreadID int
+}
+
+type RawRequest struct {
+	Method string
+	Params tgbotapi.Params
+}
+
+// FakeMessenger records everything the bot sends and lets tests push updates.
+type FakeMessenger struct {
+	mu            sync.Mutex
+	nextMessageID int
+	sent          []tgbotapi.Chattable
+	edits         []tgbotapi.EditMessageTextConfig
+	callbacks     []Callback
+	requests      []tgbotapi.Chattable
+	rawRequests   []RawRequest
+	threadSends   []ThreadMessage
+	updates       chan telegram.Update
+
+	// SendErr, when set, is returned by Send for the listed chats.
+	SendErr map[int64]error
+	// MemberStatus maps chat and user to a Telegram member status such as "administrator".
+	// Users missing from the map are plain members.
+	MemberStatus map[int64]map[int64]string
+}
+
+func NewFakeMessenger() *FakeMessenger {
+	return &FakeMessenger{
+		updates:      make(chan telegram.Update, 100),
+		MemberStatus: make(map[int64]map[int64]string),
+	}
+}
+
+func (m *FakeMessenger) Username() string {
+	return BotUsername
+}
+
+// SetMemberStatus records the status ChatMember reports for a user in a chat.
+func (m *FakeMessenger) SetMemberStatus(chatID, userID int64, status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.MemberStatus[chatID] == nil {
+		m.MemberStatus[chatID] = make(map[int64]string)
+	}
+	m.MemberStatus[chatID][userID] = status
+}
+
+func (m *FakeMessenger) ChatMember(chatID, userID int64) (tgbotapi.ChatMember, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := m.MemberStatus[chatID][userID]
+	if status == "" {
+		status = "member"
+	}
+	return tgbotapi.ChatMember{User: &tgbotapi.User{ID: userID}, Status: status}, nil
+}
+
+func (m *FakeMessenger) Send(message tgbotapi.Chattable) (tgbotapi.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chatID := ChatID(message)
+	if err := m.SendErr[chatID]; err != nil {
+		return tgbotapi.Message{}, err
+	}
+
+	m.nextMessageID++
+	m.sent = append(m.sent, message)
+
+	return tgbotapi.Message{
+		MessageID: m.nextMessageID,
+		Chat:      &tgbotapi.Chat{ID: chatID},
+	}, nil
+}
+
+// SendToThread records the topic and delivers the message like Send.
+func (m *FakeMessenger) SendToThread(message tgbotapi.MessageConfig, threadID int) (tgbotapi.Message, error) {
+	sent, err := m.Send(message)
+	if err != nil {
+		return sent, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.threadSends = append(m.threadSends, ThreadMessage{Message: message, ThreadID: threadID})
+	return sent, nil
+}
+
+// SendPhotoToThread records the topic and delivers the photo like Send.
+func (m *FakeMessenger) SendPhotoToThread(photo tgbotapi.PhotoConfig, threadID int) (tgbotapi.Message, error) {
+	sent, err := m.Send(photo)
+	if err != nil {
+		return sent, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.threadSends = append(m.threadSends, ThreadMessage{Message: photo, ThreadID: threadID})
+	return sent, nil
+}
+
+func (m *FakeMessenger) Edit(edit tgbotapi.EditMessageTextConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.edits = append(m.edits, edit)
+	return nil
+}
+
+func (m *FakeMessenger) AnswerCallback(callbackID, text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.callbacks = append(m.callbacks, Callback{ID: callbackID, Text: text})
+	return nil
+}
+
+func (m *FakeMessenger) Request(config tgbotapi.Chattable) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests = append(m.requests, config)
+	return nil
+}
+
+func (m *FakeMessenger) MakeRequest(method string, params tgbotapi.Params) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rawRequests = append(m.rawRequests, RawRequest{Method: method, Params: params})
+	return nil
+}
+
+func (m *FakeMessenger) Updates(config tgbotapi.UpdateConfig) <-chan telegram.Update {
+	return m.updates
+}
+
+func (m *FakeMessenger) StopUpdates() {}
+
+// Push queues an update for the bot's polling loop.
+func (m *FakeMessenger) Push(update tgbotapi.Update) {
+	m.updates <- telegram.Update{Update: update}
+}
+
+// Messages returns the text messages sent so far.
+func (m *FakeMessenger) Messages() []tgbotapi.MessageConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var messages []tgbotapi.MessageConfig
+	for _, sent := range m.sent {
+		if message, ok := sent.(tgbotapi.MessageConfig); ok {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// Photos returns the photos sent so far.
+func (m *FakeMessenger) Photos() []tgbotapi.PhotoConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var photos []tgbotapi.PhotoConfig
+	for _, sent := range m.sent {
+		if photo, ok := sent.(tgbotapi.PhotoConfig); ok {
+			photos = append(photos, photo)
+		}
+	}
+	return photos
+}
+
+// MessagesTo returns the text messages sent to one chat.
+func (m *FakeMessenger) MessagesTo(chatID int64) []tgbotapi.MessageConfig {
+	var messages []tgbotapi.MessageConfig
+	for _, message := range m.Messages() {
+		if message.ChatID == chatID {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+func (m *FakeMessenger) Sent() []tgbotapi.Chattable {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]tgbotapi.Chattable(nil), m.sent...)
+}
+
+// ThreadMessages returns the messages posted into forum topics.
+func (m *FakeMessenger) ThreadMessages() []ThreadMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]ThreadMessage(nil), m.threadSends...)
+}
+
+func (m *FakeMessenger) Edits() []tgbotapi.EditMessageTextConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]tgbotapi.EditMessageTextConfig(nil), m.edits...)
+}
+
+func (m *FakeMessenger) Callbacks() []Callback {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Callback(nil), m.callbacks...)
+}
+
+func (m *FakeMessenger) Requests() []tgbotapi.Chattable {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]tgbotapi.Chattable(nil), m.requests...)
+}
+
+func (m *FakeMessenger) RawRequests() []RawRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]RawRequest(nil), m.rawRequests...)
+}
+
+// Reset forgets everything recorded so far.
+func (m *FakeMessenger) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sent = nil
+	m.edits = nil
+	m.callbacks = nil
+	m.requests = nil
+	m.rawRequests = nil
+	m.threadSends = nil
+}
+
+// ChatID extracts the target chat of the sendable configs the bot uses.
+func ChatID(message tgbotapi.Chattable) int64 {
+	switch config := message.(type) {
+	case tgbotapi.MessageConfig:
+		return config.ChatID
+	case tgbotapi.PhotoConfig:
+		return config.ChatID
+	case tgbotapi.EditMessageTextConfig:
+		return config.ChatID
+	case tgbotapi.EditMessageMediaConfig:
+		return config.ChatID
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Telegram allows about 30 messages per second across all chats, so broadcasts stay below that.
+const broadcastInterval = time.Second / 25
+
+type broadcastResult struct {
+	Delivered int
+	Failed    int
+}
+
+// broadcast calls deliver for every chat, spacing the calls to respect Telegram rate limits.
+func (b *Bot) broadcast(ctx context.Context, action string, chatIDs []int64, deliver func(chatID int64) error) broadcastResult {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ticker := time.NewTicker(broadcastInterval)
+	defer ticker.Stop()
+
+	var (
+		wg        sync.WaitGroup
+		delivered atomic.Int64
+		failed    atomic.Int64
+	)
+
+	for i, chatID := range chatIDs {
+		select {
+		case <-ctx.Done():
+			slog.Warn("Broadcast interrupted",
+				"action", action,
+				"remaining", len(chatIDs)-i)
+			wg.Wait()
+			return broadcastResult{Delivered: int(delivered.Load()), Failed: int(failed.Load())}
+		case <-ticker.C:
+		}
+
+		wg.Add(1)
+		go func(chatID int64) {
+			defer wg.Done()
+			if err := deliver(chatID); err != nil {
+				failed.Add(1)
+				slog.Error("Failed to deliver broadcast", "action", action, "chat_id", chatID, "error", err)
+				return
+			}
+			delivered.Add(1)
+		}(chatID)
+	}
+
+	wg.Wait()
+
+	result := broadcastResult{Delivered: int(delivered.Load()), Failed: int(failed.Load())}
+	slog.Info("Broadcast finished",
+		"action", action,
+		"delivered", result.Delivered,
+		"failed", result.Failed)
+	return result
+}
+
+// subscriptionTargets lists the subscribed chats and indexes their subscriptions by chat.
+func subscriptionTargets(subscribers []Subscription) ([]int64, map[int64]Subscription) {
+	chatIDs := make([]int64, 0, len(subscribers))
+	byChat := make(map[int64]Subscription, len(subscribers))
+	for _, subscriber := range subscribers {
+		chatIDs = append(chatIDs, subscriber.ChatID)
+		byChat[subscriber.ChatID] = subscriber
+	}
+	return chatIDs, byChat
+}
@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menucard"
+)
+
+const (
+	menuFormatText  = "text"
+	menuFormatImage = "image"
+)
+
+var (
+	formatTextWords  = []string{"text", "текст"}
+	formatImageWords = []string{"image", "картинка", "изображение", "фото"}
+)
+
+// EnableMenuCards lets chats switch to receiving the menu as an image card.
+func (b *Bot) EnableMenuCards(renderer *menucard.Renderer) {
+	b.cards = renderer
+}
+
+// chatMenuFormat falls back to text when cards are disabled or the setting cannot be read.
+func (b *Bot) chatMenuFormat(chatID int64) string {
+	if b.cards == nil {
+		return menuFormatText
+	}
+
+	format, err := b.repo.GetMenuFormat(chatID)
+	if err != nil {
+		slog.Warn("Failed to load menu format", "chat_id", chatID, "error", err)
+		return menuFormatText
+	}
+	return format
+}
+
+func (b *Bot) renderMenuCard(date time.Time) ([]byte, error) {
+	peony, azilea, err := b.menuService.GetMenusForDate(b.context(), date)
+	if err != nil {
+		return nil, fmt.Errorf("get menus for %s: %w", date.Format("2006-01-02"), err)
+	}
+
+	return b.cards.Render(date, peony, azilea)
+}
+
+func menuCardFile(card []byte) tgbotapi.FileBytes {
+	return tgbotapi.FileBytes{Name: "menu.png", Bytes: card}
+}
+
+func (b *Bot) sendMenuCard(chatID int64, threadID int, card []byte, caption string) (tgbotapi.Message, error) {
+	photo := tgbotapi.NewPhoto(chatID, menuCardFile(card))
+	photo.Caption = caption
+	photo.ParseMode = tgbotapi.ModeHTML
+	photo.ReplyMarkup = menuKeyboard()
+
+	if threadID != 0 {
+		return b.messenger.SendPhotoToThread(photo, threadID)
+	}
+	return b.messenger.Send(photo)
+}
+
+func menuCardCaption(dayLabel string) string {
+	return fmt.Sprintf("🍽️ <b>Меню на %s</b>", dayLabel)
+}
+
+func formatKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📝 Текст", "format_text"),
+			tgbotapi.NewInlineKeyboardButtonData("🖼 Картинка", "format_image"),
+		),
+	)
+}
+
+// handleFormatCommand switches between text and image menus: "/format image" or "/format текст".
+func (b *Bot) handleFormatCommand(chatID int64, threadID int, args string) error {
+	if b.cards == nil {
+		return b.SendMessage(chatID, threadID, "Меню в виде картинки сейчас недоступно.")
+	}
+
+	word := strings.ToLower(strings.TrimSpace(args))
+	switch {
+	case slices.Contains(formatTextWords, word):
+		return b.setMenuFormat(chatID, threadID, menuFormatText)
+	case slices.Contains(formatImageWords, word):
+		return b.setMenuFormat(chatID, threadID, menuFormatImage)
+	}
+
+	current := "текст"
+	if b.chatMenuFormat(chatID) == menuFormatImage {
+		current = "картинка"
+	}
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Формат меню: %s\nВыберите, как присылать меню:", current))
+	msg.ReplyMarkup = formatKeyboard()
+	_, err := b.send(msg, threadID)
+	return err
+}
+
+func (b *Bot) setMenuFormat(chatID int64, threadID int, format string) error {
+	if b.cards == nil {
+		return b.SendMessage(chatID, threadID, "Меню в виде картинки сейчас недоступно.")
+	}
+
+	if err := b.repo.SetMenuFormat(chatID, format); err != nil {
+		slog.Error("Failed to save menu format", "chat_id", chatID, "error", err)
+		return b.SendMessage(chatID, threadID, "Не удалось сохранить формат. Попробуйте позже.")
+	}
+
+	if format == menuFormatImage {
+		return b.SendMessage(chatID, threadID, "🖼 Теперь меню будет приходить картинкой.")
+	}
+	return b.SendMessage(chatID, threadID, "📝 Теперь меню будет приходить текстом.")
+}
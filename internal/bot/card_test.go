@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	kbudailymenu "github.com/artyom-kalman/kbu-daily-menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menucard"
+)
+
+func enableTestMenuCards(b *Bot) {
+	b.EnableMenuCards(menucard.NewRenderer(kbudailymenu.Assets(""), "web/static/stickers/kto.webp"))
+}
+
+func TestFormatCommand(t *testing.T) {
+	const chatID = 100
+
+	b, messenger, repo := newTestBot(t)
+
+	if err := b.handleCommand(commandUpdate(chatID, "/format image")); err != nil {
+		t.Fatalf("handleCommand() error: %v", err)
+	}
+	if got := lastMessage(t, messenger, chatID).Text; !strings.Contains(got, "недоступно") {
+		t.Errorf("reply without renderer = %q, want unavailable notice", got)
+	}
+
+	enableTestMenuCards(b)
+
+	if err := b.handleCommand(commandUpdate(chatID, "/format картинка")); err != nil {
+		t.Fatalf("handleCommand() error: %v", err)
+	}
+	if format, _ := repo.GetMenuFormat(chatID); format != menuFormatImage {
+		t.Fatalf("GetMenuFormat() = %q, want %q", format, menuFormatImage)
+	}
+
+	messenger.Reset()
+	if err := b.handleCommand(commandUpdate(chatID, "/today")); err != nil {
+		t.Fatalf("handleCommand() error: %v", err)
+	}
+
+	photos := messenger.Photos()
+	if len(photos) != 1 {
+		t.Fatalf("sent %d photos, want 1", len(photos))
+	}
+	file, ok := photos[0].File.(tgbotapi.FileBytes)
+	if !ok {
+		t.Fatalf("photo file is %T, want FileBytes", photos[0].File)
+	}
+	if _, err := png.Decode(bytes.NewReader(file.Bytes)); err != nil {
+		t.Errorf("photo is not a PNG: %v", err)
+	}
+
+	if err := b.handleCallbackQuery(callbackQuery(chatID, "format_text"), 0); err != nil {
+		t.Fatalf("handleCallbackQuery() error: %v", err)
+	}
+	if format, _ := repo.GetMenuFormat(chatID); format != menuFormatText {
+		t.Errorf("GetMenuFormat() = %q after switching back, want %q", format, menuFormatText)
+	}
+}
+
+func TestDispatchDailyMenuCards(t *testing.T) {
+	b, messenger, repo := newTestBot(t)
+	enableTestMenuCards(b)
+
+	mustSubscribe(t, repo, 1)
+	mustSubscribe(t, repo, 2)
+	if err := repo.SetMenuFormat(2, menuFormatImage); err != nil {
+		t.Fatalf("SetMenuFormat() error: %v", err)
+	}
+
+	if err := b.dispatchDailyMenu(); err != nil {
+		t.Fatalf("dispatchDailyMenu() error: %v", err)
+	}
+
+	if got := len(messenger.MessagesTo(1)); got != 1 {
+		t.Errorf("text subscriber received %d messages, want 1", got)
+	}
+	photos := messenger.Photos()
+	if len(photos) != 1 || photos[0].ChatID != 2 {
+		t.Fatalf("photos = %+v, want one photo for chat 2", photos)
+	}
+
+	b.HandleMenuChange(context.Background(), menu.PEONY, nil, menu.NewMenuFromDishes([]string{"김치찌개", "돈까스"}, &testNow))
+	b.Stop()
+
+	if got := len(messenger.Edits()); got != 1 {
+		t.Errorf("edited %d text messages, want 1", got)
+	}
+
+	var mediaEdits int
+	for _, sent := range messenger.Sent() {
+		if edit, ok := sent.(tgbotapi.EditMessageMediaConfig); ok && edit.ChatID == 2 {
+			mediaEdits++
+		}
+	}
+	if mediaEdits != 1 {
+		t.Errorf("edited %d menu cards, want 1", mediaEdits)
+	}
+}
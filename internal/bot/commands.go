@@ -0,0 +1,177 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+var botCommands = []tgbotapi.BotCommand{
+	{Command: "today", Description: "Меню на сегодня"},
+	{Command: "tomorrow", Description: "Меню на завтра"},
+	{Command: "week", Description: "Меню на всю неделю"},
+	{Command: "peony", Description: "Меню верхней столовой Peony"},
+	{Command: "azilea", Description: "Меню нижней столовой Azilea"},
+	{Command: "mon", Description: "Меню на понедельник"},
+	{Command: "tue", Description: "Меню на вторник"},
+	{Command: "wed", Description: "Меню на среду"},
+	{Command: "thu", Description: "Меню на четверг"},
+	{Command: "fri", Description: "Меню на пятницу"},
+	{Command: "subscribe", Description: "Подписаться на ежедневное меню"},
+	{Command: "unsubscribe", Description: "Отписаться от ежедневного меню"},
+	{Command: "status", Description: "Статус подписки"},
+	{Command: "watch", Description: "Отслеживать блюдо"},
+	{Command: "unwatch", Description: "Перестать отслеживать блюдо"},
+	{Command: "watches", Description: "Список отслеживаемых блюд"},
+	{Command: "format", Description: "Меню текстом или картинкой"},
+	{Command: "top", Description: "Лучшие блюда месяца"},
+	{Command: "prefs", Description: "Предпочтения для выбора столовой"},
+}
+
+var weekdayCommands = map[string]time.Weekday{
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+}
+
+func (b *Bot) registerCommands() {
+	if err := b.messenger.Request(tgbotapi.NewSetMyCommands(botCommands...)); err != nil {
+		slog.Warn("Failed to register bot commands", "error", err)
+	}
+}
+
+func (b *Bot) context() context.Context {
+	if b.ctx == nil {
+		return context.Background()
+	}
+	return b.ctx
+}
+
+// handleText answers free-form messages like "что в пиони завтра?" or "в пятницу".
+func (b *Bot) handleText(chatID int64, threadID int, text string) error {
+	date, _ := parseDateText(text, b.clock.Now())
+	if cafeteria := parseCafeteria(text); cafeteria != "" {
+		return b.sendCafeteriaMenu(chatID, threadID, cafeteria, date)
+	}
+	return b.sendMenuForDate(chatID, threadID, date)
+}
+
+func parseCafeteria(text string) menu.Cafeteria {
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		word = strings.Trim(word, "?!.,")
+		switch {
+		case slices.Contains(inlinePeonyWords, word):
+			return menu.PEONY
+		case slices.Contains(inlineAzileaWords, word):
+			return menu.AZILEA
+		}
+	}
+	return ""
+}
+
+func (b *Bot) sendMenuForDate(chatID int64, threadID int, date time.Time) error {
+	now := b.clock.Now()
+	label := dayLabel(date, now)
+	if !menuPublished(date, now) {
+		return b.SendMessage(chatID, threadID, notPublishedText(label))
+	}
+
+	if b.chatMenuFormat(chatID) == menuFormatImage {
+		card, err := b.renderMenuCard(date)
+		if err == nil {
+			_, err = b.sendMenuCard(chatID, threadID, card, menuCardCaption(label))
+			return err
+		}
+		slog.Error("Failed to render menu card, sending text", "chat_id", chatID, "error", err)
+	}
+
+	peony, azilea, err := b.menuService.GetMenusForDate(b.context(), date)
+	if err != nil {
+		return fmt.Errorf("get menus for %s: %w", date.Format("2006-01-02"), err)
+	}
+
+	keyboard := b.ratedMenuKeyboard(date, peony, azilea)
+	peony, azilea = b.withRatings(peony), b.withRatings(azilea)
+
+	note := personalNote{lead: b.comparisonLead(chatID, peony, azilea)}
+	_, err = b.sendMenuToThread(chatID, threadID, note.wrap(formatMenus(label, peony, azilea)), keyboard)
+	return err
+}
+
+func (b *Bot) sendCafeteriaMenu(chatID int64, threadID int, cafeteria menu.Cafeteria, date time.Time) error {
+	now := b.clock.Now()
+	if !menuPublished(date, now) {
+		return b.SendMessage(chatID, threadID, notPublishedText(dayLabel(date, now)))
+	}
+
+	cafeteriaMenu, err := b.menuService.GetMenuForDate(b.context(), cafeteria, date)
+	if err != nil {
+		return fmt.Errorf("get %s menu for %s: %w", cafeteria, date.Format("2006-01-02"), err)
+	}
+
+	var keyboard tgbotapi.InlineKeyboardMarkup
+	if cafeteria == menu.PEONY {
+		keyboard = b.ratedMenuKeyboard(date, cafeteriaMenu, nil)
+	} else {
+		keyboard = b.ratedMenuKeyboard(date, nil, cafeteriaMenu)
+	}
+
+	text := formatCafeteriaMenu(dayLabel(date, now), cafeteria, b.withRatings(cafeteriaMenu))
+	_, err = b.sendMenuToThread(chatID, threadID, text, keyboard)
+	return err
+}
+
+// sendWeekMenu sends one message per weekday so long descriptions stay within Telegram limits.
+func (b *Bot) sendWeekMenu(chatID int64, threadID int) error {
+	now := b.clock.Now()
+
+	for weekday := time.Monday; weekday <= time.Friday; weekday++ {
+		date := weekdayOfCurrentWeek(now, weekday)
+
+		peony, azilea, err := b.menuService.GetMenusForDate(b.context(), date)
+		if err != nil {
+			return fmt.Errorf("get menus for %s: %w", date.Format("2006-01-02"), err)
+		}
+
+		text := fmt.Sprintf("📅 <b>%s, %s</b>\n\n%s", weekdayNominative[weekday], date.Format("02.01"), formatMenus(dayLabel(date, now), peony, azilea))
+		if _, err := b.sendHTML(chatID, threadID, text, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *Bot) handleMenuCommand(chatID int64, threadID int, command, args string) (bool, error) {
+	now := b.clock.Now()
+
+	switch command {
+	case "today":
+		return true, b.sendMenuForDate(chatID, threadID, now)
+
+	case "tomorrow":
+		return true, b.sendMenuForDate(chatID, threadID, now.AddDate(0, 0, 1))
+
+	case "week":
+		return true, b.sendWeekMenu(chatID, threadID)
+
+	case "peony", "azilea":
+		date, _ := parseDateText(args, now)
+		return true, b.sendCafeteriaMenu(chatID, threadID, menu.Cafeteria(command), date)
+	}
+
+	if weekday, ok := weekdayCommands[command]; ok {
+		return true, b.sendMenuForDate(chatID, threadID, weekdayOfCurrentWeek(now, weekday))
+	}
+
+	return false, nil
+}
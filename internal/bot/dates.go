@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	todayLabel    = "сегодня"
+	tomorrowLabel = "завтра"
+)
+
+var weekdayAccusative = [...]string{"воскресенье", "понедельник", "вторник", "среду", "четверг", "пятницу", "субботу"}
+
+// dayLabel names the date relative to now so it reads naturally after "Меню на".
+func dayLabel(date, now time.Time) string {
+	switch daysBetween(now, date) {
+	case 0:
+		return todayLabel
+	case 1:
+		return tomorrowLabel
+	default:
+		return fmt.Sprintf("%s, %s", weekdayAccusative[date.Weekday()], date.Format("02.01"))
+	}
+}
+
+func daysBetween(from, to time.Time) int {
+	fromDay := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDay := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDay.Sub(fromDay).Hours() / 24)
+}
+
+var weekdayNominative = [...]string{"Воскресенье", "Понедельник", "Вторник", "Среда", "Четверг", "Пятница", "Суббота"}
+
+var weekdayWords = map[string]time.Weekday{
+	"понедельник": time.Monday, "пн": time.Monday, "monday": time.Monday, "mon": time.Monday,
+	"вторник": time.Tuesday, "вт": time.Tuesday, "tuesday": time.Tuesday, "tue": time.Tuesday,
+	"среда": time.Wednesday, "среду": time.Wednesday, "ср": time.Wednesday, "wednesday": time.Wednesday, "wed": time.Wednesday,
+	"четверг": time.Thursday, "чт": time.Thursday, "thursday": time.Thursday, "thu": time.Thursday,
+	"пятница": time.Friday, "пятницу": time.Friday, "пт": time.Friday, "friday": time.Friday, "fri": time.Friday,
+	"суббота": time.Saturday, "субботу": time.Saturday, "сб": time.Saturday, "saturday": time.Saturday, "sat": time.Saturday,
+	"воскресенье": time.Sunday, "вс": time.Sunday, "sunday": time.Sunday, "sun": time.Sunday,
+}
+
+// parseDateText finds a day reference such as "завтра" or "в пятницу" in free text.
+// Weekdays resolve to that day of the current week, the only week the cafeteria pages list.
+func parseDateText(text string, now time.Time) (time.Time, bool) {
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		switch word {
+		case "сегодня", "today":
+			return now, true
+		case "завтра", "tomorrow":
+			return now.AddDate(0, 0, 1), true
+		case "послезавтра":
+			return now.AddDate(0, 0, 2), true
+		}
+
+		if weekday, ok := weekdayWords[word]; ok {
+			return weekdayOfCurrentWeek(now, weekday), true
+		}
+	}
+	return now, false
+}
+
+// weekdayOfCurrentWeek returns the given weekday of the Monday-based week containing now,
+// which matches the week shown on the cafeteria menu page.
+func weekdayOfCurrentWeek(now time.Time, weekday time.Weekday) time.Time {
+	return now.AddDate(0, 0, isoWeekday(weekday)-isoWeekday(now.Weekday()))
+}
+
+// menuPublished reports whether date is Monday to Friday of the current week, the days the cafeteria pages list.
+func menuPublished(date, now time.Time) bool {
+	weekday := date.Weekday()
+	if weekday == time.Saturday || weekday == time.Sunday {
+		return false
+	}
+	return daysBetween(weekdayOfCurrentWeek(now, weekday), date) == 0
+}
+
+func notPublishedText(label string) string {
+	return fmt.Sprintf("Меню на %s ещё не опубликовано: столовые выкладывают меню только на будни текущей недели.", label)
+}
+
+func isoWeekday(weekday time.Weekday) int {
+	if weekday == time.Sunday {
+		return 7
+	}
+	return int(weekday)
+}
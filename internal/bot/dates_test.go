@@ -0,0 +1,130 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func kstDate(month time.Month, day int) time.Time {
+	return time.Date(2025, month, day, 11, 30, 0, 0, testNow.Location())
+}
+
+func TestParseDateText(t *testing.T) {
+	// testNow is Wednesday, 22 October 2025.
+	tests := []struct {
+		name   string
+		text   string
+		now    time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{name: "no day", text: "что в пиони?", now: testNow, want: testNow, wantOK: false},
+		{name: "today", text: "Что сегодня?", now: testNow, want: testNow, wantOK: true},
+		{name: "tomorrow", text: "меню на завтра", now: testNow, want: kstDate(time.October, 23), wantOK: true},
+		{name: "day after tomorrow", text: "послезавтра", now: testNow, want: kstDate(time.October, 24), wantOK: true},
+		{name: "later weekday", text: "в пятницу", now: testNow, want: kstDate(time.October, 24), wantOK: true},
+		{name: "earlier weekday stays in this week", text: "а в понедельник?", now: testNow, want: kstDate(time.October, 20), wantOK: true},
+		{name: "abbreviation", text: "вт", now: testNow, want: kstDate(time.October, 21), wantOK: true},
+		{name: "english", text: "thursday peony", now: testNow, want: kstDate(time.October, 23), wantOK: true},
+		{name: "saturday is not mapped to friday", text: "в субботу", now: testNow, want: kstDate(time.October, 25), wantOK: true},
+		{name: "monday asked on sunday", text: "понедельник", now: kstDate(time.October, 26), want: kstDate(time.October, 20), wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseDateText(tt.text, tt.now)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("parseDateText(%q) = %s, %v, want %s, %v", tt.text, got.Format("2006-01-02"), ok, tt.want.Format("2006-01-02"), tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDayLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		date time.Time
+		want string
+	}{
+		{name: "today", date: testNow, want: "сегодня"},
+		{name: "tomorrow", date: kstDate(time.October, 23), want: "завтра"},
+		{name: "tomorrow just after midnight", date: time.Date(2025, time.October, 23, 0, 5, 0, 0, testNow.Location()), want: "завтра"},
+		{name: "later this week", date: kstDate(time.October, 24), want: "пятницу, 24.10"},
+		{name: "earlier this week", date: kstDate(time.October, 20), want: "понедельник, 20.10"},
+		{name: "next month", date: time.Date(2025, time.November, 3, 9, 0, 0, 0, testNow.Location()), want: "понедельник, 03.11"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dayLabel(tt.date, testNow); got != tt.want {
+				t.Errorf("dayLabel(%s) = %q, want %q", tt.date.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMenuPublished(t *testing.T) {
+	tests := []struct {
+		name string
+		date time.Time
+		now  time.Time
+		want bool
+	}{
+		{name: "today", date: testNow, now: testNow, want: true},
+		{name: "monday of this week", date: kstDate(time.October, 20), now: testNow, want: true},
+		{name: "friday of this week", date: kstDate(time.October, 24), now: testNow, want: true},
+		{name: "saturday", date: kstDate(time.October, 25), now: testNow, want: false},
+		{name: "next monday", date: kstDate(time.October, 27), now: testNow, want: false},
+		{name: "last friday", date: kstDate(time.October, 17), now: testNow, want: false},
+		{name: "tomorrow on friday", date: kstDate(time.October, 25), now: kstDate(time.October, 24), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := menuPublished(tt.date, tt.now); got != tt.want {
+				t.Errorf("menuPublished(%s, now %s) = %v, want %v", tt.date.Format("2006-01-02"), tt.now.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnpublishedDaysAreNotFetched(t *testing.T) {
+	const chatID = 300
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		command string
+		text    string
+	}{
+		{name: "tomorrow on friday", now: kstDate(time.October, 24), command: "/tomorrow"},
+		{name: "cafeteria on saturday", now: testNow, command: "/peony суббота"},
+		{name: "free text on sunday", now: kstDate(time.October, 26), text: "что сегодня?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, messenger, _ := newTestBot(t)
+			b.clock = fixedClock{now: tt.now}
+			menus := b.menuService.(*stubMenuService)
+
+			var err error
+			if tt.command != "" {
+				err = b.handleCommand(commandUpdate(chatID, tt.command))
+			} else {
+				err = b.handleText(chatID, 0, tt.text)
+			}
+			if err != nil {
+				t.Fatalf("handle %q%q error: %v", tt.command, tt.text, err)
+			}
+
+			if got := lastMessage(t, messenger, chatID).Text; !strings.Contains(got, "ещё не опубликовано") {
+				t.Errorf("reply = %q, want the menu to be reported as not published", got)
+			}
+			if len(menus.fetched) != 0 {
+				t.Errorf("menus fetched for %v, want no fetch for an unpublished day", menus.fetched)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const notChatAdminMessage = "⛔ Только администраторы группы могут управлять подпиской и настройками."
+
+var errNotChatAdmin = errors.New("user is not a chat administrator")
+
+var (
+	managementCommands  = []string{"subscribe", "unsubscribe", "watch", "unwatch", "format", "prefs"}
+	managementCallbacks = []string{"subscribe", "unsubscribe_confirm", "unsubscribe_yes", "unsubscribe_cancel", "format_text", "format_image"}
+)
+
+func isGroupChat(chat *tgbotapi.Chat) bool {
+	return chat != nil && (chat.IsGroup() || chat.IsSuperGroup())
+}
+
+// isAddressedToOtherBot reports commands like /status@OtherBot that other bots in a group should answer.
+func (b *Bot) isAddressedToOtherBot(message *tgbotapi.Message) bool {
+	_, target, found := strings.Cut(message.CommandWithAt(), "@")
+	return found && !strings.EqualFold(target, b.messenger.Username())
+}
+
+// isAddressedToBot reports whether a plain group message mentions the bot or replies to it.
+func (b *Bot) isAddressedToBot(message *tgbotapi.Message) bool {
+	username := b.messenger.Username()
+	if username == "" {
+		return false
+	}
+
+	if strings.Contains(strings.ToLower(message.Text), "@"+strings.ToLower(username)) {
+		return true
+	}
+
+	reply := message.ReplyToMessage
+	return reply != nil && reply.From != nil && strings.EqualFold(reply.From.UserName, username)
+}
+
+// canManageChat allows everyone in private chats and only administrators in groups.
+func (b *Bot) canManageChat(chat *tgbotapi.Chat, user *tgbotapi.User, senderChat *tgbotapi.Chat) (bool, error) {
+	if !isGroupChat(chat) {
+		return true, nil
+	}
+
+	// Anonymous administrators post on behalf of the group itself.
+	if senderChat != nil && senderChat.ID == chat.ID {
+		return true, nil
+	}
+
+	if user == nil {
+		return false, nil
+	}
+
+	member, err := b.messenger.ChatMember(chat.ID, user.ID)
+	if err != nil {
+		return false, fmt.Errorf("get chat member %d in chat %d: %w", user.ID, chat.ID, err)
+	}
+
+	return member.IsCreator() || member.IsAdministrator(), nil
+}
+
+func (b *Bot) authorizeCommand(message *tgbotapi.Message) error {
+	if !slices.Contains(managementCommands, message.Command()) {
+		return nil
+	}
+
+	allowed, err := b.canManageChat(message.Chat, message.From, message.SenderChat)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errNotChatAdmin
+	}
+	return nil
+}
+
+func (b *Bot) authorizeCallback(callback *tgbotapi.CallbackQuery) error {
+	if !slices.Contains(managementCallbacks, callback.Data) {
+		return nil
+	}
+
+	allowed, err := b.canManageChat(callback.Message.Chat, callback.From, nil)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errNotChatAdmin
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/bot/bottest"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/telegram"
+)
+
+const (
+	groupChatID = -1001
+	groupUserID = 42
+)
+
+func groupCommandUpdate(text string, threadID int) telegram.Update {
+	update := commandUpdate(groupChatID, text)
+	update.Message.Chat.Type = "supergroup"
+	update.Message.From = &tgbotapi.User{ID: groupUserID}
+	update.MessageThreadID = threadID
+	return update
+}
+
+func TestGroupSubscriptionRequiresAdmin(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		wantText   string
+		wantActive bool
+	}{
+		{name: "member", status: "member", wantText: "Только администраторы", wantActive: false},
+		{name: "administrator", status: "administrator", wantText: "Вы подписаны", wantActive: true},
+		{name: "creator", status: "creator", wantText: "Вы подписаны", wantActive: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, messenger, repo := newTestBot(t)
+			messenger.SetMemberStatus(groupChatID, groupUserID, tt.status)
+
+			b.HandleUpdate(groupCommandUpdate("/subscribe", 0))
+
+			if got := lastMessage(t, messenger, groupChatID).Text; !strings.Contains(got, tt.wantText) {
+				t.Errorf("reply = %q, want it to contain %q", got, tt.wantText)
+			}
+
+			active, err := repo.GetStatus(groupChatID)
+			if err != nil {
+				t.Fatalf("GetStatus() error: %v", err)
+			}
+			if active != tt.wantActive {
+				t.Errorf("GetStatus() = %v, want %v", active, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestGroupAnonymousAdminCanSubscribe(t *testing.T) {
+	b, messenger, repo := newTestBot(t)
+
+	update := groupCommandUpdate("/subscribe", 0)
+	update.Message.From = &tgbotapi.User{ID: 1087968824, UserName: "GroupAnonymousBot"}
+	update.Message.SenderChat = &tgbotapi.Chat{ID: groupChatID, Type: "supergroup"}
+	b.HandleUpdate(update)
+
+	if got := lastMessage(t, messenger, groupChatID).Text; !strings.Contains(got, "Вы подписаны") {
+		t.Errorf("reply = %q, want subscription confirmation", got)
+	}
+	if active, _ := repo.GetStatus(groupChatID); !active {
+		t.Error("anonymous admin subscription was not saved")
+	}
+}
+
+func TestGroupCallbackRequiresAdmin(t *testing.T) {
+	b, messenger, repo := newTestBot(t)
+
+	callback := callbackQuery(groupChatID, "subscribe")
+	callback.Message.Chat.Type = "group"
+	callback.From = &tgbotapi.User{ID: groupUserID}
+	b.HandleUpdate(telegram.Update{Update: tgbotapi.Update{CallbackQuery: callback}})
+
+	callbacks := messenger.Callbacks()
+	if len(callbacks) != 1 || callbacks[0].Text != notChatAdminMessage {
+		t.Fatalf("callbacks = %+v, want one answer with the admin notice", callbacks)
+	}
+	if active, _ := repo.GetStatus(groupChatID); active {
+		t.Error("non-admin callback subscribed the group")
+	}
+}
+
+func TestGroupIgnoresMessagesForOthers(t *testing.T) {
+	tests := []struct {
+		name      string
+		update    func() telegram.Update
+		wantReply bool
+	}{
+		{
+			name:   "command for another bot",
+			update: func() telegram.Update { return groupCommandUpdate("/status@OtherBot", 0) },
+		},
+		{
+			name:      "command for this bot",
+			update:    func() telegram.Update { return groupCommandUpdate("/status@"+bottest.BotUsername, 0) },
+			wantReply: true,
+		},
+		{
+			name:      "bare command",
+			update:    func() telegram.Update { return groupCommandUpdate("/today", 0) },
+			wantReply: true,
+		},
+		{
+			name: "plain chatter",
+			update: func() telegram.Update {
+				update := groupCommandUpdate("что на обед завтра?", 0)
+				update.Message.Entities = nil
+				return update
+			},
+		},
+		{
+			name: "mention",
+			update: func() telegram.Update {
+				update := groupCommandUpdate("@"+bottest.BotUsername+" что на обед завтра?", 0)
+				update.Message.Entities = nil
+				return update
+			},
+			wantReply: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, messenger, _ := newTestBot(t)
+
+			b.HandleUpdate(tt.update())
+
+			if got := len(messenger.MessagesTo(groupChatID)) > 0; got != tt.wantReply {
+				t.Errorf("replied = %v, want %v", got, tt.wantReply)
+			}
+		})
+	}
+}
+
+func TestDispatchDailyMenuToForumTopic(t *testing.T) {
+	const threadID = 7
+
+	b, messenger, repo := newTestBot(t)
+	messenger.SetMemberStatus(groupChatID, groupUserID, "administrator")
+	mustSubscribe(t, repo, 1)
+
+	b.HandleUpdate(groupCommandUpdate("/subscribe", threadID))
+	messenger.Reset()
+
+	if err := b.dispatchDailyMenu(); err != nil {
+		t.Fatalf("dispatchDailyMenu() error: %v", err)
+	}
+
+	threadMessages := messenger.ThreadMessages()
+	if len(threadMessages) != 1 {
+		t.Fatalf("thread messages = %d, want 1", len(threadMessages))
+	}
+	if got := threadMessages[0]; bottest.ChatID(got.Message) != groupChatID || got.ThreadID != threadID {
+		t.Errorf("thread message went to chat %d topic %d, want chat %d topic %d",
+			bottest.ChatID(got.Message), got.ThreadID, groupChatID, threadID)
+	}
+	if len(messenger.MessagesTo(1)) != 1 {
+		t.Errorf("private subscriber did not receive the menu")
+	}
+}
+
+func TestGroupRepliesStayInForumTopic(t *testing.T) {
+	const threadID = 7
+
+	tests := []struct {
+		name   string
+		member bool
+		update func() telegram.Update
+	}{
+		{name: "status", update: func() telegram.Update { return groupCommandUpdate("/status", threadID) }},
+		{name: "menu", update: func() telegram.Update { return groupCommandUpdate("/today", threadID) }},
+		{name: "week", update: func() telegram.Update { return groupCommandUpdate("/week", threadID) }},
+		{name: "watch", update: func() telegram.Update { return groupCommandUpdate("/watch 돈까스", threadID) }},
+		{name: "not an admin", member: true, update: func() telegram.Update { return groupCommandUpdate("/unsubscribe", threadID) }},
+		{
+			name: "mention",
+			update: func() telegram.Update {
+				update := groupCommandUpdate("@"+bottest.BotUsername+" что в пиони?", threadID)
+				update.Message.Entities = nil
+				return update
+			},
+		},
+		{
+			name: "button",
+			update: func() telegram.Update {
+				callback := callbackQuery(groupChatID, "unsubscribe_cancel")
+				callback.Message.Chat.Type = "supergroup"
+				callback.From = &tgbotapi.User{ID: groupUserID}
+				return telegram.Update{Update: tgbotapi.Update{CallbackQuery: callback}, MessageThreadID: threadID}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, messenger, _ := newTestBot(t)
+			if !tt.member {
+				messenger.SetMemberStatus(groupChatID, groupUserID, "administrator")
+			}
+
+			b.HandleUpdate(tt.update())
+
+			replies := len(messenger.MessagesTo(groupChatID))
+			if replies == 0 {
+				t.Fatal("no reply sent")
+			}
+			threadMessages := messenger.ThreadMessages()
+			if len(threadMessages) != replies {
+				t.Fatalf("%d of %d replies went to a topic, want all", len(threadMessages), replies)
+			}
+			for _, got := range threadMessages {
+				if got.ThreadID != threadID {
+					t.Errorf("reply went to topic %d, want %d", got.ThreadID, threadID)
+				}
+			}
+		})
+	}
+}
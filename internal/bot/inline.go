@@ -0,0 +1,157 @@
+package bot
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+const (
+	inlineCacheSeconds  = 300
+	inlineSpicinessFrom = 3
+)
+
+var (
+	inlineTomorrowWords = []string{"tomorrow", "завтра"}
+	inlinePeonyWords    = []string{"peony", "пиони", "верх", "верхняя"}
+	inlineAzileaWords   = []string{"azilea", "азилия", "азалия", "низ", "нижняя"}
+	inlineSpicyWords    = []string{"spicy", "hot", "острое", "остро", "острые"}
+)
+
+type inlineRequest struct {
+	tomorrow  bool
+	spicy     bool
+	cafeteria menu.Cafeteria
+}
+
+func parseInlineQuery(query string) inlineRequest {
+	var request inlineRequest
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		switch {
+		case slices.Contains(inlineTomorrowWords, word):
+			request.tomorrow = true
+		case slices.Contains(inlineSpicyWords, word):
+			request.spicy = true
+		case slices.Contains(inlinePeonyWords, word):
+			request.cafeteria = menu.PEONY
+		case slices.Contains(inlineAzileaWords, word):
+			request.cafeteria = menu.AZILEA
+		}
+	}
+	return request
+}
+
+func (b *Bot) handleInlineQuery(query *tgbotapi.InlineQuery) error {
+	results, err := b.buildInlineResults(parseInlineQuery(query.Query))
+	if err != nil {
+		results = []interface{}{
+			tgbotapi.NewInlineQueryResultArticle("error", "Не удалось получить меню", "Не удалось получить меню. Попробуйте позже."),
+		}
+	}
+
+	config := tgbotapi.InlineConfig{
+		InlineQueryID: query.ID,
+		Results:       results,
+		CacheTime:     inlineCacheSeconds,
+	}
+	if requestErr := b.messenger.Request(config); requestErr != nil {
+		return fmt.Errorf("answer inline query: %w", requestErr)
+	}
+
+	return err
+}
+
+func (b *Bot) buildInlineResults(request inlineRequest) ([]interface{}, error) {
+	now := b.clock.Now()
+	date := now
+	if request.tomorrow {
+		date = now.AddDate(0, 0, 1)
+	}
+
+	label := dayLabel(date, now)
+	idPrefix := date.Format("20060102")
+	if !menuPublished(date, now) {
+		text := notPublishedText(label)
+		return []interface{}{inlineArticle(idPrefix+"-unpublished", "🍽️ Меню на "+label, text)}, nil
+	}
+
+	peony, azilea, err := b.menuService.GetMenusForDate(b.context(), date)
+	if err != nil {
+		return nil, fmt.Errorf("get menus: %w", err)
+	}
+
+	menus := map[menu.Cafeteria]*menu.Menu{menu.PEONY: peony, menu.AZILEA: azilea}
+
+	if request.spicy {
+		return []interface{}{
+			inlineArticle(idPrefix+"-spicy", "🌶 Острые блюда на "+label, formatSpicyDishes(label, menus)),
+		}, nil
+	}
+
+	if request.cafeteria != "" {
+		cafeteriaMenu := menus[request.cafeteria]
+		return []interface{}{
+			inlineArticle(idPrefix+"-"+string(request.cafeteria),
+				cafeteriaTitle(request.cafeteria)+" — меню на "+label,
+				formatCafeteriaMenu(label, request.cafeteria, cafeteriaMenu)),
+		}, nil
+	}
+
+	return []interface{}{
+		inlineArticle(idPrefix+"-all", "🍽️ Меню на "+label, formatMenus(label, peony, azilea)),
+		inlineArticle(idPrefix+"-peony", cafeteriaTitle(menu.PEONY)+" — меню на "+label, formatCafeteriaMenu(label, menu.PEONY, peony)),
+		inlineArticle(idPrefix+"-azilea", cafeteriaTitle(menu.AZILEA)+" — меню на "+label, formatCafeteriaMenu(label, menu.AZILEA, azilea)),
+	}, nil
+}
+
+func inlineArticle(id, title, text string) tgbotapi.InlineQueryResultArticle {
+	article := tgbotapi.NewInlineQueryResultArticleHTML(id, title, splitMessage(text, telegramMessageLimit)[0])
+	article.Description = previewLine(text)
+	return article
+}
+
+// previewLine shows the first dishes of a formatted menu under the inline result title.
+func previewLine(text string) string {
+	var dishes []string
+	for _, line := range strings.Split(text, "\n") {
+		if _, dish, found := strings.Cut(line, ") <b>"); found {
+			name, _, _ := strings.Cut(dish, "</b>")
+			dishes = append(dishes, html.UnescapeString(name))
+		}
+		if len(dishes) == 3 {
+			break
+		}
+	}
+	return strings.Join(dishes, ", ")
+}
+
+func formatSpicyDishes(dayLabel string, menus map[menu.Cafeteria]*menu.Menu) string {
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("🌶 <b>Острые блюда на %s</b>\n", html.EscapeString(dayLabel)))
+
+	found := false
+	for _, cafeteria := range []menu.Cafeteria{menu.PEONY, menu.AZILEA} {
+		cafeteriaMenu := menus[cafeteria]
+		if cafeteriaMenu == nil {
+			continue
+		}
+		for _, item := range cafeteriaMenu.Items {
+			if item.Spiciness < inlineSpicinessFrom {
+				continue
+			}
+			found = true
+			message.WriteString(fmt.Sprintf("• %s: <b>%s</b> %s\n", cafeteriaTitle(cafeteria), html.EscapeString(item.Name), spicinessIcons(item.Spiciness)))
+		}
+	}
+
+	if !found {
+		message.WriteString("Ничего острого не нашлось\n")
+	}
+
+	return message.String()
+}
@@ -0,0 +1,182 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/telegram"
+)
+
+const updatesRetryDelay = 3 * time.Second
+
+// Messenger is the part of the Telegram Bot API the bot depends on.
+// It is satisfied by the tgbotapi adapter in production and by bottest.FakeMessenger in tests.
+type Messenger interface {
+	// Username is the bot's own username, used to recognise commands addressed to it.
+	Username() string
+	// Send delivers a new message, photo or other sendable config.
+	Send(message tgbotapi.Chattable) (tgbotapi.Message, error)
+	// SendToThread delivers a text message into a forum topic.
+	SendToThread(message tgbotapi.MessageConfig, threadID int) (tgbotapi.Message, error)
+	// SendPhotoToThread uploads a photo into a forum topic.
+	SendPhotoToThread(photo tgbotapi.PhotoConfig, threadID int) (tgbotapi.Message, error)
+	// Edit replaces the text of a previously sent message.
+	Edit(edit tgbotapi.EditMessageTextConfig) error
+	// AnswerCallback acknowledges an inline button press.
+	AnswerCallback(callbackID, text string) error
+	// ChatMember reports the membership status of a user in a group chat.
+	ChatMember(chatID, userID int64) (tgbotapi.ChatMember, error)
+	// Request performs an API call whose result the bot does not inspect.
+	Request(config tgbotapi.Chattable) error
+	// MakeRequest calls an API method with raw parameters not covered by tgbotapi configs.
+	MakeRequest(method string, params tgbotapi.Params) error
+	// Updates starts long polling and returns the channel of incoming updates.
+	Updates(config tgbotapi.UpdateConfig) <-chan telegram.Update
+	// StopUpdates stops long polling started by Updates.
+	StopUpdates()
+}
+
+type telegramMessenger struct {
+	api      *tgbotapi.BotAPI
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewTelegramMessenger(api *tgbotapi.BotAPI) Messenger {
+	return &telegramMessenger{
+		api:  api,
+		stop: make(chan struct{}),
+	}
+}
+
+func (m *telegramMessenger) Username() string {
+	return m.api.Self.UserName
+}
+
+func (m *telegramMessenger) Send(message tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return m.api.Send(message)
+}
+
+// SendToThread builds the request by hand because tgbotapi.MessageConfig has no message_thread_id.
+func (m *telegramMessenger) SendToThread(message tgbotapi.MessageConfig, threadID int) (tgbotapi.Message, error) {
+	params := tgbotapi.Params{}
+	params.AddFirstValid("chat_id", message.ChatID, message.ChannelUsername)
+	params.AddNonZero("message_thread_id", threadID)
+	params["text"] = message.Text
+	params.AddNonEmpty("parse_mode", message.ParseMode)
+	params.AddBool("disable_web_page_preview", message.DisableWebPagePreview)
+	params.AddBool("disable_notification", message.DisableNotification)
+	if err := params.AddInterface("reply_markup", message.ReplyMarkup); err != nil {
+		return tgbotapi.Message{}, fmt.Errorf("encode reply markup: %w", err)
+	}
+
+	resp, err := m.api.MakeRequest("sendMessage", params)
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+	return decodeMessage(resp)
+}
+
+func (m *telegramMessenger) SendPhotoToThread(photo tgbotapi.PhotoConfig, threadID int) (tgbotapi.Message, error) {
+	params := tgbotapi.Params{}
+	params.AddFirstValid("chat_id", photo.ChatID, photo.ChannelUsername)
+	params.AddNonZero("message_thread_id", threadID)
+	params.AddNonEmpty("caption", photo.Caption)
+	params.AddNonEmpty("parse_mode", photo.ParseMode)
+	params.AddBool("disable_notification", photo.DisableNotification)
+	if err := params.AddInterface("reply_markup", photo.ReplyMarkup); err != nil {
+		return tgbotapi.Message{}, fmt.Errorf("encode reply markup: %w", err)
+	}
+
+	resp, err := m.api.UploadFiles("sendPhoto", params, []tgbotapi.RequestFile{{Name: "photo", Data: photo.File}})
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+	return decodeMessage(resp)
+}
+
+func decodeMessage(resp *tgbotapi.APIResponse) (tgbotapi.Message, error) {
+	var sent tgbotapi.Message
+	if err := json.Unmarshal(resp.Result, &sent); err != nil {
+		return tgbotapi.Message{}, fmt.Errorf("decode sent message: %w", err)
+	}
+	return sent, nil
+}
+
+func (m *telegramMessenger) Edit(edit tgbotapi.EditMessageTextConfig) error {
+	_, err := m.api.Send(edit)
+	return err
+}
+
+func (m *telegramMessenger) AnswerCallback(callbackID, text string) error {
+	_, err := m.api.Request(tgbotapi.NewCallback(callbackID, text))
+	return err
+}
+
+func (m *telegramMessenger) ChatMember(chatID, userID int64) (tgbotapi.ChatMember, error) {
+	return m.api.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chatID, UserID: userID},
+	})
+}
+
+func (m *telegramMessenger) Request(config tgbotapi.Chattable) error {
+	_, err := m.api.Request(config)
+	return err
+}
+
+func (m *telegramMessenger) MakeRequest(method string, params tgbotapi.Params) error {
+	_, err := m.api.MakeRequest(method, params)
+	return err
+}
+
+// Updates mirrors tgbotapi's GetUpdatesChan but decodes into Update to keep forum topic fields.
+func (m *telegramMessenger) Updates(config tgbotapi.UpdateConfig) <-chan telegram.Update {
+	updates := make(chan telegram.Update, m.api.Buffer)
+
+	go func() {
+		defer close(updates)
+
+		for {
+			select {
+			case <-m.stop:
+				return
+			default:
+			}
+
+			resp, err := m.api.Request(config)
+			if err != nil {
+				slog.Error("Failed to get updates, retrying", "error", err, "delay", updatesRetryDelay)
+				select {
+				case <-m.stop:
+					return
+				case <-time.After(updatesRetryDelay):
+				}
+				continue
+			}
+
+			var batch []telegram.Update
+			if err := json.Unmarshal(resp.Result, &batch); err != nil {
+				slog.Error("Failed to decode updates", "error", err)
+				continue
+			}
+
+			for _, update := range batch {
+				if update.UpdateID >= config.Offset {
+					config.Offset = update.UpdateID + 1
+					updates <- update
+				}
+			}
+		}
+	}()
+
+	return updates
+}
+
+func (m *telegramMessenger) StopUpdates() {
+	m.stopOnce.Do(func() { close(m.stop) })
+}
@@ -0,0 +1,194 @@
+package bot
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/compare"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+var (
+	prefsResetWords      = []string{"сброс", "reset"}
+	prefsVegetarianWords = []string{"вегетарианец", "вегетарианка", "vegetarian"}
+	prefsSpicinessWords  = []string{"острота", "spicy"}
+
+	prefsFoodWords = map[string]menu.DietTag{
+		"свинины": menu.DietPork, "свинина": menu.DietPork, "pork": menu.DietPork,
+		"говядины": menu.DietBeef, "говядина": menu.DietBeef, "beef": menu.DietBeef,
+		"курицы": menu.DietChicken, "курица": menu.DietChicken, "chicken": menu.DietChicken,
+		"рыбы": menu.DietSeafood, "рыба": menu.DietSeafood, "морепродуктов": menu.DietSeafood, "seafood": menu.DietSeafood, "fish": menu.DietSeafood,
+	}
+
+	dietNames = map[menu.DietTag]string{
+		menu.DietPork:    "свинина",
+		menu.DietBeef:    "говядина",
+		menu.DietChicken: "курица",
+		menu.DietSeafood: "рыба и морепродукты",
+	}
+)
+
+const prefsUsage = `Настройки влияют на совет, в какую столовую идти:
+/prefs острота 2 — не предлагать блюда острее 🌶🌶
+/prefs без свинины — избегать свинины (также говядины, курицы, рыбы)
+/prefs можно свинины — снова учитывать свинину
+/prefs вегетарианец — избегать мяса и рыбы
+/prefs сброс — сбросить настройки
+Любимые блюда берутся из /watch.`
+
+// personalNote holds the per-chat lines around the shared daily menu.
+type personalNote struct {
+	lead   string
+	footer string
+}
+
+func (n personalNote) wrap(text string) string {
+	if n.lead != "" {
+		text = n.lead + "\n\n" + text
+	}
+	if n.footer != "" {
+		text += "\n\n" + n.footer
+	}
+	return text
+}
+
+// personalNotes leads every subscriber's menu with the cafeteria advice and ends it with their dish recommendation.
+func (b *Bot) personalNotes(subscribers []Subscription) map[int64]personalNote {
+	notes := make(map[int64]personalNote, len(subscribers))
+
+	peony, azilea, err := b.menuService.GetMenus()
+	if err != nil {
+		slog.Error("Failed to load menus for personal notes", "error", err)
+		return notes
+	}
+	peony, azilea = b.withRatings(peony), b.withRatings(azilea)
+
+	for _, subscriber := range subscribers {
+		notes[subscriber.ChatID] = personalNote{lead: b.comparisonLead(subscriber.ChatID, peony, azilea)}
+	}
+	for chatID, footer := range b.recommendationNotes(subscribers, peony, azilea) {
+		note := notes[chatID]
+		note.footer = footer
+		notes[chatID] = note
+	}
+
+	return notes
+}
+
+// comparisonLead advises which cafeteria suits the chat; menus should already carry ratings.
+// Without stored preferences the advice falls back to the defaults.
+func (b *Bot) comparisonLead(chatID int64, peony, azilea *menu.Menu) string {
+	prefs := compare.DefaultPreferences()
+	if b.repo != nil {
+		stored, err := b.repo.GetPreferences(chatID)
+		if err != nil {
+			slog.Warn("Failed to load preferences", "chat_id", chatID, "error", err)
+		} else {
+			prefs = stored
+		}
+	}
+
+	return formatVerdict(compare.Compare(peony, azilea, prefs))
+}
+
+func formatVerdict(verdict compare.Verdict) string {
+	if verdict.Cafeteria == "" {
+		return ""
+	}
+	return fmt.Sprintf("🧭 <b>Лучше идти в %s</b> — %s", cafeteriaTitle(verdict.Cafeteria), verdict.Reason)
+}
+
+// handlePrefsCommand shows or changes the chat's preferences, e.g. "/prefs острота 2" or "/prefs без свинины".
+func (b *Bot) handlePrefsCommand(chatID int64, threadID int, args string) error {
+	prefs, err := b.repo.GetPreferences(chatID)
+	if err != nil {
+		slog.Error("Failed to load preferences", "chat_id", chatID, "error", err)
+		return b.SendMessage(chatID, threadID, "Не удалось загрузить настройки. Попробуйте позже.")
+	}
+
+	updated, ok := applyPrefs(prefs, args)
+	if !ok {
+		return b.SendMessage(chatID, threadID, formatPrefs(prefs)+"\n\n"+prefsUsage)
+	}
+
+	if err := b.repo.SavePreferences(chatID, updated); err != nil {
+		slog.Error("Failed to save preferences", "chat_id", chatID, "error", err)
+		return b.SendMessage(chatID, threadID, "Не удалось сохранить настройки. Попробуйте позже.")
+	}
+
+	return b.SendMessage(chatID, threadID, "✅ Настройки сохранены.\n"+formatPrefs(updated))
+}
+
+// applyPrefs reports false when args do not describe a change.
+func applyPrefs(prefs compare.Preferences, args string) (compare.Preferences, bool) {
+	words := strings.Fields(strings.ToLower(args))
+	if len(words) == 0 {
+		return prefs, false
+	}
+
+	switch {
+	case len(words) == 1 && slices.Contains(prefsResetWords, words[0]):
+		return compare.DefaultPreferences(), true
+
+	case len(words) == 1 && slices.Contains(prefsVegetarianWords, words[0]):
+		prefs.Avoid = slices.Clone(menu.MeatTags)
+		return prefs, true
+
+	case len(words) == 2 && slices.Contains(prefsSpicinessWords, words[0]):
+		level, err := strconv.Atoi(words[1])
+		if err != nil || level < 0 || level > compare.NoSpicinessLimit {
+			return prefs, false
+		}
+		prefs.MaxSpiciness = level
+		return prefs, true
+
+	case len(words) == 2 && (words[0] == "без" || words[0] == "no"):
+		tag, ok := prefsFoodWords[words[1]]
+		if !ok {
+			return prefs, false
+		}
+		if !slices.Contains(prefs.Avoid, tag) {
+			prefs.Avoid = append(slices.Clone(prefs.Avoid), tag)
+		}
+		return prefs, true
+
+	case len(words) == 2 && words[0] == "можно":
+		tag, ok := prefsFoodWords[words[1]]
+		if !ok {
+			return prefs, false
+		}
+		prefs.Avoid = slices.DeleteFunc(slices.Clone(prefs.Avoid), func(avoided menu.DietTag) bool { return avoided == tag })
+		return prefs, true
+	}
+
+	return prefs, false
+}
+
+func formatPrefs(prefs compare.Preferences) string {
+	spiciness := "без ограничений"
+	if prefs.MaxSpiciness < compare.NoSpicinessLimit {
+		spiciness = fmt.Sprintf("не больше %d", prefs.MaxSpiciness)
+		if prefs.MaxSpiciness == 0 {
+			spiciness = "совсем не острое"
+		}
+	}
+
+	avoid := "ничего"
+	if len(prefs.Avoid) > 0 {
+		names := make([]string, len(prefs.Avoid))
+		for i, tag := range prefs.Avoid {
+			names[i] = dietNames[tag]
+		}
+		avoid = strings.Join(names, ", ")
+	}
+
+	favourites := "нет (добавьте через /watch)"
+	if len(prefs.Favourites) > 0 {
+		favourites = strings.Join(prefs.Favourites, ", ")
+	}
+
+	return fmt.Sprintf("⚙️ Ваши настройки:\nОстрота: %s\nНе едите: %s\nЛюбимые блюда: %s", spiciness, avoid, favourites)
+}
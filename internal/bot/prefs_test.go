@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/compare"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+func TestApplyPrefs(t *testing.T) {
+	base := compare.Preferences{MaxSpiciness: 3, Avoid: []menu.DietTag{menu.DietPork}}
+
+	tests := []struct {
+		args   string
+		want   compare.Preferences
+		wantOK bool
+	}{
+		{args: "острота 1", want: compare.Preferences{MaxSpiciness: 1, Avoid: []menu.DietTag{menu.DietPork}}, wantOK: true},
+		{args: "без рыбы", want: compare.Preferences{MaxSpiciness: 3, Avoid: []menu.DietTag{menu.DietPork, menu.DietSeafood}}, wantOK: true},
+		{args: "без свинины", want: base, wantOK: true},
+		{args: "можно свинины", want: compare.Preferences{MaxSpiciness: 3, Avoid: []menu.DietTag{}}, wantOK: true},
+		{args: "вегетарианец", want: compare.Preferences{MaxSpiciness: 3, Avoid: menu.MeatTags}, wantOK: true},
+		{args: "сброс", want: compare.DefaultPreferences(), wantOK: true},
+		{args: ""},
+		{args: "острота 9"},
+		{args: "без брокколи"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			got, ok := applyPrefs(base, tt.args)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.MaxSpiciness != tt.want.MaxSpiciness || !slices.Equal(got.Avoid, tt.want.Avoid) {
+				t.Errorf("applyPrefs(%q) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+
+	if !slices.Equal(base.Avoid, []menu.DietTag{menu.DietPork}) {
+		t.Errorf("applyPrefs modified the original preferences: %v", base.Avoid)
+	}
+}
+
+func TestPrefsCommand(t *testing.T) {
+	const chatID = 100
+
+	b, messenger, repo := newTestBot(t)
+
+	for _, command := range []string{"/prefs острота 2", "/prefs без свинины", "/watch 된장국"} {
+		if err := b.handleCommand(commandUpdate(chatID, command)); err != nil {
+			t.Fatalf("handleCommand(%q) error: %v", command, err)
+		}
+	}
+
+	prefs, err := repo.GetPreferences(chatID)
+	if err != nil {
+		t.Fatalf("GetPreferences() error: %v", err)
+	}
+	if prefs.MaxSpiciness != 2 || !slices.Equal(prefs.Avoid, []menu.DietTag{menu.DietPork}) || !slices.Equal(prefs.Favourites, []string{"된장국"}) {
+		t.Fatalf("stored preferences = %+v", prefs)
+	}
+
+	if err := b.handleCommand(commandUpdate(chatID, "/prefs")); err != nil {
+		t.Fatalf("handleCommand() error: %v", err)
+	}
+	got := lastMessage(t, messenger, chatID).Text
+	for _, want := range []string{"не больше 2", "свинина", "된장국"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("/prefs reply misses %q:\n%s", want, got)
+		}
+	}
+
+	// Peony has a 🌶🌶🌶🌶 stew and pork cutlet, Azilea has the favourite soup.
+	if err := b.handleCommand(commandUpdate(chatID, "/today")); err != nil {
+		t.Fatalf("handleCommand() error: %v", err)
+	}
+	got = lastMessage(t, messenger, chatID).Text
+	if !strings.HasPrefix(got, "🧭 <b>Лучше идти в 🌺 Azilea</b> — в Azilea есть ваше любимое: 된장국") {
+		t.Errorf("/today does not lead with the advice:\n%s", got)
+	}
+}
+
+func TestDispatchDailyMenuLeadsWithAdvice(t *testing.T) {
+	b, messenger, repo := newTestBot(t)
+
+	mustSubscribe(t, repo, 1)
+	mustSubscribe(t, repo, 2)
+	if err := repo.SavePreferences(2, compare.Preferences{MaxSpiciness: 1}); err != nil {
+		t.Fatalf("SavePreferences() error: %v", err)
+	}
+
+	if err := b.dispatchDailyMenu(); err != nil {
+		t.Fatalf("dispatchDailyMenu error: %v", err)
+	}
+
+	// Without preferences both menus have two suitable dishes, so there is nothing to advise.
+	if got := lastMessage(t, messenger, 1).Text; strings.Contains(got, "Лучше идти") {
+		t.Errorf("chat 1 menu starts with %q, want no advice on a tie", strings.SplitN(got, "\n", 2)[0])
+	}
+	if got := lastMessage(t, messenger, 2).Text; !strings.HasPrefix(got, "🧭 <b>Лучше идти в 🌺 Azilea</b> — в Azilea меньше слишком острых блюд") {
+		t.Errorf("chat 2 menu starts with %q", strings.SplitN(got, "\n", 2)[0])
+	}
+}
@@ -0,0 +1,297 @@
+package bot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/rating"
+)
+
+const (
+	ratingCallbackPrefix = "rate:"
+	ratingButtonNameLen  = 20
+	topDishesLimit       = 10
+)
+
+var ratingCafeteriaCodes = map[menu.Cafeteria]string{
+	menu.PEONY:  "p",
+	menu.AZILEA: "a",
+}
+
+type RatingService interface {
+	Rate(ctx context.Context, cafeteria menu.Cafeteria, date time.Time, dishName, rater string, score int) (menu.DishRating, error)
+	WithRatings(cafeteriaMenu *menu.Menu) (*menu.Menu, error)
+	TopDishesThisMonth(limit int) ([]rating.DishScore, error)
+}
+
+// EnableRatings adds rating buttons under menu messages and the /top command.
+func (b *Bot) EnableRatings(service RatingService) {
+	b.ratings = service
+}
+
+// withRatings attaches dish ratings for display; a failed lookup only hides the scores.
+func (b *Bot) withRatings(cafeteriaMenu *menu.Menu) *menu.Menu {
+	if b.ratings == nil || cafeteriaMenu == nil {
+		return cafeteriaMenu
+	}
+
+	rated, err := b.ratings.WithRatings(cafeteriaMenu)
+	if err != nil {
+		slog.Warn("Failed to load dish ratings", "error", err)
+		return cafeteriaMenu
+	}
+	return rated
+}
+
+// ratedMenuKeyboard adds a 👍/👎/⭐ row per dish above the subscription buttons.
+// Dishes can only be rated once they were served, so future menus get the plain keyboard.
+func (b *Bot) ratedMenuKeyboard(date time.Time, peony, azilea *menu.Menu) tgbotapi.InlineKeyboardMarkup {
+	keyboard := menuKeyboard()
+	if b.ratings == nil || daysBetween(b.clock.Now(), date) > 0 {
+		return keyboard
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, section := range []struct {
+		cafeteria menu.Cafeteria
+		menu      *menu.Menu
+	}{{menu.PEONY, peony}, {menu.AZILEA, azilea}} {
+		if section.menu == nil || len(section.menu.Items) <= 1 {
+			continue
+		}
+		for i, item := range section.menu.Items {
+			data := func(score int) string {
+				return ratingCallbackData(date, section.cafeteria, i, item.Name, score)
+			}
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("👍 "+shortDishName(item.Name), data(rating.ScoreLike)),
+				tgbotapi.NewInlineKeyboardButtonData("👎", data(rating.ScoreDislike)),
+				tgbotapi.NewInlineKeyboardButtonData("⭐", data(0)),
+			))
+		}
+	}
+
+	keyboard.InlineKeyboard = append(rows, keyboard.InlineKeyboard...)
+	return keyboard
+}
+
+// ratingCallbackData encodes "rate:<date>:<cafeteria>:<dish index>:<dish key>:<score>"; score 0 asks for stars.
+// The key pins the dish itself, so a menu corrected after it was sent cannot shift votes to another dish.
+func ratingCallbackData(date time.Time, cafeteria menu.Cafeteria, index int, dishName string, score int) string {
+	return fmt.Sprintf("%s%s:%s:%d:%s:%d", ratingCallbackPrefix, date.Format("20060102"), ratingCafeteriaCodes[cafeteria], index, dishKey(dishName), score)
+}
+
+// dishKey is a short hash of a dish name that fits Telegram's 64-byte callback data.
+func dishKey(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:4])
+}
+
+type ratingCallback struct {
+	date      time.Time
+	cafeteria menu.Cafeteria
+	index     int
+	key       string
+	score     int
+}
+
+func parseRatingCallback(data string, location *time.Location) (ratingCallback, bool) {
+	fields := strings.Split(strings.TrimPrefix(data, ratingCallbackPrefix), ":")
+	if len(fields) != 5 {
+		return ratingCallback{}, false
+	}
+
+	date, err := time.ParseInLocation("20060102", fields[0], location)
+	if err != nil {
+		return ratingCallback{}, false
+	}
+
+	var cafeteria menu.Cafeteria
+	for candidate, code := range ratingCafeteriaCodes {
+		if code == fields[1] {
+			cafeteria = candidate
+		}
+	}
+
+	index, indexErr := strconv.Atoi(fields[2])
+	score, scoreErr := strconv.Atoi(fields[4])
+	if cafeteria == "" || indexErr != nil || scoreErr != nil || index < 0 || score < 0 || score > 5 || fields[3] == "" {
+		return ratingCallback{}, false
+	}
+
+	return ratingCallback{date: date, cafeteria: cafeteria, index: index, key: fields[3], score: score}, true
+}
+
+// ratedDish finds the dish a callback points at, preferring its original position.
+func ratedDish(cafeteriaMenu *menu.Menu, request ratingCallback) (*menu.MenuItem, int) {
+	if cafeteriaMenu == nil {
+		return nil, 0
+	}
+	if request.index < len(cafeteriaMenu.Items) && dishKey(cafeteriaMenu.Items[request.index].Name) == request.key {
+		return cafeteriaMenu.Items[request.index], request.index
+	}
+	for i, item := range cafeteriaMenu.Items {
+		if dishKey(item.Name) == request.key {
+			return item, i
+		}
+	}
+	return nil, 0
+}
+
+// handleRatingCallback stores a rating and returns the text of the callback answer.
+// Anyone in a group may rate, so unlike other callbacks it is not restricted to admins.
+func (b *Bot) handleRatingCallback(callback *tgbotapi.CallbackQuery, threadID int) (string, error) {
+	if b.ratings == nil {
+		return "Оценки блюд отключены", nil
+	}
+
+	request, ok := parseRatingCallback(callback.Data, b.clock.Now().Location())
+	if !ok {
+		return "Неизвестное действие. Попробуйте еще раз.", nil
+	}
+
+	cafeteriaMenu, err := b.menuService.GetStoredMenuForDate(request.cafeteria, request.date)
+	if err != nil {
+		return "Не удалось сохранить оценку. Попробуйте позже.", fmt.Errorf("get %s menu: %w", request.cafeteria, err)
+	}
+	dish, index := ratedDish(cafeteriaMenu, request)
+	if dish == nil {
+		return "Этого блюда уже нет в меню", nil
+	}
+	request.index = index
+
+	if request.score == 0 {
+		return "", b.sendStarPicker(callback.Message.Chat.ID, threadID, request, dish.Name)
+	}
+
+	rater := fmt.Sprintf("tg:%d", callback.From.ID)
+	summary, err := b.ratings.Rate(b.context(), request.cafeteria, request.date, dish.Name, rater, request.score)
+	if errors.Is(err, rating.ErrUnknownDish) {
+		return "Этого блюда уже нет в меню", nil
+	}
+	if err != nil {
+		return "Не удалось сохранить оценку. Попробуйте позже.", fmt.Errorf("rate dish: %w", err)
+	}
+
+	return fmt.Sprintf("Спасибо! %s: %s", dish.Name, formatRating(summary)), nil
+}
+
+func (b *Bot) sendStarPicker(chatID int64, threadID int, request ratingCallback, dishName string) error {
+	buttons := make([]tgbotapi.InlineKeyboardButton, 0, 5)
+	for score := 1; score <= 5; score++ {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+			strconv.Itoa(score)+"⭐", ratingCallbackData(request.date, request.cafeteria, request.index, dishName, score)))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Оцените «%s»:", dishName))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(buttons)
+	_, err := b.send(msg, threadID)
+	return err
+}
+
+func (b *Bot) sendTopDishes(chatID int64, threadID int) error {
+	if b.ratings == nil {
+		return b.SendMessage(chatID, threadID, "Оценки блюд отключены.")
+	}
+
+	dishes, err := b.ratings.TopDishesThisMonth(topDishesLimit)
+	if err != nil {
+		slog.Error("Failed to load top dishes", "error", err)
+		return b.SendMessage(chatID, threadID, "Не удалось загрузить рейтинг. Попробуйте позже.")
+	}
+
+	if len(dishes) == 0 {
+		return b.SendMessage(chatID, threadID, "В этом месяце блюда еще не оценивали.\nОцените блюда кнопками под меню!")
+	}
+
+	var message strings.Builder
+	message.WriteString("🏆 <b>Лучшие блюда месяца</b>\n\n")
+	for i, dish := range dishes {
+		message.WriteString(fmt.Sprintf("%d) <b>%s</b> — %s\n    %s\n",
+			i+1, html.EscapeString(dish.DishName), formatRating(dish.DishRating), cafeteriaTitle(dish.Cafeteria)))
+	}
+
+	_, err = b.sendHTML(chatID, threadID, message.String(), nil)
+	return err
+}
+
+func formatRating(summary menu.DishRating) string {
+	return fmt.Sprintf("★%.1f (%d)", summary.Average, summary.Count)
+}
+
+func shortDishName(name string) string {
+	runes := []rune(name)
+	if len(runes) <= ratingButtonNameLen {
+		return name
+	}
+	return string(runes[:ratingButtonNameLen-1]) + "…"
+}
+
+type Recommender interface {
+	Recommend(raters []string, menus map[menu.Cafeteria]*menu.Menu) (map[string]rating.Recommendation, error)
+}
+
+// EnableRecommendations adds a personal dish suggestion to the daily menu of private subscribers.
+func (b *Bot) EnableRecommendations(recommender Recommender) {
+	b.recommender = recommender
+}
+
+// chatRater identifies a private chat as the user who rated dishes in it; group chats have negative IDs and no single rater.
+func chatRater(chatID int64) (string, bool) {
+	if chatID <= 0 {
+		return "", false
+	}
+	return fmt.Sprintf("tg:%d", chatID), true
+}
+
+// recommendationNotes returns the suggestion line for every subscriber that has one.
+// Recommendations are a bonus, so failures are logged and the menu goes out without them.
+func (b *Bot) recommendationNotes(subscribers []Subscription, peony, azilea *menu.Menu) map[int64]string {
+	notes := make(map[int64]string)
+	if b.recommender == nil {
+		return notes
+	}
+
+	chatsByRater := make(map[string]int64)
+	raters := make([]string, 0, len(subscribers))
+	for _, subscriber := range subscribers {
+		if rater, ok := chatRater(subscriber.ChatID); ok {
+			chatsByRater[rater] = subscriber.ChatID
+			raters = append(raters, rater)
+		}
+	}
+	if len(raters) == 0 {
+		return notes
+	}
+
+	recommendations, err := b.recommender.Recommend(raters, map[menu.Cafeteria]*menu.Menu{menu.PEONY: peony, menu.AZILEA: azilea})
+	if err != nil {
+		slog.Error("Failed to build recommendations", "error", err)
+		return notes
+	}
+
+	for rater, recommendation := range recommendations {
+		notes[chatsByRater[rater]] = formatRecommendation(recommendation)
+	}
+	return notes
+}
+
+func formatRecommendation(recommendation rating.Recommendation) string {
+	reason := fmt.Sprintf("похоже на «%s», которое вам понравилось", html.EscapeString(recommendation.LikedDish))
+	if recommendation.LikedDish == recommendation.DishName {
+		reason = "вам уже нравилось это блюдо"
+	}
+	return fmt.Sprintf("💡 Вам, скорее всего, понравится: <b>%s</b> в %s\n<i>%s</i>",
+		html.EscapeString(recommendation.DishName), cafeteriaTitle(recommendation.Cafeteria), reason)
+}
@@ -0,0 +1,327 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/bot/bottest"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database/databasetest"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/rating"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/telegram"
+)
+
+func enableTestRatings(b *Bot, repo *SubscriptionRepository) {
+	b.EnableRatings(rating.NewService(rating.NewRepository(repo.db), b.menuService, b.clock))
+}
+
+// rateData is the callback data of a rating button under testNow's menu.
+func rateData(cafeteria menu.Cafeteria, index int, dish string, score int) string {
+	return ratingCallbackData(testNow, cafeteria, index, dish, score)
+}
+
+func ratingUpdate(chatID, userID int64, data string) telegram.Update {
+	callback := callbackQuery(chatID, data)
+	callback.From = &tgbotapi.User{ID: userID}
+	return telegram.Update{Update: tgbotapi.Update{CallbackQuery: callback}}
+}
+
+func TestRatingKeyboard(t *testing.T) {
+	const chatID = 100
+
+	b, messenger, repo := newTestBot(t)
+
+	if err := b.handleCommand(commandUpdate(chatID, "/today")); err != nil {
+		t.Fatalf("handleCommand() error: %v", err)
+	}
+	if hasButton(lastMessage(t, messenger, chatID), rateData(menu.PEONY, 1, "돈까스", 5)) {
+		t.Fatalf("rating buttons shown while ratings are disabled")
+	}
+
+	enableTestRatings(b, repo)
+
+	tests := []struct {
+		command string
+		want    []string
+		notWant []string
+	}{
+		{
+			command: "/today",
+			want:    []string{rateData(menu.PEONY, 0, "김치찌개", 5), rateData(menu.PEONY, 1, "돈까스", 1), rateData(menu.AZILEA, 1, "된장국", 0), "unsubscribe_confirm"},
+		},
+		{
+			command: "/azilea",
+			want:    []string{rateData(menu.AZILEA, 0, "비빔밥", 5)},
+			notWant: []string{rateData(menu.PEONY, 0, "김치찌개", 5)},
+		},
+		{
+			command: "/tomorrow",
+			want:    []string{"unsubscribe_confirm"},
+			notWant: []string{ratingCallbackData(testNow.AddDate(0, 0, 1), menu.PEONY, 0, "김치찌개", 5)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			if err := b.handleCommand(commandUpdate(chatID, tt.command)); err != nil {
+				t.Fatalf("handleCommand() error: %v", err)
+			}
+			message := lastMessage(t, messenger, chatID)
+			for _, data := range tt.want {
+				if !hasButton(message, data) {
+					t.Errorf("missing button %q", data)
+				}
+			}
+			for _, data := range tt.notWant {
+				if hasButton(message, data) {
+					t.Errorf("unexpected button %q", data)
+				}
+			}
+		})
+	}
+}
+
+func TestRatingCallback(t *testing.T) {
+	const chatID = -100
+
+	b, messenger, repo := newTestBot(t)
+	enableTestRatings(b, repo)
+
+	// Ratings are not admin-only, so a regular group member can vote.
+	messenger.SetMemberStatus(chatID, 7, "member")
+	b.HandleUpdate(ratingUpdate(chatID, 7, rateData(menu.PEONY, 1, "돈까스", 5)))
+	b.HandleUpdate(ratingUpdate(chatID, 8, rateData(menu.PEONY, 1, "돈까스", 1)))
+
+	callbacks := messenger.Callbacks()
+	if len(callbacks) != 2 {
+		t.Fatalf("answered %d callbacks, want 2", len(callbacks))
+	}
+	if got := callbacks[1].Text; !strings.Contains(got, "돈까스") || !strings.Contains(got, "★3.0 (2)") {
+		t.Errorf("answer = %q, want dish with ★3.0 (2)", got)
+	}
+
+	if err := b.handleCommand(commandUpdate(chatID, "/today")); err != nil {
+		t.Fatalf("handleCommand() error: %v", err)
+	}
+	if got := lastMessage(t, messenger, chatID).Text; !strings.Contains(got, "<b>돈까스</b> 🐷 ★3.0 (2)") {
+		t.Errorf("menu does not show rating:\n%s", got)
+	}
+}
+
+func TestRatingCallbackStarPicker(t *testing.T) {
+	const chatID = 100
+
+	b, messenger, repo := newTestBot(t)
+	enableTestRatings(b, repo)
+
+	b.HandleUpdate(ratingUpdate(chatID, 7, rateData(menu.AZILEA, 0, "비빔밥", 0)))
+
+	message := lastMessage(t, messenger, chatID)
+	if !strings.Contains(message.Text, "비빔밥") {
+		t.Errorf("star picker text = %q, want dish name", message.Text)
+	}
+	for _, data := range []string{rateData(menu.AZILEA, 0, "비빔밥", 1), rateData(menu.AZILEA, 0, "비빔밥", 5)} {
+		if !hasButton(message, data) {
+			t.Errorf("star picker misses button %q", data)
+		}
+	}
+}
+
+func TestParseRatingCallback(t *testing.T) {
+	key := dishKey("돈까스")
+	tests := []struct {
+		data   string
+		want   ratingCallback
+		wantOK bool
+	}{
+		{data: "rate:20251022:p:1:" + key + ":5", want: ratingCallback{cafeteria: menu.PEONY, index: 1, key: key, score: 5}, wantOK: true},
+		{data: "rate:20251022:a:0:" + key + ":0", want: ratingCallback{cafeteria: menu.AZILEA, index: 0, key: key, score: 0}, wantOK: true},
+		{data: "rate:20251022:x:1:" + key + ":5"},
+		{data: "rate:20251022:p:1:" + key + ":6"},
+		{data: "rate:20251022:p:-1:" + key + ":5"},
+		{data: "rate:2025-10-22:p:1:" + key + ":5"},
+		{data: "rate:20251022:p:1::5"},
+		{data: "rate:20251022:p:1:5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			got, ok := parseRatingCallback(tt.data, testNow.Location())
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.cafeteria != tt.want.cafeteria || got.index != tt.want.index || got.key != tt.want.key || got.score != tt.want.score {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if got.date.Format("2006-01-02") != "2025-10-22" {
+				t.Errorf("date = %v, want 2025-10-22", got.date)
+			}
+		})
+	}
+}
+
+func TestTopCommand(t *testing.T) {
+	const chatID = 100
+
+	b, messenger, repo := newTestBot(t)
+	enableTestRatings(b, repo)
+
+	if err := b.handleCommand(commandUpdate(chatID, "/top")); err != nil {
+		t.Fatalf("handleCommand() error: %v", err)
+	}
+	if got := lastMessage(t, messenger, chatID).Text; !strings.Contains(got, "еще не оценивали") {
+		t.Errorf("empty top = %q, want no ratings notice", got)
+	}
+
+	b.HandleUpdate(ratingUpdate(chatID, 1, rateData(menu.AZILEA, 1, "된장국", 5)))
+	b.HandleUpdate(ratingUpdate(chatID, 2, rateData(menu.AZILEA, 1, "된장국", 4)))
+
+	if err := b.handleCommand(commandUpdate(chatID, "/top")); err != nil {
+		t.Fatalf("handleCommand() error: %v", err)
+	}
+	if got := lastMessage(t, messenger, chatID).Text; !strings.Contains(got, "1) <b>된장국</b> — ★4.5 (2)") {
+		t.Errorf("top = %q, want 된장국 first", got)
+	}
+}
+
+type stubRecommender struct {
+	raters          []string
+	recommendations map[string]rating.Recommendation
+}
+
+func (s *stubRecommender) Recommend(raters []string, menus map[menu.Cafeteria]*menu.Menu) (map[string]rating.Recommendation, error) {
+	s.raters = raters
+	return s.recommendations, nil
+}
+
+func TestDispatchDailyMenuWithRecommendations(t *testing.T) {
+	b, messenger, repo := newTestBot(t)
+
+	mustSubscribe(t, repo, 1)
+	mustSubscribe(t, repo, 2)
+	mustSubscribe(t, repo, -100)
+
+	recommender := &stubRecommender{recommendations: map[string]rating.Recommendation{
+		"tg:1": {Cafeteria: menu.PEONY, DishName: "돈까스", LikedDish: "제육볶음"},
+	}}
+	b.EnableRecommendations(recommender)
+
+	if err := b.dispatchDailyMenu(); err != nil {
+		t.Fatalf("dispatchDailyMenu error: %v", err)
+	}
+
+	if len(recommender.raters) != 2 {
+		t.Errorf("asked for raters %v, want only the two private chats", recommender.raters)
+	}
+
+	got := lastMessage(t, messenger, 1).Text
+	if !strings.Contains(got, "понравится: <b>돈까스</b> в 🌸 Peony") || !strings.Contains(got, "«제육볶음»") {
+		t.Errorf("chat 1 menu has no recommendation:\n%s", got)
+	}
+	for _, chatID := range []int64{2, -100} {
+		if got := lastMessage(t, messenger, chatID).Text; strings.Contains(got, "понравится") {
+			t.Errorf("chat %d got someone else's recommendation", chatID)
+		}
+	}
+}
+
+func TestMenuChangeKeepsRecommendation(t *testing.T) {
+	b, messenger, repo := newTestBot(t)
+
+	mustSubscribe(t, repo, 1)
+	mustSubscribe(t, repo, 2)
+	b.EnableRecommendations(&stubRecommender{recommendations: map[string]rating.Recommendation{
+		"tg:1": {Cafeteria: menu.PEONY, DishName: "돈까스", LikedDish: "제육볶음"},
+	}})
+
+	if err := b.dispatchDailyMenu(); err != nil {
+		t.Fatalf("dispatchDailyMenu error: %v", err)
+	}
+	previous := menu.NewMenuFromDishes([]string{"김치찌개", "제육볶음"}, &testNow)
+	b.HandleMenuChange(context.Background(), menu.PEONY, previous, menu.NewMenuFromDishes([]string{"김치찌개", "돈까스"}, &testNow))
+	b.Stop()
+
+	edits := make(map[int64]string)
+	for _, edit := range messenger.Edits() {
+		edits[edit.ChatID] = edit.Text
+	}
+	if got := edits[1]; !strings.Contains(got, "понравится: <b>돈까스</b> в 🌸 Peony") {
+		t.Errorf("chat 1 edited menu lost its recommendation:\n%s", got)
+	}
+	if got, ok := edits[2]; !ok || strings.Contains(got, "понравится") {
+		t.Errorf("chat 2 edited menu = %q, want it without a recommendation", got)
+	}
+}
+
+func TestRatingCallbackWithStoredMenus(t *testing.T) {
+	const chatID = 100
+
+	db := databasetest.New(t)
+	clock := fixedClock{now: testNow}
+	persistence := menu.NewMenuPersistenceService(menu.NewMenuRepository(db), clock)
+	// Callback dates are parsed at KST midnight, before 09:00 of the same day in UTC.
+	today := time.Date(2025, time.October, 22, 0, 0, 0, 0, testNow.Location())
+	yesterday := today.AddDate(0, 0, -1)
+	for date, dishes := range map[time.Time][]string{yesterday: {"제육볶음", "미역국"}, today: {"김치찌개", "돈까스"}} {
+		if err := persistence.SaveMenuForDate(menu.PEONY, menu.NewMenuFromDishes(dishes, nil), date); err != nil {
+			t.Fatalf("save menu: %v", err)
+		}
+	}
+
+	messenger := bottest.NewFakeMessenger()
+	repo := NewSubscriptionRepository(db)
+	b := NewBotWithMessenger(messenger, repo, menu.NewMenuService(persistence, nil))
+	b.clock = clock
+	enableTestRatings(b, repo)
+
+	b.HandleUpdate(ratingUpdate(chatID, 7, ratingCallbackData(today, menu.PEONY, 1, "돈까스", 5)))
+	// Buttons under yesterday's message still rate yesterday's dishes.
+	b.HandleUpdate(ratingUpdate(chatID, 7, ratingCallbackData(yesterday, menu.PEONY, 1, "미역국", 4)))
+	// A dish that is not on the day's menu is not swapped for whatever sits at its index.
+	b.HandleUpdate(ratingUpdate(chatID, 7, ratingCallbackData(today, menu.PEONY, 1, "미역국", 3)))
+	// Days without a stored menu are never fetched.
+	b.HandleUpdate(ratingUpdate(chatID, 7, ratingCallbackData(today.AddDate(0, 0, -3), menu.PEONY, 0, "김치찌개", 5)))
+
+	callbacks := messenger.Callbacks()
+	want := []string{"돈까스", "미역국", "уже нет в меню", "уже нет в меню"}
+	if len(callbacks) != len(want) {
+		t.Fatalf("answered %d callbacks, want %d", len(callbacks), len(want))
+	}
+	for i, text := range want {
+		if !strings.Contains(callbacks[i].Text, text) {
+			t.Errorf("answer %d = %q, want %q", i, callbacks[i].Text, text)
+		}
+	}
+
+	rows, err := db.Conn.Query("SELECT dish_name, substr(menu_date, 1, 10) FROM dish_ratings ORDER BY menu_date")
+	if err != nil {
+		t.Fatalf("query ratings: %v", err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var name, date string
+		if err := rows.Scan(&name, &date); err != nil {
+			t.Fatalf("scan rating: %v", err)
+		}
+		got = append(got, date+" "+name)
+	}
+	if strings.Join(got, ", ") != "2025-10-21 미역국, 2025-10-22 돈까스" {
+		t.Errorf("ratings = %v, want 미역국 on 2025-10-21 and 돈까스 on 2025-10-22", got)
+	}
+
+	var menus int
+	if err := db.Conn.QueryRow("SELECT COUNT(*) FROM menu").Scan(&menus); err != nil {
+		t.Fatalf("count menus: %v", err)
+	}
+	if menus != 2 {
+		t.Errorf("%d stored menus, want the 2 saved by the test", menus)
+	}
+}
@@ -0,0 +1,289 @@
+package bot
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf16"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+// telegramMessageLimit is the maximum length of a message text in Telegram.
+const telegramMessageLimit = 4096
+
+var dietaryIcons = map[menu.DietTag]string{
+	menu.DietPork:       "🐷",
+	menu.DietBeef:       "🐮",
+	menu.DietChicken:    "🐔",
+	menu.DietSeafood:    "🐟",
+	menu.DietVegetarian: "🥬",
+}
+
+func FormatMenuMessage(peony, azilea *menu.Menu) string {
+	return formatMenus(todayLabel, peony, azilea)
+}
+
+// formatMenus renders both cafeterias as Telegram HTML.
+func formatMenus(dayLabel string, peony, azilea *menu.Menu) string {
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("🍽️ <b>Меню на %s</b>\n\n", html.EscapeString(dayLabel)))
+
+	writeCafeteriaSection(&message, dayLabel, menu.PEONY, peony)
+	message.WriteString("\n")
+	writeCafeteriaSection(&message, dayLabel, menu.AZILEA, azilea)
+
+	return message.String()
+}
+
+func formatCafeteriaMenu(dayLabel string, cafeteria menu.Cafeteria, cafeteriaMenu *menu.Menu) string {
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("🍽️ <b>Меню на %s</b>\n\n", html.EscapeString(dayLabel)))
+	writeCafeteriaSection(&message, dayLabel, cafeteria, cafeteriaMenu)
+	return message.String()
+}
+
+func writeCafeteriaSection(message *strings.Builder, dayLabel string, cafeteria menu.Cafeteria, cafeteriaMenu *menu.Menu) {
+	message.WriteString(fmt.Sprintf("<b>%s</b>\n", cafeteriaHeading(cafeteria)))
+
+	if cafeteriaMenu == nil || len(cafeteriaMenu.Items) <= 1 {
+		if dayLabel == todayLabel {
+			message.WriteString("Сегодня выходной\n")
+		} else {
+			message.WriteString("Выходной\n")
+		}
+		return
+	}
+
+	for i, item := range cafeteriaMenu.Items {
+		message.WriteString(formatDish(i+1, item))
+	}
+
+	if tray := cafeteriaMenu.TrayNutrition(); tray != nil {
+		message.WriteString(formatTrayNutrition(*tray))
+	}
+}
+
+// formatTrayNutrition sums a full tray; the numbers are AI and table estimates, and the label says so.
+func formatTrayNutrition(tray menu.Nutrition) string {
+	return fmt.Sprintf("🍱 Поднос: ~%d ккал · Б %d г · Ж %d г · У %d г <i>(оценка)</i>\n",
+		tray.Calories, tray.Protein, tray.Fat, tray.Carbs)
+}
+
+// formatDish renders one dish; every line keeps its tags balanced so messages can be split between lines.
+func formatDish(position int, item *menu.MenuItem) string {
+	var line strings.Builder
+	line.WriteString(fmt.Sprintf("%d) <b>%s</b>", position, html.EscapeString(item.Name)))
+
+	if icons := spicinessIcons(item.Spiciness); icons != "" {
+		line.WriteString(" " + icons)
+	}
+	if badges := dietaryBadges(item); len(badges) > 0 {
+		line.WriteString(" " + strings.Join(badges, ""))
+	}
+	if item.Rating != nil && item.Rating.Count > 0 {
+		line.WriteString(" " + formatRating(*item.Rating))
+	}
+	if item.Nutrition != nil {
+		line.WriteString(fmt.Sprintf(" · ~%d ккал", item.Nutrition.Calories))
+	}
+	line.WriteString("\n")
+
+	if item.Description != "" && item.Description != "TODO" {
+		line.WriteString(fmt.Sprintf("    <i>%s</i>\n", html.EscapeString(item.Description)))
+	}
+
+	return line.String()
+}
+
+func spicinessIcons(spiciness int) string {
+	return strings.Repeat("🌶", min(max(spiciness, 0), 5))
+}
+
+func dietaryBadges(item *menu.MenuItem) []string {
+	var badges []string
+	for _, tag := range item.DietTags() {
+		badges = append(badges, dietaryIcons[tag])
+	}
+	return badges
+}
+
+func cafeteriaHeading(cafeteria menu.Cafeteria) string {
+	switch cafeteria {
+	case menu.PEONY:
+		return "🌸 Peony (верхняя столовая)"
+	case menu.AZILEA:
+		return "🌺 Azilea (нижняя столовая)"
+	default:
+		return html.EscapeString(string(cafeteria))
+	}
+}
+
+// splitMessage cuts text into parts that fit into one Telegram message, preferring line breaks.
+// Telegram counts length in UTF-16 code units, so emoji outside the BMP count twice.
+// Rendered lines never leave a tag open, so HTML stays valid in every part.
+func splitMessage(text string, limit int) []string {
+	if utf16Len(text) <= limit {
+		return []string{text}
+	}
+
+	var (
+		parts   []string
+		current strings.Builder
+		length  int
+	)
+
+	flush := func() {
+		if current.Len() > 0 {
+			parts = append(parts, strings.TrimRight(current.String(), "\n"))
+			current.Reset()
+			length = 0
+		}
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lineLength := utf16Len(line)
+
+		if length+lineLength > limit {
+			flush()
+		}
+
+		if lineLength > limit {
+			pieces := splitLine(line, limit)
+			parts = append(parts, pieces[:len(pieces)-1]...)
+			line = pieces[len(pieces)-1]
+			lineLength = utf16Len(line)
+		}
+
+		current.WriteString(line)
+		length += lineLength
+	}
+	flush()
+
+	return parts
+}
+
+// splitLine cuts a line longer than limit between tags and words, never inside a tag or an entity.
+// Tags still open at a cut are closed at the end of the piece and reopened at the start of the next one.
+func splitLine(line string, limit int) []string {
+	var (
+		pieces  []string
+		current strings.Builder
+		length  int
+		open    []string
+	)
+
+	closing := func() string {
+		var tags strings.Builder
+		for i := len(open) - 1; i >= 0; i-- {
+			tags.WriteString("</" + tagName(open[i]) + ">")
+		}
+		return tags.String()
+	}
+
+	add := func(token string) {
+		tokenLength := utf16Len(token)
+		reopened := utf16Len(strings.Join(open, ""))
+		if length+tokenLength+utf16Len(closing()) > limit && length > reopened {
+			pieces = append(pieces, current.String()+closing())
+			current.Reset()
+			current.WriteString(strings.Join(open, ""))
+			length = reopened
+		}
+		current.WriteString(token)
+		length += tokenLength
+	}
+
+	for _, token := range htmlTokens(line) {
+		switch {
+		case strings.HasPrefix(token, "</"):
+			add(token)
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+		case strings.HasPrefix(token, "<"):
+			add(token)
+			open = append(open, token)
+		case utf16Len(token)+utf16Len(strings.Join(open, ""))+utf16Len(closing()) > limit:
+			// A word longer than a whole message can only be cut between characters.
+			for _, r := range token {
+				add(string(r))
+			}
+		default:
+			add(token)
+		}
+	}
+
+	return append(pieces, current.String())
+}
+
+// htmlTokens splits rendered HTML into tags, entities and words with their trailing space.
+func htmlTokens(text string) []string {
+	var tokens []string
+	for text != "" {
+		end := 0
+		switch text[0] {
+		case '<':
+			end = strings.IndexByte(text, '>') + 1
+		case '&':
+			end = strings.IndexByte(text, ';') + 1
+		default:
+			end = strings.IndexAny(text, "<& ")
+			if end < 0 {
+				end = len(text)
+			} else if text[end] == ' ' {
+				end++
+			}
+		}
+		if end <= 0 {
+			end = len(text)
+		}
+
+		tokens = append(tokens, text[:end])
+		text = text[end:]
+	}
+	return tokens
+}
+
+// tagName returns "b" for "<b>" and "a" for `<a href="...">`.
+func tagName(tag string) string {
+	name := strings.Trim(tag, "</>")
+	if i := strings.IndexByte(name, ' '); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// utf16Len returns the length of s in UTF-16 code units, the unit of Telegram's message limit.
+func utf16Len(s string) int {
+	length := 0
+	for _, r := range s {
+		length += utf16.RuneLen(r)
+	}
+	return length
+}
+
+// sendHTML sends an HTML message, splitting it when it exceeds Telegram's limit.
+// The keyboard goes to the last part; the messages sent before a failure are returned with it.
+func (b *Bot) sendHTML(chatID int64, threadID int, text string, keyboard any) ([]tgbotapi.Message, error) {
+	parts := splitMessage(text, telegramMessageLimit)
+
+	sent := make([]tgbotapi.Message, 0, len(parts))
+	for i, part := range parts {
+		msg := tgbotapi.NewMessage(chatID, part)
+		msg.ParseMode = tgbotapi.ModeHTML
+		if i == len(parts)-1 {
+			msg.ReplyMarkup = keyboard
+		}
+
+		message, err := b.send(msg, threadID)
+		if err != nil {
+			return sent, err
+		}
+		sent = append(sent, message)
+	}
+
+	return sent, nil
+}
@@ -0,0 +1,165 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+func TestFormatDish(t *testing.T) {
+	tests := []struct {
+		name string
+		item menu.MenuItem
+		want string
+	}{
+		{
+			name: "escapes markup",
+			item: menu.MenuItem{Name: "Rice <b>&</b> soup", Description: "1 < 2"},
+			want: "1) <b>Rice &lt;b&gt;&amp;&lt;/b&gt; soup</b>\n    <i>1 &lt; 2</i>\n",
+		},
+		{
+			name: "spiciness and badges",
+			item: menu.MenuItem{Name: "제육볶음", Description: "Острая свинина", Spiciness: 3},
+			want: "1) <b>제육볶음</b> 🌶🌶🌶 🐷\n    <i>Острая свинина</i>\n",
+		},
+		{
+			name: "vegetarian",
+			item: menu.MenuItem{Name: "두부조림", Description: "TODO"},
+			want: "1) <b>두부조림</b> 🥬\n",
+		},
+		{
+			name: "calorie estimate",
+			item: menu.MenuItem{Name: "두부조림", Nutrition: &menu.Nutrition{Calories: 180}},
+			want: "1) <b>두부조림</b> 🥬 · ~180 ккал\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDish(1, &tt.item); got != tt.want {
+				t.Errorf("formatDish() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	line := "1) <b>" + strings.Repeat("밥", 40) + "</b> 🌶🌶\n"
+	text := strings.Repeat(line, 200)
+
+	parts := splitMessage(text, telegramMessageLimit)
+	if len(parts) < 2 {
+		t.Fatalf("splitMessage() returned %d parts, want several", len(parts))
+	}
+
+	var joined strings.Builder
+	for i, part := range parts {
+		checkMessagePart(t, i, part, telegramMessageLimit)
+		joined.WriteString(part + "\n")
+	}
+	if joined.String() != text {
+		t.Error("parts do not add up to the original text")
+	}
+
+	if got := splitMessage("short", telegramMessageLimit); len(got) != 1 || got[0] != "short" {
+		t.Errorf("splitMessage(short) = %q", got)
+	}
+}
+
+func TestSplitMessageLongLine(t *testing.T) {
+	const limit = 100
+
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "long description", text: "1) <b>김치찌개</b>\n    <i>" + strings.Repeat("Острый суп &amp; рис 🌶 ", 30) + "</i>\n"},
+		{name: "long word", text: "<b>" + strings.Repeat("밥", 250) + "</b>"},
+		{name: "nested tags", text: "<b>Peony <i>" + strings.Repeat("кимчи ", 60) + "</i> конец</b>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := splitMessage(tt.text, limit)
+			if len(parts) < 2 {
+				t.Fatalf("splitMessage() returned %d parts, want several", len(parts))
+			}
+
+			var content strings.Builder
+			for i, part := range parts {
+				checkMessagePart(t, i, part, limit)
+				content.WriteString(part)
+			}
+			if got, want := textContent(content.String()), textContent(tt.text); got != want {
+				t.Errorf("parts hold %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// checkMessagePart fails when a part is too long for Telegram or is not valid HTML on its own.
+func checkMessagePart(t *testing.T, i int, part string, limit int) {
+	t.Helper()
+
+	if n := utf16Len(part); n > limit {
+		t.Errorf("part %d has %d UTF-16 code units, limit %d", i, n, limit)
+	}
+	for _, tag := range []string{"b", "i"} {
+		if strings.Count(part, "<"+tag+">") != strings.Count(part, "</"+tag+">") {
+			t.Errorf("part %d has unbalanced <%s> tags: %q", i, tag, part)
+		}
+	}
+	for _, token := range htmlTokens(part) {
+		if strings.HasPrefix(token, "<") && !strings.HasSuffix(token, ">") ||
+			strings.HasPrefix(token, "&") && !strings.HasSuffix(token, ";") {
+			t.Errorf("part %d cuts %q", i, token)
+		}
+	}
+}
+
+// textContent drops tags and line breaks, which splitting may add or remove.
+func textContent(text string) string {
+	var content strings.Builder
+	for _, token := range htmlTokens(text) {
+		if !strings.HasPrefix(token, "<") {
+			content.WriteString(strings.ReplaceAll(token, "\n", ""))
+		}
+	}
+	return content.String()
+}
+
+func TestUTF16Len(t *testing.T) {
+	for text, want := range map[string]int{"abc": 3, "밥": 1, "Борщ": 4, "🌶": 2, "🍽️": 3} {
+		if got := utf16Len(text); got != want {
+			t.Errorf("utf16Len(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestPreviewLine(t *testing.T) {
+	peony := menu.NewMenu([]*menu.MenuItem{{Name: "Fish & chips"}, {Name: "김치"}, {Name: "밥"}, {Name: "국"}}, nil)
+
+	if got := previewLine(formatCafeteriaMenu(todayLabel, menu.PEONY, peony)); got != "Fish & chips, 김치, 밥" {
+		t.Errorf("previewLine() = %q", got)
+	}
+}
+
+func TestCafeteriaMenuTrayNutrition(t *testing.T) {
+	peony := menu.NewMenu([]*menu.MenuItem{
+		{Name: "김치찌개", Nutrition: &menu.Nutrition{Calories: 250, Protein: 16, Carbs: 10, Fat: 15}},
+		{Name: "쌀밥", Nutrition: &menu.Nutrition{Calories: 300, Protein: 5, Carbs: 66, Fat: 1}},
+		{Name: "단무지"},
+	}, nil)
+
+	got := formatCafeteriaMenu(todayLabel, menu.PEONY, peony)
+	want := "🍱 Поднос: ~550 ккал · Б 21 г · Ж 16 г · У 76 г <i>(оценка)</i>\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("formatCafeteriaMenu() = %q, want suffix %q", got, want)
+	}
+
+	plain := menu.NewMenu([]*menu.MenuItem{{Name: "단무지"}, {Name: "국"}}, nil)
+	if got := formatCafeteriaMenu(todayLabel, menu.PEONY, plain); strings.Contains(got, "Поднос") {
+		t.Errorf("menu without estimates shows a tray total: %q", got)
+	}
+}
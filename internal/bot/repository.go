@@ -4,8 +4,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/artyom-kalman/kbu-daily-menu/internal/compare"
 	"github.com/artyom-kalman/kbu-daily-menu/internal/database"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
 )
 
 type SubscriptionRepository struct {
@@ -41,11 +46,45 @@ func (r *SubscriptionRepository) LoadSubscribers() ([]int64, error) {
 	return subscribers, nil
 }
 
-func (r *SubscriptionRepository) Subscribe(chatID int64) error {
+type Subscription struct {
+	ChatID   int64
+	ThreadID int
+	Format   string
+}
+
+func (r *SubscriptionRepository) LoadSubscriptions() ([]Subscription, error) {
+	rows, err := r.db.Conn.Query(`
+		SELECT s.chat_id, s.thread_id, COALESCE(c.menu_format, ?) FROM bot_subscriptions s
+		LEFT JOIN bot_chat_settings c ON c.chat_id = s.chat_id
+		WHERE s.is_active = true
+	`, menuFormatText)
+	if err != nil {
+		return nil, fmt.Errorf("query active subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []Subscription
+	for rows.Next() {
+		var subscription Subscription
+		if err := rows.Scan(&subscription.ChatID, &subscription.ThreadID, &subscription.Format); err != nil {
+			return nil, fmt.Errorf("scan subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// Subscribe activates daily delivery for a chat; threadID selects a forum topic, 0 means the main chat.
+func (r *SubscriptionRepository) Subscribe(chatID int64, threadID int) error {
 	_, err := r.db.Conn.Exec(`
-		INSERT OR REPLACE INTO bot_subscriptions (chat_id, is_active, updated_at) 
-		VALUES (?, true, CURRENT_TIMESTAMP)
-	`, chatID)
+		INSERT OR REPLACE INTO bot_subscriptions (chat_id, is_active, thread_id, updated_at) 
+		VALUES (?, true, ?, CURRENT_TIMESTAMP)
+	`, chatID, threadID)
 	if err != nil {
 		return fmt.Errorf("subscribe chat %d: %w", chatID, err)
 	}
@@ -79,3 +118,288 @@ func (r *SubscriptionRepository) GetStatus(chatID int64) (bool, error) {
 	}
 	return isActive, nil
 }
+
+type Stats struct {
+	ActiveSubscribers   int
+	InactiveSubscribers int
+	GroupSubscribers    int
+	DeliveredToday      int
+	Watches             int
+}
+
+// LoadStats counts subscriptions, watches and the menu messages delivered for menuDate.
+func (r *SubscriptionRepository) LoadStats(menuDate time.Time) (Stats, error) {
+	var stats Stats
+	err := r.db.Conn.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM bot_subscriptions WHERE is_active = true),
+			(SELECT COUNT(*) FROM bot_subscriptions WHERE is_active = false),
+			(SELECT COUNT(*) FROM bot_subscriptions WHERE is_active = true AND chat_id < 0),
+			(SELECT COUNT(*) FROM bot_sent_messages WHERE menu_date = ?),
+			(SELECT COUNT(*) FROM bot_watches)
+	`, menuDate.Format("2006-01-02")).Scan(
+		&stats.ActiveSubscribers,
+		&stats.InactiveSubscribers,
+		&stats.GroupSubscribers,
+		&stats.DeliveredToday,
+		&stats.Watches,
+	)
+	if err != nil {
+		return Stats{}, fmt.Errorf("load bot stats: %w", err)
+	}
+	return stats, nil
+}
+
+// SentMessage is a delivered menu; a long text menu is split into several messages, listed in order.
+type SentMessage struct {
+	ChatID     int64
+	MessageIDs []int
+	Format     string
+}
+
+func (r *SubscriptionRepository) SaveSentMessage(chatID int64, messageIDs []int, menuDate time.Time, format string) error {
+	_, err := r.db.Conn.Exec(`
+		INSERT OR REPLACE INTO bot_sent_messages (chat_id, menu_date, message_id, message_ids, format, sent_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`, chatID, menuDate.Format("2006-01-02"), messageIDs[0], encodeMessageIDs(messageIDs), format)
+	if err != nil {
+		return fmt.Errorf("save sent message for chat %d: %w", chatID, err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepository) LoadSentMessages(menuDate time.Time) ([]SentMessage, error) {
+	rows, err := r.db.Conn.Query(`
+		SELECT m.chat_id, m.message_id, m.message_ids, m.format FROM bot_sent_messages m
+		JOIN bot_subscriptions s ON s.chat_id = m.chat_id
+		WHERE m.menu_date = ? AND s.is_active = true
+	`, menuDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("query sent messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []SentMessage
+	for rows.Next() {
+		var (
+			message   SentMessage
+			firstID   int
+			encodedIDs string
+		)
+		if err := rows.Scan(&message.ChatID, &firstID, &encodedIDs, &message.Format); err != nil {
+			return nil, fmt.Errorf("scan sent message: %w", err)
+		}
+		if message.MessageIDs, err = decodeMessageIDs(encodedIDs); err != nil {
+			return nil, fmt.Errorf("decode sent message ids for chat %d: %w", message.ChatID, err)
+		}
+		// Messages sent before the parts were recorded only know their first message.
+		if len(message.MessageIDs) == 0 {
+			message.MessageIDs = []int{firstID}
+		}
+		messages = append(messages, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate sent messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// MarkMessageUpdated records an edit of a delivered menu, which may have changed the messages it takes.
+func (r *SubscriptionRepository) MarkMessageUpdated(chatID int64, messageIDs []int, menuDate time.Time) error {
+	_, err := r.db.Conn.Exec(`
+		UPDATE bot_sent_messages
+		SET message_id = ?, message_ids = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE chat_id = ? AND menu_date = ?
+	`, messageIDs[0], encodeMessageIDs(messageIDs), chatID, menuDate.Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("mark message updated for chat %d: %w", chatID, err)
+	}
+	return nil
+}
+
+func encodeMessageIDs(messageIDs []int) string {
+	encoded := make([]string, len(messageIDs))
+	for i, id := range messageIDs {
+		encoded[i] = strconv.Itoa(id)
+	}
+	return strings.Join(encoded, ",")
+}
+
+func decodeMessageIDs(encoded string) ([]int, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+
+	var messageIDs []int
+	for _, field := range strings.Split(encoded, ",") {
+		id, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		messageIDs = append(messageIDs, id)
+	}
+	return messageIDs, nil
+}
+
+// GetMenuFormat returns how menus are delivered to a chat, text unless the chat chose otherwise.
+func (r *SubscriptionRepository) GetMenuFormat(chatID int64) (string, error) {
+	var format string
+	err := r.db.Conn.QueryRow(`
+		SELECT menu_format FROM bot_chat_settings
+		WHERE chat_id = ?
+	`, chatID).Scan(&format)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return menuFormatText, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get menu format for chat %d: %w", chatID, err)
+	}
+	return format, nil
+}
+
+func (r *SubscriptionRepository) SetMenuFormat(chatID int64, format string) error {
+	_, err := r.db.Conn.Exec(`
+		INSERT INTO bot_chat_settings (chat_id, menu_format, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(chat_id) DO UPDATE SET menu_format = excluded.menu_format, updated_at = excluded.updated_at
+	`, chatID, format)
+	if err != nil {
+		return fmt.Errorf("set menu format for chat %d: %w", chatID, err)
+	}
+	return nil
+}
+
+// GetPreferences returns the chat's food preferences; watched dishes count as favourites.
+func (r *SubscriptionRepository) GetPreferences(chatID int64) (compare.Preferences, error) {
+	prefs := compare.DefaultPreferences()
+
+	var avoid string
+	err := r.db.Conn.QueryRow(`
+		SELECT max_spiciness, avoid FROM bot_chat_settings
+		WHERE chat_id = ?
+	`, chatID).Scan(&prefs.MaxSpiciness, &avoid)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return compare.Preferences{}, fmt.Errorf("get preferences for chat %d: %w", chatID, err)
+	}
+
+	for _, tag := range strings.Split(avoid, ",") {
+		if tag != "" {
+			prefs.Avoid = append(prefs.Avoid, menu.DietTag(tag))
+		}
+	}
+
+	watches, err := r.LoadWatches(chatID)
+	if err != nil {
+		return compare.Preferences{}, err
+	}
+	for _, watch := range watches {
+		if watch.Query != "" {
+			prefs.Favourites = append(prefs.Favourites, watch.Query)
+		}
+	}
+
+	return prefs, nil
+}
+
+// SavePreferences stores the spiciness limit and avoided foods; favourites are managed through watches.
+func (r *SubscriptionRepository) SavePreferences(chatID int64, prefs compare.Preferences) error {
+	avoid := make([]string, len(prefs.Avoid))
+	for i, tag := range prefs.Avoid {
+		avoid[i] = string(tag)
+	}
+
+	_, err := r.db.Conn.Exec(`
+		INSERT INTO bot_chat_settings (chat_id, max_spiciness, avoid, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(chat_id) DO UPDATE SET
+			max_spiciness = excluded.max_spiciness,
+			avoid = excluded.avoid,
+			updated_at = excluded.updated_at
+	`, chatID, prefs.MaxSpiciness, strings.Join(avoid, ","))
+	if err != nil {
+		return fmt.Errorf("save preferences for chat %d: %w", chatID, err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepository) AddWatch(watch Watch) error {
+	_, err := r.db.Conn.Exec(`
+		INSERT OR IGNORE INTO bot_watches (chat_id, query, min_spiciness)
+		VALUES (?, ?, ?)
+	`, watch.ChatID, watch.Query, watch.MinSpiciness)
+	if err != nil {
+		return fmt.Errorf("add watch for chat %d: %w", watch.ChatID, err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepository) RemoveWatch(watchID int64) error {
+	_, err := r.db.Conn.Exec("DELETE FROM bot_watches WHERE id = ?", watchID)
+	if err != nil {
+		return fmt.Errorf("remove watch %d: %w", watchID, err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepository) LoadWatches(chatID int64) ([]Watch, error) {
+	return r.queryWatches(`
+		SELECT id, chat_id, query, min_spiciness FROM bot_watches
+		WHERE chat_id = ? ORDER BY id
+	`, chatID)
+}
+
+// LoadPendingWatches returns the watches of subscribed chats that have not been alerted about the cafeteria's menu for that day.
+func (r *SubscriptionRepository) LoadPendingWatches(menuDate time.Time, cafeteria string) ([]Watch, error) {
+	return r.queryWatches(`
+		SELECT w.id, w.chat_id, w.query, w.min_spiciness FROM bot_watches w
+		JOIN bot_subscriptions s ON s.chat_id = w.chat_id AND s.is_active = true
+		WHERE NOT EXISTS (
+			SELECT 1 FROM bot_watch_alerts a
+			WHERE a.watch_id = w.id AND a.menu_date = ? AND a.cafeteria = ?
+		)
+		ORDER BY w.chat_id, w.id
+	`, menuDate.Format("2006-01-02"), cafeteria)
+}
+
+func (r *SubscriptionRepository) queryWatches(query string, args ...any) ([]Watch, error) {
+	rows, err := r.db.Conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query watches: %w", err)
+	}
+	defer rows.Close()
+
+	var watches []Watch
+	for rows.Next() {
+		var watch Watch
+		if err := rows.Scan(&watch.ID, &watch.ChatID, &watch.Query, &watch.MinSpiciness); err != nil {
+			return nil, fmt.Errorf("scan watch: %w", err)
+		}
+		watches = append(watches, watch)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate watches: %w", err)
+	}
+
+	return watches, nil
+}
+
+// MarkWatchAlerted records an alert and reports false if one was already sent for that day.
+func (r *SubscriptionRepository) MarkWatchAlerted(watchID int64, menuDate time.Time, cafeteria string) (bool, error) {
+	result, err := r.db.Conn.Exec(`
+		INSERT OR IGNORE INTO bot_watch_alerts (watch_id, menu_date, cafeteria)
+		VALUES (?, ?, ?)
+	`, watchID, menuDate.Format("2006-01-02"), cafeteria)
+	if err != nil {
+		return false, fmt.Errorf("mark watch %d alerted: %w", watchID, err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("mark watch %d alerted: %w", watchID, err)
+	}
+	return inserted > 0, nil
+}
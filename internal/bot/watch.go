@@ -0,0 +1,237 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+var (
+	spicinessFilterRegex = regexp.MustCompile(`\s*(?:>=|≥|>|\+)?\s*([1-5])\s*\+?$`)
+	spicyKeywords        = []string{"spicy", "hot", "острое", "острая", "острый", "остро", "매운", "맵다"}
+	errEmptyWatch        = errors.New("empty watch query")
+)
+
+type Watch struct {
+	ID           int64
+	ChatID       int64
+	Query        string
+	MinSpiciness int
+}
+
+// parseWatch understands "돈까스", "borscht", "spicy >= 4" and "кимчи ≥3".
+func parseWatch(chatID int64, text string) (Watch, error) {
+	watch := Watch{ChatID: chatID}
+	text = strings.TrimSpace(strings.ToLower(text))
+
+	if match := spicinessFilterRegex.FindStringSubmatchIndex(text); match != nil {
+		level, err := strconv.Atoi(text[match[2]:match[3]])
+		if err == nil && (match[0] > 0 || strings.ContainsAny(text, ">≥+")) {
+			watch.MinSpiciness = level
+			text = strings.TrimSpace(text[:match[0]])
+		}
+	}
+
+	if slices.Contains(spicyKeywords, text) {
+		if watch.MinSpiciness == 0 {
+			watch.MinSpiciness = 3
+		}
+		text = ""
+	}
+
+	watch.Query = text
+	if watch.Query == "" && watch.MinSpiciness == 0 {
+		return Watch{}, errEmptyWatch
+	}
+
+	return watch, nil
+}
+
+func (w Watch) Matches(item *menu.MenuItem) bool {
+	if w.MinSpiciness > 0 && item.Spiciness < w.MinSpiciness {
+		return false
+	}
+	if w.Query == "" {
+		return w.MinSpiciness > 0
+	}
+	return menu.MatchDish(w.Query, item.Name)
+}
+
+func (w Watch) String() string {
+	switch {
+	case w.Query == "":
+		return fmt.Sprintf("острота от %d", w.MinSpiciness)
+	case w.MinSpiciness > 0:
+		return fmt.Sprintf("%s (острота от %d)", w.Query, w.MinSpiciness)
+	default:
+		return w.Query
+	}
+}
+
+func (b *Bot) addWatch(chatID int64, threadID int, args string) error {
+	watch, err := parseWatch(chatID, args)
+	if errors.Is(err, errEmptyWatch) {
+		return b.SendMessage(chatID, threadID, "Укажите блюдо, например:\n/watch 돈까스\n/watch борщ\n/watch острое >= 4")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := b.repo.AddWatch(watch); err != nil {
+		slog.Error("Failed to add watch", "chat_id", chatID, "error", err)
+		return b.SendMessage(chatID, threadID, "Не удалось сохранить отслеживание. Попробуйте позже.")
+	}
+
+	reply := fmt.Sprintf("👀 Буду сообщать, когда в меню появится: %s", watch)
+	if active, err := b.repo.GetStatus(chatID); err == nil && !active {
+		reply += "\nУведомления приходят только подписчикам — подпишитесь командой /subscribe."
+	}
+	return b.SendMessage(chatID, threadID, reply)
+}
+
+func (b *Bot) removeWatch(chatID int64, threadID int, args string) error {
+	watches, err := b.repo.LoadWatches(chatID)
+	if err != nil {
+		slog.Error("Failed to load watches", "chat_id", chatID, "error", err)
+		return b.SendMessage(chatID, threadID, "Не удалось загрузить список отслеживаний. Попробуйте позже.")
+	}
+
+	target, found := findWatch(watches, chatID, args)
+	if !found {
+		return b.SendMessage(chatID, threadID, "Такого отслеживания нет. Посмотрите список: /watches")
+	}
+
+	if err := b.repo.RemoveWatch(target.ID); err != nil {
+		slog.Error("Failed to remove watch", "chat_id", chatID, "error", err)
+		return b.SendMessage(chatID, threadID, "Не удалось удалить отслеживание. Попробуйте позже.")
+	}
+
+	return b.SendMessage(chatID, threadID, fmt.Sprintf("🗑 Больше не отслеживаю: %s", target))
+}
+
+// findWatch accepts either the position shown by /watches or the original query.
+func findWatch(watches []Watch, chatID int64, args string) (Watch, bool) {
+	args = strings.TrimSpace(args)
+	if position, err := strconv.Atoi(args); err == nil {
+		if position >= 1 && position <= len(watches) {
+			return watches[position-1], true
+		}
+		return Watch{}, false
+	}
+
+	wanted, err := parseWatch(chatID, args)
+	if err != nil {
+		return Watch{}, false
+	}
+
+	for _, watch := range watches {
+		if watch.Query == wanted.Query && watch.MinSpiciness == wanted.MinSpiciness {
+			return watch, true
+		}
+	}
+	return Watch{}, false
+}
+
+func (b *Bot) listWatches(chatID int64, threadID int) error {
+	watches, err := b.repo.LoadWatches(chatID)
+	if err != nil {
+		slog.Error("Failed to load watches", "chat_id", chatID, "error", err)
+		return b.SendMessage(chatID, threadID, "Не удалось загрузить список отслеживаний. Попробуйте позже.")
+	}
+
+	if len(watches) == 0 {
+		return b.SendMessage(chatID, threadID, "Вы пока ничего не отслеживаете.\nДобавьте блюдо командой /watch 돈까스")
+	}
+
+	var message strings.Builder
+	message.WriteString("👀 Отслеживаемые блюда:\n")
+	for i, watch := range watches {
+		message.WriteString(fmt.Sprintf("%d) %s\n", i+1, watch))
+	}
+	message.WriteString("\nУдалить: /unwatch <номер>")
+
+	return b.SendMessage(chatID, threadID, message.String())
+}
+
+// CheckWatches alerts subscribed chats whose watched dishes appear in a freshly updated menu.
+// A watch is marked as alerted only once its message was delivered, so a failed send is retried on the next update.
+func (b *Bot) CheckWatches(ctx context.Context, cafeteria menu.Cafeteria, updated *menu.Menu) {
+	if updated == nil || len(updated.Items) <= 1 {
+		return
+	}
+
+	menuDate := b.clock.Now()
+	watches, err := b.repo.LoadPendingWatches(menuDate, string(cafeteria))
+	if err != nil {
+		slog.Error("Failed to load watches", "error", err)
+		return
+	}
+
+	alerts := make(map[int64][]string)
+	alerted := make(map[int64][]int64)
+
+	for _, watch := range watches {
+		var dishes []string
+		for _, item := range updated.Items {
+			if watch.Matches(item) {
+				dishes = append(dishes, item.Name)
+			}
+		}
+		if len(dishes) == 0 {
+			continue
+		}
+
+		alerts[watch.ChatID] = append(alerts[watch.ChatID],
+			fmt.Sprintf("• %s — по запросу «%s»", strings.Join(dishes, ", "), watch))
+		alerted[watch.ChatID] = append(alerted[watch.ChatID], watch.ID)
+	}
+
+	if len(alerts) == 0 {
+		return
+	}
+
+	// Alerts go to the forum topic chosen at subscribe time, like the daily menu.
+	subscribers, err := b.repo.LoadSubscriptions()
+	if err != nil {
+		slog.Error("Failed to load subscribers for watch alerts", "error", err)
+		return
+	}
+	_, targets := subscriptionTargets(subscribers)
+
+	chatIDs := make([]int64, 0, len(alerts))
+	for chatID := range alerts {
+		chatIDs = append(chatIDs, chatID)
+	}
+
+	b.broadcast(ctx, "watch_alert", chatIDs, func(chatID int64) error {
+		text := fmt.Sprintf("🔔 Сегодня в %s:\n%s", cafeteriaTitle(cafeteria), strings.Join(alerts[chatID], "\n"))
+		if err := b.SendMessage(chatID, targets[chatID].ThreadID, text); err != nil {
+			return err
+		}
+
+		for _, watchID := range alerted[chatID] {
+			if _, err := b.repo.MarkWatchAlerted(watchID, menuDate, string(cafeteria)); err != nil {
+				slog.Error("Failed to record watch alert", "watch_id", watchID, "error", err)
+			}
+		}
+		return nil
+	})
+}
+
+func cafeteriaTitle(cafeteria menu.Cafeteria) string {
+	switch cafeteria {
+	case menu.PEONY:
+		return "🌸 Peony"
+	case menu.AZILEA:
+		return "🌺 Azilea"
+	default:
+		return string(cafeteria)
+	}
+}
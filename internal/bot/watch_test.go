@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+func TestParseWatch(t *testing.T) {
+	tests := []struct {
+		text          string
+		wantQuery     string
+		wantSpiciness int
+		wantErr       error
+	}{
+		{text: "돈까스", wantQuery: "돈까스"},
+		{text: "  Borscht ", wantQuery: "borscht"},
+		{text: "spicy >= 4", wantSpiciness: 4},
+		{text: "острое", wantSpiciness: 3},
+		{text: "кимчи ≥3", wantQuery: "кимчи", wantSpiciness: 3},
+		{text: "кимчи 3+", wantQuery: "кимчи", wantSpiciness: 3},
+		{text: "제육볶음 > 2", wantQuery: "제육볶음", wantSpiciness: 2},
+		{text: "7up", wantQuery: "7up"},
+		{text: "4", wantQuery: "4"},
+		{text: "", wantErr: errEmptyWatch},
+		{text: "   ", wantErr: errEmptyWatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			watch, err := parseWatch(42, tt.text)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseWatch(%q) error = %v, want %v", tt.text, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if watch.ChatID != 42 || watch.Query != tt.wantQuery || watch.MinSpiciness != tt.wantSpiciness {
+				t.Errorf("parseWatch(%q) = %+v, want query %q and spiciness %d", tt.text, watch, tt.wantQuery, tt.wantSpiciness)
+			}
+		})
+	}
+}
+
+func TestWatchMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		watch Watch
+		item  menu.MenuItem
+		want  bool
+	}{
+		{name: "query", watch: Watch{Query: "돈까스"}, item: menu.MenuItem{Name: "치즈돈까스"}, want: true},
+		{name: "transliterated query", watch: Watch{Query: "тонкацу"}, item: menu.MenuItem{Name: "돈까스"}, want: true},
+		{name: "other dish", watch: Watch{Query: "돈까스"}, item: menu.MenuItem{Name: "된장국"}, want: false},
+		{name: "spicy enough", watch: Watch{MinSpiciness: 3}, item: menu.MenuItem{Name: "김치찌개", Spiciness: 4}, want: true},
+		{name: "too mild", watch: Watch{MinSpiciness: 3}, item: menu.MenuItem{Name: "김치찌개", Spiciness: 2}, want: false},
+		{name: "query and spiciness", watch: Watch{Query: "김치", MinSpiciness: 3}, item: menu.MenuItem{Name: "김치찌개", Spiciness: 2}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.watch.Matches(&tt.item); got != tt.want {
+				t.Errorf("%+v.Matches(%q) = %v, want %v", tt.watch, tt.item.Name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckWatches(t *testing.T) {
+	const (
+		subscribed   int64 = 501
+		unsubscribed int64 = 502
+		failing      int64 = 503
+	)
+
+	b, messenger, repo := newTestBot(t)
+	for _, chatID := range []int64{subscribed, unsubscribed, failing} {
+		mustSubscribe(t, repo, chatID)
+		if err := repo.AddWatch(Watch{ChatID: chatID, Query: "돈까스"}); err != nil {
+			t.Fatalf("AddWatch error: %v", err)
+		}
+	}
+	if err := repo.Unsubscribe(unsubscribed); err != nil {
+		t.Fatalf("Unsubscribe error: %v", err)
+	}
+	messenger.SendErr = map[int64]error{failing: errors.New("telegram is down")}
+
+	updated := menu.NewMenuFromDishes([]string{"김치찌개", "치즈돈까스"}, &testNow)
+	b.CheckWatches(context.Background(), menu.PEONY, updated)
+
+	if got := lastMessage(t, messenger, subscribed).Text; !strings.Contains(got, "치즈돈까스") {
+		t.Errorf("alert = %q, want it to name the dish", got)
+	}
+	if messages := messenger.MessagesTo(unsubscribed); len(messages) != 0 {
+		t.Errorf("unsubscribed chat got %d alerts, want none", len(messages))
+	}
+
+	// The failed alert is retried on the next update, the delivered one is not repeated.
+	messenger.SendErr = nil
+	b.CheckWatches(context.Background(), menu.PEONY, updated)
+
+	if messages := messenger.MessagesTo(subscribed); len(messages) != 1 {
+		t.Errorf("subscribed chat got %d alerts, want 1", len(messages))
+	}
+	if messages := messenger.MessagesTo(failing); len(messages) != 1 {
+		t.Errorf("chat whose first alert failed got %d alerts, want 1", len(messages))
+	}
+}
@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var webhookAllowedUpdates = []string{"message", "callback_query", "inline_query"}
+
+// seenUpdateLimit bounds how many update IDs are remembered; Telegram resends an update within minutes,
+// long before this many newer ones arrive.
+const seenUpdateLimit = 1000
+
+type webhookConfig struct {
+	url    string
+	secret string
+}
+
+// UseWebhook switches the bot from long polling to receiving updates on webhookURL.
+// Telegram echoes secret in the X-Telegram-Bot-Api-Secret-Token header of every request.
+func (b *Bot) UseWebhook(webhookURL, secret string) {
+	b.webhook = &webhookConfig{
+		url:    webhookURL,
+		secret: secret,
+	}
+}
+
+// setWebhook builds the request by hand because tgbotapi.WebhookConfig has no secret_token field.
+func (b *Bot) setWebhook() error {
+	params := tgbotapi.Params{
+		"url":          b.webhook.url,
+		"secret_token": b.webhook.secret,
+	}
+	if err := params.AddInterface("allowed_updates", webhookAllowedUpdates); err != nil {
+		return fmt.Errorf("encode allowed updates: %w", err)
+	}
+
+	if err := b.messenger.MakeRequest("setWebhook", params); err != nil {
+		return err
+	}
+
+	slog.Info("Telegram webhook registered", "url", b.webhook.url)
+	return nil
+}
+
+// deleteWebhook clears a webhook left from a previous deployment, otherwise getUpdates is rejected.
+func (b *Bot) deleteWebhook() {
+	if err := b.messenger.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		slog.Warn("Failed to delete Telegram webhook", "error", err)
+	}
+}
+
+// updateLog remembers the most recent update IDs to drop updates Telegram delivers twice.
+type updateLog struct {
+	mu    sync.Mutex
+	seen  map[int]struct{}
+	order []int
+}
+
+// firstSeen records id and reports whether it had not been seen before.
+func (l *updateLog) firstSeen(id int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.seen == nil {
+		l.seen = make(map[int]struct{}, seenUpdateLimit)
+	}
+	if _, ok := l.seen[id]; ok {
+		return false
+	}
+
+	l.seen[id] = struct{}{}
+	l.order = append(l.order, id)
+	if len(l.order) > seenUpdateLimit {
+		delete(l.seen, l.order[0])
+		l.order = l.order[1:]
+	}
+	return true
+}
@@ -0,0 +1,23 @@
+package bot
+
+import "testing"
+
+func TestUpdateLogForgetsOldestIDs(t *testing.T) {
+	var log updateLog
+
+	for id := 1; id <= seenUpdateLimit+1; id++ {
+		if !log.firstSeen(id) {
+			t.Fatalf("firstSeen(%d) = false on first delivery", id)
+		}
+	}
+
+	if log.firstSeen(seenUpdateLimit + 1) {
+		t.Errorf("firstSeen(%d) = true for a repeated update", seenUpdateLimit+1)
+	}
+	if !log.firstSeen(1) {
+		t.Errorf("firstSeen(1) = false, want the oldest update to be forgotten")
+	}
+	if len(log.order) != seenUpdateLimit || len(log.seen) != seenUpdateLimit {
+		t.Errorf("log keeps %d ids in order and %d in the set, want %d", len(log.order), len(log.seen), seenUpdateLimit)
+	}
+}
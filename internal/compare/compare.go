@@ -0,0 +1,147 @@
+// Package compare decides which cafeteria suits a chat better on a given day.
+package compare
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+// NoSpicinessLimit is the tolerance of chats that did not set one.
+const NoSpicinessLimit = 5
+
+const (
+	favouriteBonus = 3.0
+	// A dish rated above the neutral 3 stars adds to its cafeteria's score, one rated below subtracts.
+	neutralRating = 3.0
+)
+
+type Preferences struct {
+	MaxSpiciness int
+	Avoid        []menu.DietTag
+	Favourites   []string
+}
+
+// DefaultPreferences has no restrictions, which is what anonymous web visitors get.
+func DefaultPreferences() Preferences {
+	return Preferences{MaxSpiciness: NoSpicinessLimit}
+}
+
+type Verdict struct {
+	// Cafeteria is empty when both cafeterias are closed or score the same, so there is nothing to advise.
+	Cafeteria menu.Cafeteria
+	Reason    string
+	Scores    map[menu.Cafeteria]float64
+}
+
+type cafeteriaStats struct {
+	cafeteria  menu.Cafeteria
+	open       bool
+	score      float64
+	suitable   int
+	tooSpicy   int
+	avoided    int
+	favourites []string
+	bestRated  *menu.MenuItem
+}
+
+// Compare scores both menus against the preferences and explains the winner in one line.
+func Compare(peony, azilea *menu.Menu, prefs Preferences) Verdict {
+	stats := []cafeteriaStats{
+		score(menu.PEONY, peony, prefs),
+		score(menu.AZILEA, azilea, prefs),
+	}
+
+	verdict := Verdict{Scores: make(map[menu.Cafeteria]float64, len(stats))}
+	for _, s := range stats {
+		if s.open {
+			verdict.Scores[s.cafeteria] = s.score
+		}
+	}
+
+	winner, loser := stats[0], stats[1]
+	if !winner.open || (loser.open && loser.score > winner.score) {
+		winner, loser = loser, winner
+	}
+	if !winner.open || (loser.open && loser.score == winner.score) {
+		return verdict
+	}
+
+	verdict.Cafeteria = winner.cafeteria
+	verdict.Reason = reason(winner, loser)
+	return verdict
+}
+
+func score(cafeteria menu.Cafeteria, cafeteriaMenu *menu.Menu, prefs Preferences) cafeteriaStats {
+	stats := cafeteriaStats{cafeteria: cafeteria}
+	if cafeteriaMenu == nil || len(cafeteriaMenu.Items) <= 1 {
+		return stats
+	}
+	stats.open = true
+
+	for _, item := range cafeteriaMenu.Items {
+		switch {
+		case item.Spiciness > prefs.MaxSpiciness:
+			stats.tooSpicy++
+			stats.score--
+			continue
+		case slices.ContainsFunc(item.DietTags(), func(tag menu.DietTag) bool { return slices.Contains(prefs.Avoid, tag) }):
+			stats.avoided++
+			stats.score--
+			continue
+		}
+
+		stats.suitable++
+		stats.score++
+
+		if slices.ContainsFunc(prefs.Favourites, func(query string) bool { return menu.MatchDish(query, item.Name) }) {
+			stats.favourites = append(stats.favourites, item.Name)
+			stats.score += favouriteBonus
+		}
+
+		if item.Rating != nil && item.Rating.Count > 0 {
+			stats.score += item.Rating.Average - neutralRating
+			if stats.bestRated == nil || item.Rating.Average > stats.bestRated.Rating.Average {
+				stats.bestRated = item
+			}
+		}
+	}
+
+	return stats
+}
+
+// reason names the most telling difference between the two menus, checking the strongest signals first.
+func reason(winner, loser cafeteriaStats) string {
+	name := cafeteriaName(winner.cafeteria)
+
+	switch {
+	case !loser.open:
+		return fmt.Sprintf("%s сегодня не работает", cafeteriaName(loser.cafeteria))
+	case len(winner.favourites) > len(loser.favourites):
+		return fmt.Sprintf("в %s есть ваше любимое: %s", name, strings.Join(winner.favourites, ", "))
+	case winner.tooSpicy < loser.tooSpicy:
+		return fmt.Sprintf("в %s меньше слишком острых блюд (%d против %d)", name, winner.tooSpicy, loser.tooSpicy)
+	case winner.avoided < loser.avoided:
+		return fmt.Sprintf("в %s меньше блюд, которые вы не едите (%d против %d)", name, winner.avoided, loser.avoided)
+	case winner.bestRated != nil && (loser.bestRated == nil || winner.bestRated.Rating.Average > loser.bestRated.Rating.Average):
+		return fmt.Sprintf("в %s есть %s с оценкой ★%.1f", name, winner.bestRated.Name, winner.bestRated.Rating.Average)
+	case winner.suitable > loser.suitable:
+		return fmt.Sprintf("в %s больше подходящих блюд (%d против %d)", name, winner.suitable, loser.suitable)
+	default:
+		// Only the ratings are left to tell the menus apart.
+		return fmt.Sprintf("в %s блюда оценивают ниже", cafeteriaName(loser.cafeteria))
+	}
+}
+
+func cafeteriaName(cafeteria menu.Cafeteria) string {
+	switch cafeteria {
+	case menu.PEONY:
+		return "Peony"
+	case menu.AZILEA:
+		return "Azilea"
+	default:
+		return string(cafeteria)
+	}
+}
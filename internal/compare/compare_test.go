@@ -0,0 +1,126 @@
+package compare
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+func testMenu(items ...*menu.MenuItem) *menu.Menu {
+	return menu.NewMenu(items, nil)
+}
+
+func TestCompare(t *testing.T) {
+	spicyPeony := testMenu(
+		&menu.MenuItem{Name: "김치찌개", Spiciness: 4},
+		&menu.MenuItem{Name: "제육볶음", Spiciness: 4},
+		&menu.MenuItem{Name: "쌀밥"},
+	)
+	mildAzilea := testMenu(
+		&menu.MenuItem{Name: "된장국", Spiciness: 1},
+		&menu.MenuItem{Name: "돈까스"},
+		&menu.MenuItem{Name: "쌀밥"},
+	)
+	closed := testMenu(&menu.MenuItem{Name: "Сегодня тут пусто"})
+
+	tests := []struct {
+		name       string
+		peony      *menu.Menu
+		azilea     *menu.Menu
+		prefs      Preferences
+		want       menu.Cafeteria
+		wantReason string
+	}{
+		{
+			name:       "one cafeteria closed",
+			peony:      closed,
+			azilea:     mildAzilea,
+			prefs:      DefaultPreferences(),
+			want:       menu.AZILEA,
+			wantReason: "Peony сегодня не работает",
+		},
+		{
+			name:   "both closed",
+			peony:  closed,
+			azilea: nil,
+			prefs:  DefaultPreferences(),
+		},
+		{
+			name:   "tie",
+			peony:  mildAzilea,
+			azilea: testMenu(&menu.MenuItem{Name: "비빔밥"}, &menu.MenuItem{Name: "미역국"}, &menu.MenuItem{Name: "쌀밥"}),
+			prefs:  DefaultPreferences(),
+		},
+		{
+			name:       "favourite dish wins",
+			peony:      spicyPeony,
+			azilea:     mildAzilea,
+			prefs:      Preferences{MaxSpiciness: NoSpicinessLimit, Favourites: []string{"김치찌개"}},
+			want:       menu.PEONY,
+			wantReason: "ваше любимое: 김치찌개",
+		},
+		{
+			name:       "low spiciness tolerance",
+			peony:      spicyPeony,
+			azilea:     mildAzilea,
+			prefs:      Preferences{MaxSpiciness: 2},
+			want:       menu.AZILEA,
+			wantReason: "меньше слишком острых блюд (0 против 2)",
+		},
+		{
+			name:       "avoided food",
+			peony:      testMenu(&menu.MenuItem{Name: "비빔밥"}, &menu.MenuItem{Name: "쌀밥"}),
+			azilea:     mildAzilea,
+			prefs:      Preferences{MaxSpiciness: NoSpicinessLimit, Avoid: []menu.DietTag{menu.DietPork}},
+			want:       menu.PEONY,
+			wantReason: "которые вы не едите (0 против 1)",
+		},
+		{
+			name:  "ratings",
+			peony: spicyPeony,
+			azilea: testMenu(
+				&menu.MenuItem{Name: "된장국", Rating: &menu.DishRating{Average: 4.5, Count: 10}},
+				&menu.MenuItem{Name: "돈까스"},
+				&menu.MenuItem{Name: "쌀밥"},
+			),
+			prefs:      DefaultPreferences(),
+			want:       menu.AZILEA,
+			wantReason: "된장국 с оценкой ★4.5",
+		},
+		{
+			name: "poorly rated dish",
+			peony: testMenu(
+				&menu.MenuItem{Name: "김치찌개", Rating: &menu.DishRating{Average: 1, Count: 3}},
+				&menu.MenuItem{Name: "쌀밥"},
+			),
+			azilea:     testMenu(&menu.MenuItem{Name: "된장국"}, &menu.MenuItem{Name: "쌀밥"}),
+			prefs:      DefaultPreferences(),
+			want:       menu.AZILEA,
+			wantReason: "в Peony блюда оценивают ниже",
+		},
+		{
+			name:       "more suitable dishes",
+			peony:      spicyPeony,
+			azilea:     testMenu(&menu.MenuItem{Name: "된장국"}, &menu.MenuItem{Name: "쌀밥"}),
+			prefs:      DefaultPreferences(),
+			want:       menu.PEONY,
+			wantReason: "больше подходящих блюд (3 против 2)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := Compare(tt.peony, tt.azilea, tt.prefs)
+			if verdict.Cafeteria != tt.want {
+				t.Fatalf("Cafeteria = %q, want %q (scores %v)", verdict.Cafeteria, tt.want, verdict.Scores)
+			}
+			if tt.want == "" && verdict.Reason != "" {
+				t.Errorf("Reason = %q, want none without a verdict", verdict.Reason)
+			}
+			if !strings.Contains(verdict.Reason, tt.wantReason) {
+				t.Errorf("Reason = %q, want it to contain %q", verdict.Reason, tt.wantReason)
+			}
+		})
+	}
+}
@@ -2,7 +2,11 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,16 +16,38 @@ func init() {
 }
 
 type Config struct {
-	Port             string
-	DatabasePath     string
-	MigrationPath    string
+	Port          string
+	DatabasePath  string
+	MigrationPath string
+	// AssetsDir serves migrations, templates and web files from disk instead of the binary;
+	// MigrationPath is relative to the assets root either way.
+	AssetsDir        string
 	PeonyURL         string
 	AzileaURL        string
 	TelegramBotToken string
 	GPTURL           string
 	GPTToken         string
+
+	TelegramMode          string
+	TelegramWebhookURL    string
+	TelegramWebhookSecret string
+
+	AdminChatIDs []int64
+
+	// Cron expressions for menu refreshes; empty means the scheduler default.
+	PeonySchedule  []string
+	AzileaSchedule []string
+
+	// MenuPollInterval re-fetches an unpublished menu until MenuPollCutoff, a time of day; zero disables polling.
+	MenuPollInterval time.Duration
+	MenuPollCutoff   time.Duration
 }
 
+const (
+	TelegramModePolling = "polling"
+	TelegramModeWebhook = "webhook"
+)
+
 func LoadConfig() (*Config, error) {
 	peonyURL, err := GetEnv("PEONY_URL")
 	if err != nil {
@@ -40,6 +66,7 @@ func LoadConfig() (*Config, error) {
 
 	databasePath := GetEnvWithDefault("DATABASE_PATH", "./database/daily-menu.db")
 	migrationPath := GetEnvWithDefault("MIGRATION_PATH", "migrations")
+	assetsDir := os.Getenv("ASSETS_DIR")
 
 	port := GetEnvWithDefault("PORT", "8080")
 
@@ -53,18 +80,105 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	telegramMode := GetEnvWithDefault("TELEGRAM_MODE", TelegramModePolling)
+	webhookURL := os.Getenv("TELEGRAM_WEBHOOK_URL")
+	webhookSecret := os.Getenv("TELEGRAM_WEBHOOK_SECRET")
+
+	switch telegramMode {
+	case TelegramModePolling:
+	case TelegramModeWebhook:
+		parsedURL, err := url.ParseRequestURI(webhookURL)
+		if err != nil {
+			return nil, fmt.Errorf("TELEGRAM_WEBHOOK_URL must be a valid URL in webhook mode: %w", err)
+		}
+		if parsedURL.Path == "" || parsedURL.Path == "/" {
+			return nil, fmt.Errorf("TELEGRAM_WEBHOOK_URL must include a path such as /telegram/webhook")
+		}
+		if webhookSecret == "" {
+			return nil, fmt.Errorf("TELEGRAM_WEBHOOK_SECRET is required in webhook mode")
+		}
+	default:
+		return nil, fmt.Errorf("unknown TELEGRAM_MODE %q, expected %q or %q", telegramMode, TelegramModePolling, TelegramModeWebhook)
+	}
+
+	adminChatIDs, err := parseChatIDs(os.Getenv("ADMIN_CHAT_IDS"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ADMIN_CHAT_IDS: %w", err)
+	}
+
+	pollInterval, err := time.ParseDuration(GetEnvWithDefault("MENU_POLL_INTERVAL", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse MENU_POLL_INTERVAL: %w", err)
+	}
+
+	pollCutoff, err := parseTimeOfDay(GetEnvWithDefault("MENU_POLL_CUTOFF", "13:00"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse MENU_POLL_CUTOFF: %w", err)
+	}
+
 	return &Config{
 		Port:             port,
 		DatabasePath:     databasePath,
 		MigrationPath:    migrationPath,
+		AssetsDir:        assetsDir,
 		PeonyURL:         peonyURL,
 		AzileaURL:        azileaURL,
 		TelegramBotToken: telegramBotToken,
 		GPTToken:         gptToken,
 		GPTURL:           gptURL,
+
+		TelegramMode:          telegramMode,
+		TelegramWebhookURL:    webhookURL,
+		TelegramWebhookSecret: webhookSecret,
+
+		AdminChatIDs: adminChatIDs,
+
+		PeonySchedule:  parseSchedule(os.Getenv("PEONY_SCHEDULE")),
+		AzileaSchedule: parseSchedule(os.Getenv("AZILEA_SCHEDULE")),
+
+		MenuPollInterval: pollInterval,
+		MenuPollCutoff:   pollCutoff,
 	}, nil
 }
 
+// parseChatIDs reads a comma-separated list of Telegram chat IDs such as "12345,-100987".
+func parseChatIDs(value string) ([]int64, error) {
+	var chatIDs []int64
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		chatID, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chat ID %q: %w", field, err)
+		}
+		chatIDs = append(chatIDs, chatID)
+	}
+	return chatIDs, nil
+}
+
+// parseSchedule splits a ";"-separated list of cron expressions; the expressions themselves may contain commas.
+func parseSchedule(value string) []string {
+	var specs []string
+	for _, spec := range strings.Split(value, ";") {
+		if spec = strings.TrimSpace(spec); spec != "" {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// parseTimeOfDay converts "HH:MM" into the offset from midnight.
+func parseTimeOfDay(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
 func GetEnv(key string) (string, error) {
 	env := os.Getenv(key)
 	if env == "" {
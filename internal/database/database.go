@@ -1,26 +1,40 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
+const (
+	busyTimeout  = 5 * time.Second
+	pingTimeout  = 5 * time.Second
+	maxOpenConns = 4
+)
+
 type Database struct {
 	Conn *sql.DB
 }
 
-func Init(dbPath string, migrationPath string) (*Database, error) {
+// Init opens the database and applies the migrations found in migrationPath within migrationFS.
+func Init(dbPath string, migrationFS fs.FS, migrationPath string) (*Database, error) {
 	db, err := NewDatabase(dbPath)
 	if err != nil {
 		return nil, err
 	}
 
 	migrator := NewMigrator(db)
-	if err := migrator.LoadMigrationsFromFS(os.DirFS("./"), migrationPath); err != nil {
+	if err := migrator.LoadMigrationsFromFS(migrationFS, migrationPath); err != nil {
 		return nil, fmt.Errorf("failed to load migrations from %s: %w", migrationPath, err)
 	}
 	if err := migrator.Up(); err != nil {
@@ -30,18 +44,64 @@ func Init(dbPath string, migrationPath string) (*Database, error) {
 	return db, nil
 }
 
+// NewDatabase opens the SQLite database at path, creating its directory when missing.
+// Connections use WAL so readers do not block the writer, wait up to busyTimeout for locks
+// and start transactions with an immediate write lock, so concurrent writers queue instead of failing.
 func NewDatabase(path string) (*Database, error) {
-	conn, err := sql.Open("sqlite3", path)
+	if dir := filepath.Dir(path); !inMemory(path) && !strings.HasPrefix(path, "file:") {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create database directory %s: %w", dir, err)
+		}
+	}
+
+	conn, err := sql.Open("sqlite3", dataSourceName(path))
 	if err != nil {
 		slog.Error("Failed to open database", "err", err)
 		return nil, err
 	}
 
+	// SQLite has a single writer; a few connections let reads proceed alongside it under WAL.
+	// An in-memory database belongs to its connection, so it gets exactly one that is never closed.
+	openConns := maxOpenConns
+	if inMemory(path) {
+		openConns = 1
+	}
+	conn.SetMaxOpenConns(openConns)
+	conn.SetMaxIdleConns(openConns)
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("connect to database %s: %w", path, err)
+	}
+
 	return &Database{
 		Conn: conn,
 	}, nil
 }
 
+// inMemory reports ":memory:" and "file:...?mode=memory" paths.
+func inMemory(path string) bool {
+	return strings.Contains(path, ":memory:") || strings.Contains(path, "mode=memory")
+}
+
+func dataSourceName(path string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+
+	options := url.Values{}
+	options.Set("_journal_mode", "WAL")
+	options.Set("_synchronous", "NORMAL")
+	options.Set("_busy_timeout", strconv.Itoa(int(busyTimeout.Milliseconds())))
+	options.Set("_foreign_keys", "on")
+	options.Set("_txlock", "immediate")
+
+	return path + separator + options.Encode()
+}
+
 func (db *Database) Close() error {
 	return db.Conn.Close()
 }
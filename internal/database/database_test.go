@@ -0,0 +1,152 @@
+package database_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	kbudailymenu "github.com/artyom-kalman/kbu-daily-menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/bot"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database/databasetest"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+func TestNewDatabaseConfiguresSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "dir", "menu.db")
+
+	db, err := database.NewDatabase(path)
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("database file was not created: %v", err)
+	}
+
+	tests := []struct {
+		pragma string
+		want   string
+	}{
+		{pragma: "journal_mode", want: "wal"},
+		{pragma: "busy_timeout", want: "5000"},
+		{pragma: "foreign_keys", want: "1"},
+		{pragma: "synchronous", want: "1"},
+	}
+	for _, tt := range tests {
+		var got string
+		if err := db.Conn.QueryRow("PRAGMA " + tt.pragma).Scan(&got); err != nil {
+			t.Fatalf("PRAGMA %s: %v", tt.pragma, err)
+		}
+		if got != tt.want {
+			t.Errorf("PRAGMA %s = %q, want %q", tt.pragma, got, tt.want)
+		}
+	}
+}
+
+func TestNewDatabaseFailsOnUnusablePath(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	if db, err := database.NewDatabase(filepath.Join(file, "menu.db")); err == nil {
+		db.Close()
+		t.Fatalf("NewDatabase succeeded under a regular file")
+	}
+}
+
+// The scheduler saves menus while the bot records subscriptions; neither may fail with "database is locked".
+// An in-memory database must also stay one database however many goroutines use it.
+func TestConcurrentWrites(t *testing.T) {
+	tests := []struct {
+		name string
+		open func(t *testing.T) *database.Database
+	}{
+		{name: "file", open: func(t *testing.T) *database.Database { return databasetest.New(t) }},
+		{
+			name: "in memory",
+			open: func(t *testing.T) *database.Database {
+				db, err := database.Init(":memory:", kbudailymenu.Assets(""), "migrations")
+				if err != nil {
+					t.Fatalf("Init: %v", err)
+				}
+				t.Cleanup(func() { db.Close() })
+
+				// A second connection would open a second, empty database.
+				if got := db.Conn.Stats().MaxOpenConnections; got != 1 {
+					t.Fatalf("in-memory database allows %d connections, want 1", got)
+				}
+				return db
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkConcurrentWrites(t, tt.open(t))
+		})
+	}
+}
+
+func checkConcurrentWrites(t *testing.T, db *database.Database) {
+	t.Helper()
+
+	menus := menu.NewMenuRepository(db)
+	subscriptions := bot.NewSubscriptionRepository(db)
+	start := time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	const workers, writes = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*workers*writes)
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < writes; i++ {
+				dishes := []*menu.MenuItem{{Name: fmt.Sprintf("반찬 %d-%d", worker, i)}}
+				date := start.AddDate(0, 0, i)
+				if err := menus.SaveMenu(fmt.Sprintf("cafeteria-%d", worker), dishes, date, date); err != nil {
+					errs <- fmt.Errorf("SaveMenu: %w", err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < writes; i++ {
+				if err := subscriptions.Subscribe(int64(worker*writes+i), 0); err != nil {
+					errs <- fmt.Errorf("Subscribe: %w", err)
+				}
+				if _, err := subscriptions.LoadSubscribers(); err != nil {
+					errs <- fmt.Errorf("LoadSubscribers: %w", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	subscribers, err := subscriptions.LoadSubscribers()
+	if err != nil {
+		t.Fatalf("LoadSubscribers: %v", err)
+	}
+	if len(subscribers) != workers*writes {
+		t.Errorf("%d subscribers, want %d", len(subscribers), workers*writes)
+	}
+
+	var menuCount, revisionCount int
+	if err := db.Conn.QueryRow("SELECT (SELECT COUNT(*) FROM menu), (SELECT COUNT(*) FROM menu_revisions)").Scan(&menuCount, &revisionCount); err != nil {
+		t.Fatalf("count menus: %v", err)
+	}
+	if menuCount != workers*writes || revisionCount != workers*writes {
+		t.Errorf("%d menus and %d revisions, want %d of each", menuCount, revisionCount, workers*writes)
+	}
+}
@@ -0,0 +1,22 @@
+// Package databasetest opens migrated SQLite databases for tests, independent of the working directory.
+package databasetest
+
+import (
+	"path/filepath"
+	"testing"
+
+	kbudailymenu "github.com/artyom-kalman/kbu-daily-menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database"
+)
+
+// New returns a database in a temporary directory with the embedded migrations applied; it is closed when the test ends.
+func New(t testing.TB) *database.Database {
+	t.Helper()
+
+	db, err := database.Init(filepath.Join(t.TempDir(), "test.db"), kbudailymenu.Assets(""), "migrations")
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
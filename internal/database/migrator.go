@@ -1,21 +1,58 @@
 package database
 
 import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"io/fs"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 )
 
+const downSuffix = ".down.sql"
+
+// renamedVersions maps versions recorded by older releases to the files that replaced them,
+// so renumbering a migration does not apply it twice.
+var renamedVersions = map[string]string{
+	// Shared the 002 prefix with 002_create_bot_subscriptions and ran second only by lexical order.
+	"002_menu_unique_index": "011_menu_unique_index",
+}
+
 type Migrator struct {
 	db         *Database
 	migrations []Migration
 }
 
+// Migration is one schema change. Down is empty when the migration cannot be rolled back.
 type Migration struct {
 	Version string
 	SQL     string
+	Down    string
+}
+
+// Checksum identifies the migration's up SQL; down SQL may be fixed after the fact.
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.SQL))
+	return hex.EncodeToString(sum[:])
+}
+
+// MigrationStatus tells whether a migration has been applied and when.
+// Modified means the file changed after it was applied; Missing means it was applied but is no longer loaded.
+type MigrationStatus struct {
+	Version    string
+	Applied    bool
+	AppliedAt  time.Time
+	Modified   bool
+	Missing    bool
+	Reversible bool
+}
+
+type appliedMigration struct {
+	appliedAt time.Time
+	checksum  sql.NullString
 }
 
 func NewMigrator(db *Database) *Migrator {
@@ -31,22 +68,32 @@ func (m *Migrator) AddMigration(version, sql string) {
 	})
 }
 
+// Up applies pending migrations in version order. It refuses to run when an applied migration was edited.
 func (m *Migrator) Up() error {
-	if err := m.createMigrationsTable(); err != nil {
-		return fmt.Errorf("failed to create migrations table: %w", err)
-	}
-
-	appliedVersions, err := m.getAppliedVersions()
+	applied, err := m.prepare()
 	if err != nil {
-		return fmt.Errorf("failed to get applied versions: %w", err)
+		return err
 	}
 
-	sort.Slice(m.migrations, func(i, j int) bool {
-		return m.migrations[i].Version < m.migrations[j].Version
-	})
+	for _, migration := range m.migrations {
+		record, ok := applied[migration.Version]
+		if !ok {
+			continue
+		}
+		if !record.checksum.Valid {
+			// Applied before checksums were recorded: trust the file as it is now.
+			if err := m.recordChecksum(migration); err != nil {
+				return fmt.Errorf("failed to record checksum for %s: %w", migration.Version, err)
+			}
+			continue
+		}
+		if record.checksum.String != migration.Checksum() {
+			return fmt.Errorf("migration %s was modified after it was applied; add a new migration instead", migration.Version)
+		}
+	}
 
 	for _, migration := range m.migrations {
-		if _, applied := appliedVersions[migration.Version]; applied {
+		if _, ok := applied[migration.Version]; ok {
 			continue
 		}
 
@@ -58,33 +105,185 @@ func (m *Migrator) Up() error {
 	return nil
 }
 
+// Down rolls back the last n applied migrations, newest first.
+// Nothing is rolled back unless every one of them has a down migration.
+func (m *Migrator) Down(n int) error {
+	if n < 1 {
+		return fmt.Errorf("invalid number of migrations to roll back: %d", n)
+	}
+
+	applied, err := m.prepare()
+	if err != nil {
+		return err
+	}
+	if n > len(applied) {
+		return fmt.Errorf("cannot roll back %d migrations, only %d are applied", n, len(applied))
+	}
+
+	versions := make([]string, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(versions)))
+
+	loaded := make(map[string]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		loaded[migration.Version] = migration
+	}
+
+	rollback := make([]Migration, 0, n)
+	for _, version := range versions[:n] {
+		migration, ok := loaded[version]
+		if !ok {
+			return fmt.Errorf("cannot roll back %s: migration file is missing", version)
+		}
+		if migration.Down == "" {
+			return fmt.Errorf("cannot roll back %s: no %s file", version, version+downSuffix)
+		}
+		rollback = append(rollback, migration)
+	}
+
+	for _, migration := range rollback {
+		if err := m.revertMigration(migration); err != nil {
+			return fmt.Errorf("failed to roll back migration %s: %w", migration.Version, err)
+		}
+	}
+
+	return nil
+}
+
+// Status lists loaded and applied migrations in version order.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.prepare()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{
+			Version:    migration.Version,
+			Reversible: migration.Down != "",
+		}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.appliedAt
+			status.Modified = record.checksum.Valid && record.checksum.String != migration.Checksum()
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for version, record := range applied {
+		statuses = append(statuses, MigrationStatus{
+			Version:   version,
+			Applied:   true,
+			AppliedAt: record.appliedAt,
+			Missing:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// prepare validates the loaded migrations, brings the migrations table up to date and returns what is applied.
+func (m *Migrator) prepare() (map[string]appliedMigration, error) {
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+	if err := m.checkVersions(); err != nil {
+		return nil, err
+	}
+
+	if err := m.createMigrationsTable(); err != nil {
+		return nil, fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	applied, err := m.getAppliedVersions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied versions: %w", err)
+	}
+	return applied, nil
+}
+
+// checkVersions rejects migrations sharing a numeric prefix, whose order would depend on their names.
+func (m *Migrator) checkVersions() error {
+	seen := make(map[string]string, len(m.migrations))
+	for _, migration := range m.migrations {
+		prefix, _, _ := strings.Cut(migration.Version, "_")
+		if other, ok := seen[prefix]; ok {
+			return fmt.Errorf("duplicate migration version %s: %s and %s", prefix, other, migration.Version)
+		}
+		seen[prefix] = migration.Version
+	}
+	return nil
+}
+
 func (m *Migrator) createMigrationsTable() error {
 	_, err := m.db.Conn.Exec(`
 		CREATE TABLE IF NOT EXISTS schema_migrations (
 			version TEXT PRIMARY KEY,
-			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			checksum TEXT
 		)
 	`)
-	return err
+	if err != nil {
+		return err
+	}
+
+	var hasChecksum bool
+	if err := m.db.Conn.QueryRow(
+		"SELECT COUNT(*) > 0 FROM pragma_table_info('schema_migrations') WHERE name = 'checksum'",
+	).Scan(&hasChecksum); err != nil {
+		return err
+	}
+	if !hasChecksum {
+		if _, err := m.db.Conn.Exec("ALTER TABLE schema_migrations ADD COLUMN checksum TEXT"); err != nil {
+			return err
+		}
+	}
+
+	for from, to := range renamedVersions {
+		if _, err := m.db.Conn.Exec(
+			"UPDATE schema_migrations SET version = ? WHERE version = ?", to, from,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (m *Migrator) getAppliedVersions() (map[string]bool, error) {
-	rows, err := m.db.Conn.Query("SELECT version FROM schema_migrations")
+func (m *Migrator) getAppliedVersions() (map[string]appliedMigration, error) {
+	rows, err := m.db.Conn.Query("SELECT version, applied_at, checksum FROM schema_migrations")
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	versions := make(map[string]bool)
+	versions := make(map[string]appliedMigration)
 	for rows.Next() {
-		var version string
-		if err := rows.Scan(&version); err != nil {
+		var (
+			version string
+			record  appliedMigration
+		)
+		if err := rows.Scan(&version, &record.appliedAt, &record.checksum); err != nil {
 			return nil, err
 		}
-		versions[version] = true
+		versions[version] = record
 	}
 
-	return versions, nil
+	return versions, rows.Err()
+}
+
+func (m *Migrator) recordChecksum(migration Migration) error {
+	_, err := m.db.Conn.Exec(
+		"UPDATE schema_migrations SET checksum = ? WHERE version = ?", migration.Checksum(), migration.Version,
+	)
+	return err
 }
 
 func (m *Migrator) applyMigration(migration Migration) error {
@@ -98,33 +297,72 @@ func (m *Migrator) applyMigration(migration Migration) error {
 		return err
 	}
 
-	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (?)", migration.Version); err != nil {
+	if _, err := tx.Exec(
+		"INSERT INTO schema_migrations (version, checksum) VALUES (?, ?)", migration.Version, migration.Checksum(),
+	); err != nil {
 		return err
 	}
 
 	return tx.Commit()
 }
 
+func (m *Migrator) revertMigration(migration Migration) error {
+	tx, err := m.db.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(migration.Down); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// LoadMigrationsFromFS loads NNN_name.sql files from dir, pairing each with an optional NNN_name.down.sql.
 func (m *Migrator) LoadMigrationsFromFS(migrationFS fs.FS, dir string) error {
 	entries, err := fs.ReadDir(migrationFS, dir)
 	if err != nil {
 		return fmt.Errorf("failed to read migration directory: %w", err)
 	}
 
+	ups := make(map[string]string)
+	downs := make(map[string]string)
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
 			continue
 		}
 
-		version := strings.TrimSuffix(entry.Name(), ".sql")
 		path := filepath.Join(dir, entry.Name())
-
 		content, err := fs.ReadFile(migrationFS, path)
 		if err != nil {
 			return fmt.Errorf("failed to read migration file %s: %w", path, err)
 		}
 
-		m.AddMigration(version, string(content))
+		if version, ok := strings.CutSuffix(entry.Name(), downSuffix); ok {
+			downs[version] = string(content)
+			continue
+		}
+		ups[strings.TrimSuffix(entry.Name(), ".sql")] = string(content)
+	}
+
+	for version := range downs {
+		if _, ok := ups[version]; !ok {
+			return fmt.Errorf("down migration %s has no matching %s.sql", version+downSuffix, version)
+		}
+	}
+
+	for version, up := range ups {
+		m.migrations = append(m.migrations, Migration{
+			Version: version,
+			SQL:     up,
+			Down:    downs[version],
+		})
 	}
 
 	return nil
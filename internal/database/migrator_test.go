@@ -0,0 +1,243 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	kbudailymenu "github.com/artyom-kalman/kbu-daily-menu"
+)
+
+func newTestMigrator(t *testing.T, files fstest.MapFS) (*Database, *Migrator) {
+	t.Helper()
+
+	db, err := NewDatabase(filepath.Join(t.TempDir(), "migrations.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db, loadMigrator(t, db, files)
+}
+
+func loadMigrator(t *testing.T, db *Database, files fstest.MapFS) *Migrator {
+	t.Helper()
+
+	migrator := NewMigrator(db)
+	if err := migrator.LoadMigrationsFromFS(files, "migrations"); err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	return migrator
+}
+
+func sqlFile(content string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(content)}
+}
+
+func tableExists(t *testing.T, db *Database, name string) bool {
+	t.Helper()
+
+	var count int
+	if err := db.Conn.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count); err != nil {
+		t.Fatalf("query sqlite_master: %v", err)
+	}
+	return count > 0
+}
+
+func TestMigratorUpDown(t *testing.T) {
+	db, migrator := newTestMigrator(t, fstest.MapFS{
+		"migrations/001_create_a.sql":      sqlFile("CREATE TABLE a (id INTEGER);"),
+		"migrations/001_create_a.down.sql": sqlFile("DROP TABLE a;"),
+		"migrations/002_create_b.sql":      sqlFile("CREATE TABLE b (id INTEGER);"),
+		"migrations/002_create_b.down.sql": sqlFile("DROP TABLE b;"),
+		"migrations/003_create_c.sql":      sqlFile("CREATE TABLE c (id INTEGER);"),
+	})
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	// 003 has no down file, so nothing is rolled back.
+	if err := migrator.Down(2); err == nil || !strings.Contains(err.Error(), "003_create_c.down.sql") {
+		t.Fatalf("Down(2) error = %v, want missing down file", err)
+	}
+	if !tableExists(t, db, "c") || !tableExists(t, db, "b") {
+		t.Fatalf("failed Down changed the schema")
+	}
+
+	if _, err := db.Conn.Exec("DROP TABLE c; DELETE FROM schema_migrations WHERE version = '003_create_c'"); err != nil {
+		t.Fatalf("drop c: %v", err)
+	}
+	if err := migrator.Down(1); err != nil {
+		t.Fatalf("Down(1): %v", err)
+	}
+	if tableExists(t, db, "b") || !tableExists(t, db, "a") {
+		t.Fatalf("Down(1) did not roll back only 002")
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	want := []MigrationStatus{
+		{Version: "001_create_a", Applied: true, Reversible: true},
+		{Version: "002_create_b", Reversible: true},
+		{Version: "003_create_c"},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("Status = %+v, want %+v", statuses, want)
+	}
+	for i, status := range statuses {
+		status.AppliedAt = want[i].AppliedAt
+		if status != want[i] {
+			t.Errorf("Status[%d] = %+v, want %+v", i, status, want[i])
+		}
+	}
+
+	if err := migrator.Up(); err != nil || !tableExists(t, db, "b") {
+		t.Fatalf("Up after Down: %v", err)
+	}
+}
+
+func TestMigratorRejectsEditedMigration(t *testing.T) {
+	db, migrator := newTestMigrator(t, fstest.MapFS{
+		"migrations/001_create_a.sql": sqlFile("CREATE TABLE a (id INTEGER);"),
+	})
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	edited := loadMigrator(t, db, fstest.MapFS{
+		"migrations/001_create_a.sql": sqlFile("CREATE TABLE a (id INTEGER, name TEXT);"),
+		"migrations/002_create_b.sql": sqlFile("CREATE TABLE b (id INTEGER);"),
+	})
+	if err := edited.Up(); err == nil || !strings.Contains(err.Error(), "001_create_a was modified") {
+		t.Fatalf("Up error = %v, want modified migration", err)
+	}
+	if tableExists(t, db, "b") {
+		t.Fatalf("pending migration applied after a checksum mismatch")
+	}
+
+	statuses, err := edited.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !statuses[0].Modified || statuses[1].Applied {
+		t.Fatalf("Status = %+v, want 001 modified and 002 pending", statuses)
+	}
+}
+
+func TestMigratorRejectsDuplicatePrefix(t *testing.T) {
+	_, migrator := newTestMigrator(t, fstest.MapFS{
+		"migrations/002_create_a.sql": sqlFile("CREATE TABLE a (id INTEGER);"),
+		"migrations/002_create_b.sql": sqlFile("CREATE TABLE b (id INTEGER);"),
+	})
+
+	if err := migrator.Up(); err == nil || !strings.Contains(err.Error(), "duplicate migration version 002") {
+		t.Fatalf("Up error = %v, want duplicate version", err)
+	}
+}
+
+func TestMigratorRejectsOrphanDownFile(t *testing.T) {
+	db, err := NewDatabase(filepath.Join(t.TempDir(), "migrations.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	err = NewMigrator(db).LoadMigrationsFromFS(fstest.MapFS{
+		"migrations/001_create_a.down.sql": sqlFile("DROP TABLE a;"),
+	}, "migrations")
+	if err == nil {
+		t.Fatalf("loaded a down migration without its up file")
+	}
+}
+
+// Databases migrated by older releases have no checksums and recorded the unique index as a 002 migration.
+func TestMigratorUpgradesLegacyTable(t *testing.T) {
+	db, err := NewDatabase(filepath.Join(t.TempDir(), "legacy.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	legacy := NewMigrator(db)
+	for _, name := range []string{"001_create_menu_table", "011_menu_unique_index"} {
+		content, err := os.ReadFile(filepath.Join("..", "..", "migrations", name+".sql"))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if _, err := db.Conn.Exec(string(content)); err != nil {
+			t.Fatalf("apply %s: %v", name, err)
+		}
+	}
+	if _, err := db.Conn.Exec(`
+		CREATE TABLE schema_migrations (version TEXT PRIMARY KEY, applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP);
+		INSERT INTO schema_migrations (version) VALUES ('001_create_menu_table'), ('002_menu_unique_index');
+	`); err != nil {
+		t.Fatalf("create legacy migrations table: %v", err)
+	}
+
+	if err := legacy.LoadMigrationsFromFS(kbudailymenu.Assets(""), "migrations"); err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if err := legacy.Up(); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	statuses, err := legacy.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.Modified || status.Missing {
+			t.Errorf("%s: %+v, want applied", status.Version, status)
+		}
+	}
+
+	var missing int
+	if err := db.Conn.QueryRow("SELECT COUNT(*) FROM schema_migrations WHERE checksum IS NULL").Scan(&missing); err != nil {
+		t.Fatalf("count checksums: %v", err)
+	}
+	if missing != 0 {
+		t.Errorf("%d applied migrations have no checksum", missing)
+	}
+}
+
+func TestRepositoryMigrationsRoundTrip(t *testing.T) {
+	db, err := NewDatabase(filepath.Join(t.TempDir(), "menu.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	migrator := NewMigrator(db)
+	if err := migrator.LoadMigrationsFromFS(kbudailymenu.Assets(""), "migrations"); err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if err := migrator.Down(len(statuses)); err != nil {
+		t.Fatalf("Down(%d): %v", len(statuses), err)
+	}
+
+	var tables int
+	if err := db.Conn.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence')").Scan(&tables); err != nil {
+		t.Fatalf("count tables: %v", err)
+	}
+	if tables != 0 {
+		t.Errorf("%d tables left after rolling back every migration", tables)
+	}
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up after Down: %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"io/fs"
+	"net/http"
+	"os"
+)
+
+// onlyFiles serves files from an fs.FS without listing directories, like gin.Static does for disk paths.
+type onlyFiles struct {
+	fs http.FileSystem
+}
+
+func assetFileSystem(assets fs.FS, dir string) http.FileSystem {
+	sub, err := fs.Sub(assets, dir)
+	if err != nil {
+		// Only an invalid dir fails; serve nothing rather than the whole tree.
+		sub = emptyFS{}
+	}
+	return onlyFiles{fs: http.FS(sub)}
+}
+
+func (o onlyFiles) Open(name string) (http.File, error) {
+	file, err := o.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return noReaddirFile{file}, nil
+}
+
+type noReaddirFile struct {
+	http.File
+}
+
+func (noReaddirFile) Readdir(int) ([]os.FileInfo, error) {
+	return nil, nil
+}
+
+type emptyFS struct{}
+
+func (emptyFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
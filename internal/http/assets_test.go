@@ -0,0 +1,78 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	kbudailymenu "github.com/artyom-kalman/kbu-daily-menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+func TestServerUsesEmbeddedAssetsOutsideRepository(t *testing.T) {
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	defer os.Chdir(root)
+
+	server := NewServer(nil, &fakeMenuService{
+		peony:  menu.NewMenuFromDishes([]string{"김치찌개"}, nil),
+		azilea: menu.NewMenuFromDishes([]string{"오늘은 휴무입니다"}, nil),
+	})
+	server.SetAssets(kbudailymenu.Assets(""))
+	server.SetupRouter()
+
+	tests := []struct {
+		path     string
+		status   int
+		contains string
+		excludes string
+	}{
+		{path: "/", status: http.StatusOK, contains: "김치찌개"},
+		{path: "/static/styles.css", status: http.StatusOK},
+		{path: "/static/stickers/", status: http.StatusNotFound, excludes: "kto.webp"},
+		{path: "/static/missing.css", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		body := recorder.Body.String()
+		if recorder.Code != tt.status {
+			t.Errorf("GET %s status = %d, want %d", tt.path, recorder.Code, tt.status)
+		}
+		if !strings.Contains(body, tt.contains) {
+			t.Errorf("GET %s body does not contain %q", tt.path, tt.contains)
+		}
+		if tt.excludes != "" && strings.Contains(body, tt.excludes) {
+			t.Errorf("GET %s lists the directory: %s", tt.path, body)
+		}
+	}
+}
+
+func TestServerAssetsOverride(t *testing.T) {
+	server := NewServer(nil, &fakeMenuService{
+		peony:  menu.NewMenuFromDishes([]string{"김치찌개"}, nil),
+		azilea: menu.NewMenuFromDishes([]string{"비빔밥"}, nil),
+	})
+	server.SetAssets(fstest.MapFS{
+		"templates/index.html":  {Data: []byte(`draft index`)},
+		"web/dist/tailwind.css": {Data: []byte(`body{}`)},
+	})
+	server.SetupRouter()
+
+	for path, want := range map[string]string{"/": "draft index", "/dist/tailwind.css": "body{}"} {
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusOK || recorder.Body.String() != want {
+			t.Errorf("GET %s = %d %q, want %q", path, recorder.Code, recorder.Body.String(), want)
+		}
+	}
+}
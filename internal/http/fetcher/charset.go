@@ -0,0 +1,92 @@
+package fetcher
+
+import (
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/korean"
+)
+
+// charsetSniffLength follows the HTML spec, which looks for <meta charset> in the first 1024 bytes.
+const charsetSniffLength = 1024
+
+var metaCharsetRegex = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([a-z0-9_:.-]+)`)
+
+// charsetAliases covers labels Korean sites use that the WHATWG index does not know.
+var charsetAliases = map[string]encoding.Encoding{
+	"cp949":         korean.EUCKR,
+	"ms949":         korean.EUCKR,
+	"uhc":           korean.EUCKR,
+	"x-windows-949": korean.EUCKR,
+}
+
+// DecodeBody converts a page to UTF-8. The charset comes from the Content-Type header, then from a
+// <meta> tag; undeclared pages are UTF-8 when valid and EUC-KR otherwise, the usual legacy Korean encoding.
+func DecodeBody(data []byte, contentType string) (string, error) {
+	label := charsetFromContentType(contentType)
+	if label == "" {
+		label = charsetFromMeta(data)
+	}
+
+	var enc encoding.Encoding
+	switch {
+	case label != "":
+		var err error
+		if enc, err = lookupCharset(label); err != nil {
+			return "", err
+		}
+	case utf8.Valid(data):
+		return string(data), nil
+	default:
+		enc = korean.EUCKR
+	}
+
+	if enc == encoding.Nop || strings.EqualFold(label, "utf-8") || strings.EqualFold(label, "utf8") {
+		return string(data), nil
+	}
+
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", fmt.Errorf("decode %s body: %w", label, err)
+	}
+	return string(decoded), nil
+}
+
+func charsetFromContentType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return params["charset"]
+}
+
+func charsetFromMeta(data []byte) string {
+	if len(data) > charsetSniffLength {
+		data = data[:charsetSniffLength]
+	}
+	if match := metaCharsetRegex.FindSubmatch(data); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+func lookupCharset(label string) (encoding.Encoding, error) {
+	label = strings.ToLower(strings.TrimSpace(label))
+	if enc, ok := charsetAliases[label]; ok {
+		return enc, nil
+	}
+
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %q: %w", label, err)
+	}
+	return enc, nil
+}
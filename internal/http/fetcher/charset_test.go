@@ -0,0 +1,72 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return data
+}
+
+func TestDecodeBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		fixture     string
+		contentType string
+	}{
+		{name: "utf-8 meta", fixture: "menu_utf8.html", contentType: "text/html"},
+		{name: "utf-8 header", fixture: "menu_utf8.html", contentType: "text/html; charset=UTF-8"},
+		{name: "euc-kr meta", fixture: "menu_euckr.html", contentType: "text/html"},
+		{name: "euc-kr header", fixture: "menu_euckr.html", contentType: "text/html; charset=EUC-KR"},
+		{name: "cp949 header", fixture: "menu_euckr.html", contentType: "text/html; charset=cp949"},
+		{name: "ks_c_5601 header", fixture: "menu_euckr.html", contentType: "text/html; charset=ks_c_5601-1987"},
+		{name: "undeclared euc-kr", fixture: "menu_euckr_undeclared.html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := DecodeBody(readFixture(t, tt.fixture), tt.contentType)
+			if err != nil {
+				t.Fatalf("DecodeBody() error = %v", err)
+			}
+			for _, dish := range []string{"김치찌개", "돈까스", "쌀밥"} {
+				if !strings.Contains(body, dish) {
+					t.Errorf("decoded body does not contain %q", dish)
+				}
+			}
+		})
+	}
+
+	if _, err := DecodeBody([]byte("<html></html>"), "text/html; charset=klingon"); err == nil {
+		t.Error("DecodeBody() with an unknown charset succeeded, want an error")
+	}
+}
+
+func TestFetchPageTranscodesEUCKR(t *testing.T) {
+	fixture := readFixture(t, "menu_euckr.html")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=euc-kr")
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	page, err := NewHTTPFetcher(server.URL).FetchPage(context.Background())
+	if err != nil {
+		t.Fatalf("FetchPage() error = %v", err)
+	}
+	if !strings.Contains(page.Body, `<li class="foodItem">김치찌개</li>`) {
+		t.Errorf("FetchPage() body is not UTF-8: %q", page.Body)
+	}
+}
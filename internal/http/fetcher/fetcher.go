@@ -1,12 +1,20 @@
 package fetcher
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/retry"
 )
 
 const (
@@ -14,79 +22,135 @@ const (
 	httpMaxResponseSize = 10 * 1024 * 1024
 	httpRetryAttempts   = 3
 	httpRetryDelay      = 2 * time.Second
+	httpMaxRetryDelay   = 10 * time.Second
 )
 
+// Page is a fetched page body. Unchanged is set when the server or the content hash
+// shows the body is the same as the previous fetch, so callers can reuse what they derived from it.
+type Page struct {
+	Body      string
+	Hash      string
+	FetchedAt time.Time
+	Unchanged bool
+}
+
+// SnapshotStore keeps every distinct page body so parsing can be replayed later.
+type SnapshotStore interface {
+	SaveSnapshot(url, hash, body string, fetchedAt time.Time) error
+}
+
 type HTTPFetcher struct {
 	url        string
 	httpClient *http.Client
+	retry      retry.Policy
+
+	mu sync.Mutex
+	// last holds the previous page with its validators for conditional requests.
+	last         *Page
+	etag         string
+	lastModified string
+	snapshots    SnapshotStore
 }
 
-func NewHTTPFetcher(url string) *HTTPFetcher {
+func NewHTTPFetcher(rawURL string) *HTTPFetcher {
 	client := &http.Client{
 		Timeout: httpDefaultTimeout,
 		Transport: &http.Transport{
-			MaxIdleConns:       10,
-			IdleConnTimeout:    30 * time.Second,
-			DisableCompression: false,
+			MaxIdleConns:    10,
+			IdleConnTimeout: 30 * time.Second,
+			// Compression is negotiated by the fetcher itself, see fetchAttempt.
+			DisableCompression: true,
 		},
 	}
 
 	return &HTTPFetcher{
-		url:        url,
+		url:        rawURL,
 		httpClient: client,
+		retry: retry.Policy{
+			Name:      "fetch " + rawURL,
+			Attempts:  httpRetryAttempts,
+			BaseDelay: httpRetryDelay,
+			MaxDelay:  httpMaxRetryDelay,
+			Breaker:   retry.BreakerFor(hostOf(rawURL)),
+		},
 	}
 }
 
+// EnableSnapshots stores each distinct page body. Failing to store one is logged and does not fail the fetch.
+func (f *HTTPFetcher) EnableSnapshots(store SnapshotStore) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.snapshots = store
+}
+
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return rawURL
+	}
+	return parsed.Host
+}
+
 func (f *HTTPFetcher) Fetch() (string, error) {
 	return f.FetchWithContext(context.Background())
 }
 
 func (f *HTTPFetcher) FetchWithContext(ctx context.Context) (string, error) {
-	var lastErr error
-	for attempt := 1; attempt <= httpRetryAttempts; attempt++ {
-		if attempt > 1 {
-			slog.Warn("Retry attempt for URL",
-				"attempt", attempt,
-				"max_attempts", httpRetryAttempts,
-				"url", f.url)
-			select {
-			case <-ctx.Done():
-				return "", ctx.Err()
-			case <-time.After(httpRetryDelay):
-			}
-		}
-
-		body, err := f.fetchAttempt(ctx)
-		if err == nil {
-			slog.Debug("Successfully fetched content",
-				"bytes", len(body))
-			return body, nil
-		}
+	page, err := f.FetchPage(ctx)
+	if err != nil {
+		return "", err
+	}
+	return page.Body, nil
+}
 
-		lastErr = err
-		slog.Error("Fetch attempt failed",
+// FetchPage downloads the page unless the server confirms the previous copy is still current.
+func (f *HTTPFetcher) FetchPage(ctx context.Context) (*Page, error) {
+	var page *Page
+	err := retry.Do(ctx, f.retry, func(ctx context.Context) error {
+		var err error
+		page, err = f.fetchAttempt(ctx)
+		return err
+	})
+	if err != nil {
+		slog.Error("Failed to fetch URL",
 			"error", err,
-			"attempt", attempt)
+			"class", retry.Classify(err).String(),
+			"url", f.url)
+		return nil, err
 	}
 
-	slog.Error("All fetch attempts failed for URL",
-		"error", lastErr,
-		"url", f.url)
-	return "", fmt.Errorf("failed to fetch content after %d attempts: %w", httpRetryAttempts, lastErr)
+	slog.Debug("Successfully fetched content",
+		"bytes", len(page.Body),
+		"unchanged", page.Unchanged)
+	return page, nil
 }
 
-func (f *HTTPFetcher) fetchAttempt(ctx context.Context) (string, error) {
+func (f *HTTPFetcher) fetchAttempt(ctx context.Context) (*Page, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("User-Agent", "KBU-Daily-Menu/1.0")
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	f.mu.Lock()
+	last := f.last
+	if last != nil {
+		if f.etag != "" {
+			req.Header.Set("If-None-Match", f.etag)
+		}
+		if f.lastModified != "" {
+			req.Header.Set("If-Modified-Since", f.lastModified)
+		}
+	}
+	f.mu.Unlock()
 
 	resp, err := f.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("HTTP request failed: %w", err)
+		return nil, retry.Mark(retry.Network, fmt.Errorf("HTTP request failed: %w", err))
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -94,26 +158,67 @@ func (f *HTTPFetcher) fetchAttempt(ctx context.Context) (string, error) {
 		}
 	}()
 
+	if resp.StatusCode == http.StatusNotModified && last != nil {
+		slog.Debug("Page not modified", "url", f.url)
+		return &Page{Body: last.Body, Hash: last.Hash, FetchedAt: time.Now(), Unchanged: true}, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		slog.Error("Received non-200 status code",
 			"status_code", resp.StatusCode,
 			"url", f.url)
-		return "", fmt.Errorf("HTTP request failed with status %d", resp.StatusCode)
+		return nil, retry.HTTPStatus(resp.StatusCode, fmt.Errorf("HTTP request failed with status %d", resp.StatusCode))
 	}
 
-	body, err := f.readResponseBody(resp.Body)
+	data, err := f.readResponseBody(resp)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return nil, retry.Mark(retry.Network, fmt.Errorf("failed to read response body: %w", err))
 	}
 
-	return body, nil
+	body, err := DecodeBody(data, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, retry.Mark(retry.Parse, err)
+	}
+
+	sum := sha256.Sum256([]byte(body))
+	page := &Page{
+		Body:      body,
+		Hash:      hex.EncodeToString(sum[:]),
+		FetchedAt: time.Now(),
+	}
+	page.Unchanged = last != nil && last.Hash == page.Hash
+
+	f.mu.Lock()
+	f.last = page
+	f.etag = resp.Header.Get("ETag")
+	f.lastModified = resp.Header.Get("Last-Modified")
+	snapshots := f.snapshots
+	f.mu.Unlock()
+
+	if snapshots != nil && !page.Unchanged {
+		if err := snapshots.SaveSnapshot(f.url, page.Hash, page.Body, page.FetchedAt); err != nil {
+			slog.Error("Failed to save page snapshot", "error", err, "url", f.url)
+		}
+	}
+
+	return page, nil
 }
 
-func (f *HTTPFetcher) readResponseBody(body io.Reader) (string, error) {
+func (f *HTTPFetcher) readResponseBody(resp *http.Response) ([]byte, error) {
+	var body io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip body: %w", err)
+		}
+		defer gzipReader.Close()
+		body = gzipReader
+	}
+
 	limitedReader := io.LimitReader(body, httpMaxResponseSize)
 	data, err := io.ReadAll(limitedReader)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if len(data) == httpMaxResponseSize {
@@ -121,5 +226,5 @@ func (f *HTTPFetcher) readResponseBody(body io.Reader) (string, error) {
 			"max_bytes", httpMaxResponseSize)
 	}
 
-	return string(data), nil
+	return data, nil
 }
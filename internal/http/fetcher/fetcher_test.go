@@ -0,0 +1,119 @@
+package fetcher
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/retry"
+)
+
+func TestFetchRetriesOnlyTransientStatuses(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantCalls int
+		wantClass retry.Class
+		wantErr   bool
+	}{
+		{name: "recovers from 503", statuses: []int{503, 200}, wantCalls: 2},
+		{name: "does not retry 404", statuses: []int{404}, wantCalls: 1, wantClass: retry.Client, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statuses[min(calls, len(tt.statuses)-1)])
+				calls++
+			}))
+			defer server.Close()
+
+			fetcher := NewHTTPFetcher(server.URL)
+			fetcher.retry.BaseDelay = time.Millisecond
+
+			_, err := fetcher.FetchWithContext(context.Background())
+			if calls != tt.wantCalls || (err != nil) != tt.wantErr {
+				t.Fatalf("made %d requests and returned %v; want %d requests, error %v", calls, err, tt.wantCalls, tt.wantErr)
+			}
+			if err != nil && retry.Classify(err) != tt.wantClass {
+				t.Errorf("Classify() = %s, want %s", retry.Classify(err), tt.wantClass)
+			}
+		})
+	}
+}
+
+type recordingStore struct {
+	hashes []string
+}
+
+func (s *recordingStore) SaveSnapshot(url, hash, body string, fetchedAt time.Time) error {
+	s.hashes = append(s.hashes, hash)
+	return nil
+}
+
+func TestFetchPageUsesConditionalRequests(t *testing.T) {
+	body := "<ul class=\"foodList\">v1</ul>"
+	var conditional []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		etag := `"` + body + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	store := &recordingStore{}
+	fetcher := NewHTTPFetcher(server.URL)
+	fetcher.EnableSnapshots(store)
+
+	first, err := fetcher.FetchPage(context.Background())
+	if err != nil || first.Unchanged || first.Body != body {
+		t.Fatalf("first FetchPage() = %+v, %v", first, err)
+	}
+
+	second, err := fetcher.FetchPage(context.Background())
+	if err != nil || !second.Unchanged || second.Body != body || second.Hash != first.Hash {
+		t.Fatalf("FetchPage() after 304 = %+v, %v; want the cached page", second, err)
+	}
+	if conditional[0] != "" || conditional[1] == "" {
+		t.Errorf("If-None-Match headers = %q, want only the second request to be conditional", conditional)
+	}
+
+	body = "<ul class=\"foodList\">v2</ul>"
+	third, err := fetcher.FetchPage(context.Background())
+	if err != nil || third.Unchanged || third.Body != body {
+		t.Fatalf("FetchPage() after a change = %+v, %v", third, err)
+	}
+
+	if len(store.hashes) != 2 || store.hashes[0] != first.Hash || store.hashes[1] != third.Hash {
+		t.Errorf("stored snapshots %q, want one per distinct body", store.hashes)
+	}
+}
+
+func TestFetchPageDecodesGzip(t *testing.T) {
+	const body = "<html>식단표</html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.Write([]byte(body))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		writer.Write([]byte(body))
+		writer.Close()
+	}))
+	defer server.Close()
+
+	page, err := NewHTTPFetcher(server.URL).FetchPage(context.Background())
+	if err != nil || page.Body != body {
+		t.Fatalf("FetchPage() = %+v, %v; want the decoded body", page, err)
+	}
+}
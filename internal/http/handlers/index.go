@@ -3,6 +3,7 @@ package handlers
 import (
 	"log/slog"
 
+	"github.com/artyom-kalman/kbu-daily-menu/internal/compare"
 	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
 	"github.com/gin-gonic/gin"
 )
@@ -12,7 +13,8 @@ type MenuService interface {
 	GetAzileaMenu() (*menu.Menu, error)
 }
 
-func HandleIndex(menuService MenuService) gin.HandlerFunc {
+// HandleIndex renders today's menus; ratings and history may be nil when those features are disabled.
+func HandleIndex(menuService MenuService, ratings RatingService, history MenuHistory, clock menu.Clock) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		slog.Info("Received request")
 
@@ -33,9 +35,34 @@ func HandleIndex(menuService MenuService) gin.HandlerFunc {
 			}
 		}
 
-		c.HTML(200, "index.html", map[string]*menu.Menu{
-			"Peony":  peonyMenu,
-			"Azilea": azileaMenu,
+		if ratings != nil {
+			peonyMenu = withRatings(ratings, peonyMenu)
+			azileaMenu = withRatings(ratings, azileaMenu)
+		}
+
+		// Visitors are anonymous, so the advice only considers ratings and the menus themselves.
+		verdict := compare.Compare(peonyMenu, azileaMenu, compare.DefaultPreferences())
+
+		c.HTML(200, "index.html", gin.H{
+			"Peony":        peonyMenu,
+			"Azilea":       azileaMenu,
+			"Ratings":      ratings != nil,
+			"Verdict":      verdict,
+			"PeonyChange":  changeNotice(history, clock, menu.PEONY),
+			"AzileaChange": changeNotice(history, clock, menu.AZILEA),
 		})
 	}
 }
+
+func withRatings(ratings RatingService, cafeteriaMenu *menu.Menu) *menu.Menu {
+	if cafeteriaMenu == nil {
+		return nil
+	}
+
+	rated, err := ratings.WithRatings(cafeteriaMenu)
+	if err != nil {
+		slog.Error("Error loading dish ratings", "error", err)
+		return cafeteriaMenu
+	}
+	return rated
+}
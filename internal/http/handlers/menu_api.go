@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+	"github.com/gin-gonic/gin"
+)
+
+const nutritionNote = "Калории и БЖУ — приблизительная оценка для стандартной порции"
+
+type cafeteriaResponse struct {
+	Open          bool             `json:"open"`
+	Dishes        []*menu.MenuItem `json:"dishes"`
+	TrayNutrition *menu.Nutrition  `json:"tray_nutrition,omitempty"`
+}
+
+type menuResponse struct {
+	Peony  *cafeteriaResponse `json:"peony"`
+	Azilea *cafeteriaResponse `json:"azilea"`
+	// Nutrition values are always estimates; the flag lets clients label them without parsing the note.
+	NutritionIsEstimate bool   `json:"nutrition_is_estimate"`
+	NutritionNote       string `json:"nutrition_note"`
+}
+
+// HandleMenuAPI returns today's menus as JSON; ratings may be nil when dish ratings are disabled.
+func HandleMenuAPI(menuService MenuService, ratings RatingService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		peonyMenu, err := menuService.GetPeonyMenu()
+		if err != nil {
+			slog.Error("Error getting Peony menu", "error", err)
+			c.AbortWithStatus(http.StatusBadGateway)
+			return
+		}
+
+		azileaMenu, err := menuService.GetAzileaMenu()
+		if err != nil {
+			slog.Error("Error getting Azilea menu", "error", err)
+			c.AbortWithStatus(http.StatusBadGateway)
+			return
+		}
+
+		if ratings != nil {
+			peonyMenu = withRatings(ratings, peonyMenu)
+			azileaMenu = withRatings(ratings, azileaMenu)
+		}
+
+		c.JSON(http.StatusOK, menuResponse{
+			Peony:               newCafeteriaResponse(peonyMenu),
+			Azilea:              newCafeteriaResponse(azileaMenu),
+			NutritionIsEstimate: true,
+			NutritionNote:       nutritionNote,
+		})
+	}
+}
+
+func newCafeteriaResponse(cafeteriaMenu *menu.Menu) *cafeteriaResponse {
+	if cafeteriaMenu == nil || len(cafeteriaMenu.Items) <= 1 {
+		return &cafeteriaResponse{Dishes: []*menu.MenuItem{}}
+	}
+
+	return &cafeteriaResponse{
+		Open:          true,
+		Dishes:        cafeteriaMenu.Items,
+		TrayNutrition: cafeteriaMenu.TrayNutrition(),
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+	"github.com/gin-gonic/gin"
+)
+
+// MenuCardService only reads stored menus, so requests for arbitrary dates never reach the cafeteria pages.
+type MenuCardService interface {
+	GetStoredMenuForDate(cafeteria menu.Cafeteria, date time.Time) (*menu.Menu, error)
+}
+
+type MenuCardRenderer interface {
+	Render(date time.Time, peony, azilea *menu.Menu) ([]byte, error)
+}
+
+// HandleMenuCard serves /menu/{date}.png, where date is YYYY-MM-DD or "today".
+// Only stored menus of the current week are rendered; any other date is not found.
+func HandleMenuCard(menuService MenuCardService, renderer MenuCardRenderer, clock menu.Clock) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name, isPNG := strings.CutSuffix(c.Param("file"), ".png")
+		if !isPNG {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		now := clock.Now()
+		date := now
+		if name != "today" {
+			parsed, err := time.ParseInLocation("2006-01-02", name, now.Location())
+			if err != nil {
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+			date = parsed
+		}
+
+		if !menu.InCurrentWeek(date, now) {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		peony, err := menuService.GetStoredMenuForDate(menu.PEONY, date)
+		if err != nil {
+			slog.Error("Failed to load peony menu for menu card", "date", name, "error", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		azilea, err := menuService.GetStoredMenuForDate(menu.AZILEA, date)
+		if err != nil {
+			slog.Error("Failed to load azilea menu for menu card", "date", name, "error", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		if peony == nil && azilea == nil {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		card, err := renderer.Render(date, peony, azilea)
+		if err != nil {
+			slog.Error("Failed to render menu card", "date", name, "error", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.Header("Cache-Control", "public, max-age=300")
+		c.Data(http.StatusOK, "image/png", card)
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+	"github.com/gin-gonic/gin"
+)
+
+type MenuHistory interface {
+	GetRevisionsForDate(cafeteria menu.Cafeteria, date time.Time) ([]menu.MenuRevision, error)
+}
+
+// HandleMenuRevisions serves /api/menu/revisions?cafeteria=peony&date=YYYY-MM-DD; the date defaults to today.
+func HandleMenuRevisions(history MenuHistory, clock menu.Clock) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cafeteria := menu.Cafeteria(c.Query("cafeteria"))
+		if cafeteria != menu.PEONY && cafeteria != menu.AZILEA {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cafeteria must be peony or azilea"})
+			return
+		}
+
+		date := clock.Now()
+		if value := c.Query("date"); value != "" {
+			parsed, err := time.ParseInLocation("2006-01-02", value, date.Location())
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD"})
+				return
+			}
+			date = parsed
+		}
+
+		revisions, err := history.GetRevisionsForDate(cafeteria, date)
+		if err != nil {
+			slog.Error("Failed to load menu revisions", "cafeteria", string(cafeteria), "error", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		if revisions == nil {
+			revisions = []menu.MenuRevision{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"cafeteria": cafeteria,
+			"date":      date.Format("2006-01-02"),
+			"revisions": revisions,
+		})
+	}
+}
+
+// changeNotice describes the latest correction of today's menu, such as "Обновлено в 10:42: +김치찌개, −된장국".
+// The first publication is not a change worth pointing out, so it yields an empty notice.
+func changeNotice(history MenuHistory, clock menu.Clock, cafeteria menu.Cafeteria) string {
+	if history == nil {
+		return ""
+	}
+
+	now := clock.Now()
+	revisions, err := history.GetRevisionsForDate(cafeteria, now)
+	if err != nil {
+		slog.Error("Failed to load menu revisions", "cafeteria", string(cafeteria), "error", err)
+		return ""
+	}
+	if len(revisions) < 2 {
+		return ""
+	}
+
+	latest, previous := revisions[len(revisions)-1], revisions[len(revisions)-2]
+	if !latest.IsCorrection(&previous) {
+		return ""
+	}
+	return fmt.Sprintf("Обновлено в %s: %s", latest.RevisedAt.In(now.Location()).Format("15:04"), latest.Summary())
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/rating"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	sessionCookie   = "kbu_session"
+	sessionMaxAge   = 365 * 24 * 60 * 60
+	topDishesOnPage = 20
+)
+
+type RatingService interface {
+	Rate(ctx context.Context, cafeteria menu.Cafeteria, date time.Time, dishName, rater string, score int) (menu.DishRating, error)
+	WithRatings(cafeteriaMenu *menu.Menu) (*menu.Menu, error)
+	TopDishesThisMonth(limit int) ([]rating.DishScore, error)
+}
+
+type rateRequest struct {
+	Cafeteria string `json:"cafeteria"`
+	Dish      string `json:"dish"`
+	Score     int    `json:"score"`
+}
+
+// HandleRateDish stores a rating for one of today's dishes.
+// Visitors are anonymous, so repeated votes are told apart by a session cookie.
+func HandleRateDish(service RatingService, clock menu.Clock) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request rateRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		cafeteria := menu.Cafeteria(request.Cafeteria)
+		if cafeteria != menu.PEONY && cafeteria != menu.AZILEA {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		session, err := ratingSession(c)
+		if err != nil {
+			slog.Error("Failed to create rating session", "error", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		summary, err := service.Rate(c.Request.Context(), cafeteria, clock.Now(), request.Dish, "web:"+session, request.Score)
+		if errors.Is(err, rating.ErrInvalidScore) || errors.Is(err, rating.ErrUnknownDish) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to rate dish", "dish", request.Dish, "error", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, summary)
+	}
+}
+
+func ratingSession(c *gin.Context) (string, error) {
+	if session, err := c.Cookie(sessionCookie); err == nil && session != "" {
+		return session, nil
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	session := hex.EncodeToString(buf)
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(sessionCookie, session, sessionMaxAge, "/", "", false, true)
+	return session, nil
+}
+
+func HandleTopDishes(service RatingService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dishes, err := service.TopDishesThisMonth(topDishesOnPage)
+		if err != nil {
+			slog.Error("Failed to load top dishes", "error", err)
+		}
+
+		c.HTML(http.StatusOK, "top.html", gin.H{
+			"Dishes": dishes,
+		})
+	}
+}
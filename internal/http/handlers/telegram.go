@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	TelegramSecretHeader  = "X-Telegram-Bot-Api-Secret-Token"
+	maxTelegramUpdateSize = 1 << 20
+)
+
+// TelegramUpdateHandler accepts an update and returns once it is queued; an error means the payload is malformed
+// or the bot is shutting down, and Telegram delivers the update again later.
+type TelegramUpdateHandler interface {
+	HandleWebhookUpdate(payload []byte) error
+}
+
+func HandleTelegramWebhook(updateHandler TelegramUpdateHandler, secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(TelegramSecretHeader)
+		if secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			slog.Warn("Rejected Telegram webhook request with invalid secret token", "remote_addr", c.ClientIP())
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxTelegramUpdateSize))
+		if err != nil {
+			slog.Error("Failed to read Telegram update", "error", err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		if err := updateHandler.HandleWebhookUpdate(payload); err != nil {
+			slog.Error("Rejected Telegram update", "error", err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
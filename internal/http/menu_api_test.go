@@ -0,0 +1,83 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	kbudailymenu "github.com/artyom-kalman/kbu-daily-menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+func newNutritionTestServer() *Server {
+	menus := &fakeMenuService{
+		peony: menu.NewMenu([]*menu.MenuItem{
+			{Name: "김치찌개", Nutrition: &menu.Nutrition{Calories: 250, Protein: 16, Carbs: 10, Fat: 15}},
+			{Name: "쌀밥", Nutrition: &menu.Nutrition{Calories: 300, Protein: 5, Carbs: 66, Fat: 1}},
+		}, nil),
+		azilea: menu.NewMenuFromDishes([]string{"오늘은 휴무입니다"}, nil),
+	}
+
+	server := NewServer(nil, menus)
+	server.SetAssets(kbudailymenu.Assets(""))
+	server.SetupRouter()
+	return server
+}
+
+func TestMenuAPI(t *testing.T) {
+	server := newNutritionTestServer()
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/menu", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", recorder.Code)
+	}
+
+	var response struct {
+		Peony struct {
+			Open          bool            `json:"open"`
+			Dishes        []menu.MenuItem `json:"dishes"`
+			TrayNutrition *menu.Nutrition `json:"tray_nutrition"`
+		} `json:"peony"`
+		Azilea struct {
+			Open          bool            `json:"open"`
+			Dishes        []menu.MenuItem `json:"dishes"`
+			TrayNutrition *menu.Nutrition `json:"tray_nutrition"`
+		} `json:"azilea"`
+		NutritionIsEstimate bool   `json:"nutrition_is_estimate"`
+		NutritionNote       string `json:"nutrition_note"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if !response.NutritionIsEstimate || response.NutritionNote == "" {
+		t.Errorf("response does not label nutrition as an estimate")
+	}
+	if !response.Peony.Open || len(response.Peony.Dishes) != 2 || response.Peony.Dishes[1].Nutrition == nil {
+		t.Errorf("peony = %+v, want two dishes with nutrition", response.Peony)
+	}
+	want := menu.Nutrition{Calories: 550, Protein: 21, Carbs: 76, Fat: 16}
+	if got := response.Peony.TrayNutrition; got == nil || *got != want {
+		t.Errorf("peony tray = %+v, want %+v", got, want)
+	}
+	if response.Azilea.Open || len(response.Azilea.Dishes) != 0 || response.Azilea.TrayNutrition != nil {
+		t.Errorf("azilea = %+v, want closed", response.Azilea)
+	}
+}
+
+func TestIndexShowsNutritionEstimate(t *testing.T) {
+	server := newNutritionTestServer()
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	body := recorder.Body.String()
+	for _, want := range []string{"≈ 250 ккал", "Весь поднос: ≈ 550 ккал", "(оценка)"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("index page does not contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	kbudailymenu "github.com/artyom-kalman/kbu-daily-menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// After fires at once: a fixed clock never moves, so there is nothing to wait for.
+func (c fixedClock) After(time.Duration) <-chan time.Time {
+	fired := make(chan time.Time, 1)
+	fired <- time.Time(c)
+	return fired
+}
+
+type recordingRenderer struct {
+	dates []time.Time
+}
+
+func (r *recordingRenderer) Render(date time.Time, peony, azilea *menu.Menu) ([]byte, error) {
+	r.dates = append(r.dates, date)
+	return []byte("\x89PNG"), nil
+}
+
+// storedMenus answers only for dates that have a stored menu, like MenuService.GetStoredMenuForDate.
+type storedMenus map[string]*menu.Menu
+
+func (s storedMenus) GetStoredMenuForDate(cafeteria menu.Cafeteria, date time.Time) (*menu.Menu, error) {
+	return s[date.Format("2006-01-02")], nil
+}
+
+func TestMenuCardRoute(t *testing.T) {
+	now := time.Date(2025, time.October, 22, 11, 0, 0, 0, time.FixedZone("KST", 9*60*60))
+	stored := menu.NewMenuFromDishes([]string{"김치찌개", "돈까스"}, nil)
+	menus := storedMenus{
+		"2025-10-20": stored,
+		"2025-10-22": stored,
+		"2025-10-24": stored,
+		"2025-10-27": stored,
+	}
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantDate   string
+	}{
+		{path: "/menu/today.png", wantStatus: http.StatusOK, wantDate: "2025-10-22"},
+		{path: "/menu/2025-10-24.png", wantStatus: http.StatusOK, wantDate: "2025-10-24"},
+		{path: "/menu/2025-10-20.png", wantStatus: http.StatusOK, wantDate: "2025-10-20"},
+		{path: "/menu/2025-10-23.png", wantStatus: http.StatusNotFound},
+		{path: "/menu/2025-10-27.png", wantStatus: http.StatusNotFound},
+		{path: "/menu/2025-10-19.png", wantStatus: http.StatusNotFound},
+		{path: "/menu/2025-10-24.jpg", wantStatus: http.StatusNotFound},
+		{path: "/menu/yesterday.png", wantStatus: http.StatusNotFound},
+		{path: "/menu/2024-01-01.png", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			renderer := &recordingRenderer{}
+			server := NewServer(nil, &fakeMenuService{})
+			server.SetAssets(kbudailymenu.Assets(""))
+			server.EnableMenuCards(menus, renderer, fixedClock(now))
+			server.SetupRouter()
+
+			recorder := httptest.NewRecorder()
+			server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := recorder.Header().Get("Content-Type"); got != "image/png" {
+				t.Errorf("Content-Type = %q, want image/png", got)
+			}
+			if len(renderer.dates) != 1 || renderer.dates[0].Format("2006-01-02") != tt.wantDate {
+				t.Errorf("rendered dates = %v, want %s", renderer.dates, tt.wantDate)
+			}
+		})
+	}
+}
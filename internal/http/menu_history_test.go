@@ -0,0 +1,151 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	kbudailymenu "github.com/artyom-kalman/kbu-daily-menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database/databasetest"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+type fakeMenuHistory map[menu.Cafeteria][]menu.MenuRevision
+
+func (h fakeMenuHistory) GetRevisionsForDate(cafeteria menu.Cafeteria, date time.Time) ([]menu.MenuRevision, error) {
+	return h[cafeteria], nil
+}
+
+func newMenuHistoryTestServer() *Server {
+	kst := time.FixedZone("KST", 9*60*60)
+	now := time.Date(2025, time.October, 22, 11, 0, 0, 0, kst)
+	history := fakeMenuHistory{
+		menu.PEONY: {
+			{RevisedAt: time.Date(2025, time.October, 22, 6, 0, 0, 0, kst), Dishes: []string{"쌀밥", "된장국"}, Added: []string{"쌀밥", "된장국"}},
+			{RevisedAt: time.Date(2025, time.October, 22, 1, 42, 0, 0, time.UTC), Dishes: []string{"쌀밥", "김치찌개"}, Added: []string{"김치찌개"}, Removed: []string{"된장국"}},
+		},
+		// Azilea published late, which replaced the placeholder but did not correct a menu.
+		menu.AZILEA: {
+			{RevisedAt: time.Date(2025, time.October, 22, 6, 0, 0, 0, kst), Dishes: []string{"오늘은 휴무입니다"}, Added: []string{"오늘은 휴무입니다"}},
+			{RevisedAt: time.Date(2025, time.October, 22, 9, 30, 0, 0, kst), Dishes: []string{"비빔밥", "된장국"}, Added: []string{"비빔밥", "된장국"}, Removed: []string{"오늘은 휴무입니다"}},
+		},
+	}
+
+	server := NewServer(nil, &fakeMenuService{
+		peony:  menu.NewMenuFromDishes([]string{"쌀밥", "김치찌개"}, nil),
+		azilea: menu.NewMenuFromDishes([]string{"비빔밥", "된장국"}, nil),
+	})
+	server.SetAssets(kbudailymenu.Assets(""))
+	server.EnableMenuHistory(history, fixedClock(now))
+	server.SetupRouter()
+	return server
+}
+
+func TestMenuRevisionsAPI(t *testing.T) {
+	server := newMenuHistoryTestServer()
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/menu/revisions?cafeteria=peony", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", recorder.Code)
+	}
+
+	var response struct {
+		Date      string              `json:"date"`
+		Revisions []menu.MenuRevision `json:"revisions"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.Date != "2025-10-22" || len(response.Revisions) != 2 || response.Revisions[1].Removed[0] != "된장국" {
+		t.Errorf("response = %+v", response)
+	}
+
+	for _, query := range []string{"", "?cafeteria=cafe", "?cafeteria=peony&date=22.10.2025"} {
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/menu/revisions"+query, nil))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("GET %q status = %d, want 400", query, recorder.Code)
+		}
+	}
+}
+
+func TestMenuRevisionsAPIForDate(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	now := time.Date(2025, time.October, 22, 11, 0, 0, 0, kst)
+	persistence := menu.NewMenuPersistenceService(menu.NewMenuRepository(databasetest.New(t)), fixedClock(now))
+
+	yesterday := now.AddDate(0, 0, -1)
+	for _, dishes := range [][]string{{"쌀밥", "된장국"}, {"쌀밥", "김치찌개"}} {
+		if err := persistence.SaveMenuForDate(menu.PEONY, menu.NewMenuFromDishes(dishes, nil), yesterday); err != nil {
+			t.Fatalf("SaveMenuForDate(yesterday) error: %v", err)
+		}
+	}
+	if err := persistence.SaveMenuForDate(menu.PEONY, menu.NewMenuFromDishes([]string{"비빔밥", "미역국"}, nil), now); err != nil {
+		t.Fatalf("SaveMenuForDate(today) error: %v", err)
+	}
+
+	server := NewServer(nil, &fakeMenuService{})
+	server.SetAssets(kbudailymenu.Assets(""))
+	server.EnableMenuHistory(menu.NewMenuService(persistence, nil), fixedClock(now))
+	server.SetupRouter()
+
+	tests := []struct {
+		date       string
+		wantDishes [][]string
+	}{
+		{date: "2025-10-21", wantDishes: [][]string{{"쌀밥", "된장국"}, {"쌀밥", "김치찌개"}}},
+		{date: "2025-10-22", wantDishes: [][]string{{"비빔밥", "미역국"}}},
+		{date: "2025-10-20", wantDishes: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/menu/revisions?cafeteria=peony&date="+tt.date, nil))
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", recorder.Code)
+			}
+
+			var response struct {
+				Date      string              `json:"date"`
+				Revisions []menu.MenuRevision `json:"revisions"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if response.Date != tt.date {
+				t.Errorf("date = %q, want %q", response.Date, tt.date)
+			}
+
+			var got [][]string
+			for _, revision := range response.Revisions {
+				got = append(got, revision.Dishes)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantDishes) {
+				t.Errorf("revisions = %v, want %v", got, tt.wantDishes)
+			}
+		})
+	}
+}
+
+func TestIndexShowsMenuCorrections(t *testing.T) {
+	server := newMenuHistoryTestServer()
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// html/template escapes "+" in text, so compare what a browser would show.
+	body := html.UnescapeString(recorder.Body.String())
+	if !strings.Contains(body, "Обновлено в 10:42: +김치찌개, −된장국") {
+		t.Errorf("index page does not show the Peony correction in KST")
+	}
+	if strings.Count(body, "✏️") != 1 {
+		t.Errorf("index page shows %d change notices, want only Peony's", strings.Count(body, "✏️"))
+	}
+}
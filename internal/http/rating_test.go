@@ -0,0 +1,141 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	kbudailymenu "github.com/artyom-kalman/kbu-daily-menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database/databasetest"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/rating"
+)
+
+func newRatingTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	db := databasetest.New(t)
+	now := time.Date(2025, time.October, 22, 11, 0, 0, 0, time.FixedZone("KST", 9*60*60))
+	menus := &fakeMenuService{
+		peony:  menu.NewMenuFromDishes([]string{"김치찌개", "돈까스"}, nil),
+		azilea: menu.NewMenuFromDishes([]string{"비빔밥", "된장국"}, nil),
+	}
+
+	server := NewServer(nil, menus)
+	server.SetAssets(kbudailymenu.Assets(""))
+	server.EnableRatings(rating.NewService(rating.NewRepository(db), menus, fixedClock(now)), fixedClock(now))
+	server.SetupRouter()
+	return server
+}
+
+func postRating(server *Server, cookie *http.Cookie, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/ratings", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestRateDishRoute(t *testing.T) {
+	server := newRatingTestServer(t)
+
+	first := postRating(server, nil, `{"cafeteria":"peony","dish":"돈까스","score":5}`)
+	if first.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", first.Code, first.Body)
+	}
+
+	var session *http.Cookie
+	for _, cookie := range first.Result().Cookies() {
+		if cookie.Name == "kbu_session" {
+			session = cookie
+		}
+	}
+	if session == nil || session.Value == "" || !session.HttpOnly {
+		t.Fatalf("session cookie = %+v, want an HttpOnly kbu_session", session)
+	}
+
+	// The same visitor changing their mind replaces the vote instead of adding one.
+	again := postRating(server, session, `{"cafeteria":"peony","dish":"돈까스","score":1}`)
+	var summary menu.DishRating
+	if err := json.Unmarshal(again.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if summary.Count != 1 || summary.Average != 1 {
+		t.Errorf("summary = %+v, want a single vote of 1", summary)
+	}
+
+	index := httptest.NewRecorder()
+	server.router.ServeHTTP(index, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := index.Body.String(); !strings.Contains(body, "★1.0") || !strings.Contains(body, `data-dish="돈까스"`) {
+		t.Errorf("index page does not show the rating")
+	}
+	// The only rated dish got one star, which tips the advice towards Azilea.
+	if body := index.Body.String(); !strings.Contains(body, "Сегодня лучше идти в 🌺 Azilea") {
+		t.Errorf("index page does not lead with the cafeteria advice")
+	}
+}
+
+func TestIndexHidesVerdictOnTie(t *testing.T) {
+	server := newRatingTestServer(t)
+
+	// Neither menu has ratings yet and both have two suitable dishes.
+	index := httptest.NewRecorder()
+	server.router.ServeHTTP(index, httptest.NewRequest(http.MethodGet, "/", nil))
+	if index.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", index.Code, http.StatusOK)
+	}
+	if body := index.Body.String(); strings.Contains(body, "лучше идти") {
+		t.Errorf("index page advises a cafeteria although both menus score the same")
+	}
+}
+
+func TestRateDishRouteRejectsInvalidRequests(t *testing.T) {
+	server := newRatingTestServer(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "malformed json", body: `{"cafeteria":`},
+		{name: "unknown cafeteria", body: `{"cafeteria":"cafe","dish":"돈까스","score":5}`},
+		{name: "dish not on menu", body: `{"cafeteria":"azilea","dish":"돈까스","score":5}`},
+		{name: "score out of range", body: `{"cafeteria":"peony","dish":"돈까스","score":9}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := postRating(server, nil, tt.body).Code; got != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", got)
+			}
+		})
+	}
+}
+
+func TestTopDishesPage(t *testing.T) {
+	server := newRatingTestServer(t)
+
+	for _, score := range []int{5, 4} {
+		body := fmt.Sprintf(`{"cafeteria":"azilea","dish":"비빔밥","score":%d}`, score)
+		if got := postRating(server, nil, body).Code; got != http.StatusOK {
+			t.Fatalf("rate status = %d, want 200", got)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/top", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", recorder.Code)
+	}
+	if body := recorder.Body.String(); !strings.Contains(body, "비빔밥") || !strings.Contains(body, "★4.5 (2)") {
+		t.Errorf("top page does not list 비빔밥 with ★4.5 (2)")
+	}
+}
@@ -3,6 +3,8 @@ package http
 import (
 	"context"
 	"fmt"
+	"html/template"
+	"io/fs"
 	"log/slog"
 	"net/http"
 	"os"
@@ -11,6 +13,7 @@ import (
 	"time"
 
 	"github.com/artyom-kalman/kbu-daily-menu/internal/http/handlers"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
 	"github.com/gin-gonic/gin"
 )
 
@@ -27,6 +30,22 @@ type Server struct {
 		Stop() error
 	}
 	menuService handlers.MenuService
+
+	telegramWebhookPath   string
+	telegramWebhookSecret string
+	telegramUpdateHandler handlers.TelegramUpdateHandler
+
+	menuCardService  handlers.MenuCardService
+	menuCardRenderer handlers.MenuCardRenderer
+	menuCardClock    menu.Clock
+
+	ratingService handlers.RatingService
+	ratingClock   menu.Clock
+
+	menuHistory      handlers.MenuHistory
+	menuHistoryClock menu.Clock
+
+	assets fs.FS
 }
 
 func NewServer(scheduler interface {
@@ -38,6 +57,42 @@ func NewServer(scheduler interface {
 	}
 }
 
+// EnableTelegramWebhook registers a route at path that feeds verified Telegram updates to updateHandler.
+// It must be called before SetupRouter.
+func (s *Server) EnableTelegramWebhook(path, secret string, updateHandler handlers.TelegramUpdateHandler) {
+	s.telegramWebhookPath = path
+	s.telegramWebhookSecret = secret
+	s.telegramUpdateHandler = updateHandler
+}
+
+// EnableMenuCards serves PNG menu cards at /menu/{date}.png for link previews.
+// It must be called before SetupRouter.
+func (s *Server) EnableMenuCards(menuService handlers.MenuCardService, renderer handlers.MenuCardRenderer, clock menu.Clock) {
+	s.menuCardService = menuService
+	s.menuCardRenderer = renderer
+	s.menuCardClock = clock
+}
+
+// EnableRatings lets visitors rate today's dishes and adds the monthly top at /top.
+// It must be called before SetupRouter.
+func (s *Server) EnableRatings(service handlers.RatingService, clock menu.Clock) {
+	s.ratingService = service
+	s.ratingClock = clock
+}
+
+// EnableMenuHistory serves menu revisions at /api/menu/revisions and shows today's corrections on the index page.
+// It must be called before SetupRouter.
+func (s *Server) EnableMenuHistory(history handlers.MenuHistory, clock menu.Clock) {
+	s.menuHistory = history
+	s.menuHistoryClock = clock
+}
+
+// SetAssets serves templates and web files from assets instead of the working directory.
+// It must be called before SetupRouter.
+func (s *Server) SetAssets(assets fs.FS) {
+	s.assets = assets
+}
+
 func (s *Server) SetupRouter() {
 	if os.Getenv("GIN_MODE") == "" {
 		gin.SetMode(gin.ReleaseMode)
@@ -49,7 +104,10 @@ func (s *Server) SetupRouter() {
 	s.router.Use(gin.Recovery())
 	s.router.Use(CORSMiddleware())
 
-	s.router.LoadHTMLGlob("templates/*.html")
+	if s.assets == nil {
+		s.assets = os.DirFS(".")
+	}
+	s.router.SetHTMLTemplate(template.Must(template.ParseFS(s.assets, "templates/*.html")))
 
 	s.setupRoutes()
 }
@@ -57,14 +115,34 @@ func (s *Server) SetupRouter() {
 func (s *Server) setupRoutes() {
 	s.router.GET("/up", healthCheckHandler)
 
-	s.router.StaticFile("/dist/tailwind.css", "./web/dist/tailwind.css")
-	s.router.StaticFile("/dist/app.js", "./web/dist/app.js")
-	s.router.Static("/static", "./web/static")
-	s.router.Static("/img", "./web/img")
+	dist := assetFileSystem(s.assets, "web/dist")
+	s.router.StaticFileFS("/dist/tailwind.css", "tailwind.css", dist)
+	s.router.StaticFileFS("/dist/app.js", "app.js", dist)
+	s.router.StaticFS("/static", assetFileSystem(s.assets, "web/static"))
+	s.router.StaticFS("/img", assetFileSystem(s.assets, "web/img"))
 
 	webGroup := s.router.Group("")
 	{
-		webGroup.GET("/", handlers.HandleIndex(s.menuService))
+		webGroup.GET("/", handlers.HandleIndex(s.menuService, s.ratingService, s.menuHistory, s.menuHistoryClock))
+	}
+
+	s.router.GET("/api/menu", handlers.HandleMenuAPI(s.menuService, s.ratingService))
+
+	if s.menuHistory != nil {
+		s.router.GET("/api/menu/revisions", handlers.HandleMenuRevisions(s.menuHistory, s.menuHistoryClock))
+	}
+
+	if s.ratingService != nil {
+		s.router.GET("/top", handlers.HandleTopDishes(s.ratingService))
+		s.router.POST("/api/ratings", handlers.HandleRateDish(s.ratingService, s.ratingClock))
+	}
+
+	if s.menuCardRenderer != nil {
+		s.router.GET("/menu/:file", handlers.HandleMenuCard(s.menuCardService, s.menuCardRenderer, s.menuCardClock))
+	}
+
+	if s.telegramUpdateHandler != nil {
+		s.router.POST(s.telegramWebhookPath, handlers.HandleTelegramWebhook(s.telegramUpdateHandler, s.telegramWebhookSecret))
 	}
 
 	if gin.Mode() != gin.ReleaseMode {
@@ -0,0 +1,309 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	kbudailymenu "github.com/artyom-kalman/kbu-daily-menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/bot"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/http/handlers"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+const (
+	testBotToken      = "123:test"
+	testWebhookPath   = "/telegram/webhook"
+	testWebhookSecret = "s3cret-token"
+)
+
+type telegramCall struct {
+	method string
+	params map[string]string
+}
+
+// fakeTelegram imitates the Bot API endpoints the bot calls while handling an update.
+type fakeTelegram struct {
+	mu    sync.Mutex
+	calls []telegramCall
+
+	// release, when set, holds every sendMessage call until it is closed.
+	release chan struct{}
+}
+
+func (f *fakeTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params := make(map[string]string)
+	for key := range r.PostForm {
+		params[key] = r.PostForm.Get(key)
+	}
+
+	if method == "sendMessage" && f.release != nil {
+		<-f.release
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, telegramCall{method: method, params: params})
+	f.mu.Unlock()
+
+	var result any = true
+	switch method {
+	case "getMe":
+		result = map[string]any{"id": 1, "is_bot": true, "first_name": "Menu", "username": "kbu_menu_bot"}
+	case "sendMessage":
+		result = map[string]any{"message_id": 7, "date": 0, "chat": map[string]any{"id": params["chat_id"], "type": "private"}}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
+
+func (f *fakeTelegram) callsTo(method string) []telegramCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var calls []telegramCall
+	for _, call := range f.calls {
+		if call.method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+type fakeMenuService struct {
+	peony  *menu.Menu
+	azilea *menu.Menu
+}
+
+func (s *fakeMenuService) GetPeonyMenu() (*menu.Menu, error)  { return s.peony, nil }
+func (s *fakeMenuService) GetAzileaMenu() (*menu.Menu, error) { return s.azilea, nil }
+func (s *fakeMenuService) GetMenus() (*menu.Menu, *menu.Menu, error) {
+	return s.peony, s.azilea, nil
+}
+func (s *fakeMenuService) GetMenusForDate(ctx context.Context, date time.Time) (*menu.Menu, *menu.Menu, error) {
+	return s.peony, s.azilea, nil
+}
+func (s *fakeMenuService) GetMenuForDate(ctx context.Context, cafeteria menu.Cafeteria, date time.Time) (*menu.Menu, error) {
+	if cafeteria == menu.PEONY {
+		return s.peony, nil
+	}
+	return s.azilea, nil
+}
+
+func (s *fakeMenuService) GetStoredMenuForDate(cafeteria menu.Cafeteria, date time.Time) (*menu.Menu, error) {
+	return s.GetMenuForDate(context.Background(), cafeteria, date)
+}
+
+func newWebhookTestServer(t *testing.T) (*Server, *bot.Bot, *fakeTelegram) {
+	t.Helper()
+
+	telegram := &fakeTelegram{}
+	telegramServer := httptest.NewServer(telegram)
+	t.Cleanup(telegramServer.Close)
+
+	api, err := tgbotapi.NewBotAPIWithClient(testBotToken, telegramServer.URL+"/bot%s/%s", telegramServer.Client())
+	if err != nil {
+		t.Fatalf("create bot api: %v", err)
+	}
+
+	menus := &fakeMenuService{
+		peony:  menu.NewMenuFromDishes([]string{"김치찌개", "돈까스"}, nil),
+		azilea: menu.NewMenuFromDishes([]string{"비빔밥", "된장국"}, nil),
+	}
+
+	botInstance := bot.NewBotWithAPI(api, nil, menus)
+	t.Cleanup(func() { botInstance.Stop() })
+
+	server := NewServer(nil, menus)
+	server.SetAssets(kbudailymenu.Assets(""))
+	server.EnableTelegramWebhook(testWebhookPath, testWebhookSecret, botInstance)
+	server.SetupRouter()
+
+	return server, botInstance, telegram
+}
+
+func postUpdate(server *Server, secret, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, testWebhookPath, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(handlers.TelegramSecretHeader, secret)
+	}
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func commandUpdate(chatID int64, command string) string {
+	return commandUpdateWithID(1, chatID, command)
+}
+
+func commandUpdateWithID(updateID int, chatID int64, command string) string {
+	return fmt.Sprintf(`{
+		"update_id": %d,
+		"message": {
+			"message_id": 10,
+			"date": 0,
+			"chat": {"id": %d, "type": "private"},
+			"text": %q,
+			"entities": [{"type": "bot_command", "offset": 0, "length": %d}]
+		}
+	}`, updateID, chatID, command, len(command))
+}
+
+func TestTelegramWebhookDispatchesVerifiedUpdates(t *testing.T) {
+	server, botInstance, telegram := newWebhookTestServer(t)
+
+	recorder := postUpdate(server, testWebhookSecret, commandUpdate(42, "/today"))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+	botInstance.Stop()
+
+	sent := telegram.callsTo("sendMessage")
+	if len(sent) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(sent))
+	}
+	if sent[0].params["chat_id"] != "42" {
+		t.Errorf("chat_id = %q, want 42", sent[0].params["chat_id"])
+	}
+	for _, dish := range []string{"김치찌개", "비빔밥"} {
+		if !strings.Contains(sent[0].params["text"], dish) {
+			t.Errorf("menu message does not mention %s:\n%s", dish, sent[0].params["text"])
+		}
+	}
+}
+
+func TestTelegramWebhookRejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		body   string
+		want   int
+	}{
+		{name: "missing secret", body: commandUpdate(42, "/today"), want: http.StatusUnauthorized},
+		{name: "wrong secret", secret: "guess", body: commandUpdate(42, "/today"), want: http.StatusUnauthorized},
+		{name: "malformed update", secret: testWebhookSecret, body: "{not json", want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, botInstance, telegram := newWebhookTestServer(t)
+
+			recorder := postUpdate(server, tt.secret, tt.body)
+			if recorder.Code != tt.want {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.want)
+			}
+			botInstance.Stop()
+			if sent := telegram.callsTo("sendMessage"); len(sent) != 0 {
+				t.Errorf("sendMessage calls = %d, want 0", len(sent))
+			}
+		})
+	}
+}
+
+func TestTelegramWebhookAnswersBeforeHandling(t *testing.T) {
+	server, botInstance, telegram := newWebhookTestServer(t)
+	telegram.release = make(chan struct{})
+
+	answered := make(chan int)
+	go func() {
+		answered <- postUpdate(server, testWebhookSecret, commandUpdate(42, "/today")).Code
+	}()
+
+	select {
+	case code := <-answered:
+		if code != http.StatusOK {
+			t.Errorf("status = %d, want %d", code, http.StatusOK)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook did not answer while the reply was still being sent")
+	}
+
+	close(telegram.release)
+	botInstance.Stop()
+	if sent := telegram.callsTo("sendMessage"); len(sent) != 1 {
+		t.Errorf("sendMessage calls = %d, want 1", len(sent))
+	}
+}
+
+func TestTelegramWebhookDropsRepeatedUpdates(t *testing.T) {
+	server, botInstance, telegram := newWebhookTestServer(t)
+
+	for _, body := range []string{
+		commandUpdateWithID(5, 42, "/today"),
+		commandUpdateWithID(5, 42, "/today"),
+		commandUpdateWithID(6, 43, "/today"),
+	} {
+		if recorder := postUpdate(server, testWebhookSecret, body); recorder.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
+		}
+	}
+	botInstance.Stop()
+
+	chats := map[string]int{}
+	for _, call := range telegram.callsTo("sendMessage") {
+		chats[call.params["chat_id"]]++
+	}
+	if chats["42"] != 1 || chats["43"] != 1 {
+		t.Errorf("replies per chat = %v, want one each for 42 and 43", chats)
+	}
+}
+
+func TestTelegramWebhookRejectsUpdatesDuringStop(t *testing.T) {
+	server, botInstance, telegram := newWebhookTestServer(t)
+	telegram.release = make(chan struct{})
+	var releaseOnce sync.Once
+	release := func() { releaseOnce.Do(func() { close(telegram.release) }) }
+	t.Cleanup(release)
+
+	// The first update holds Stop until its reply is released.
+	if recorder := postUpdate(server, testWebhookSecret, commandUpdateWithID(1, 42, "/today")); recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		botInstance.Stop()
+		close(stopped)
+	}()
+
+	// Updates posted before Stop began are still accepted; keep posting until one is rejected.
+	rejected := int64(0)
+	deadline := time.Now().Add(5 * time.Second)
+	for chatID := int64(43); rejected == 0; chatID++ {
+		if time.Now().After(deadline) {
+			t.Fatal("webhook kept accepting updates during Stop")
+		}
+		if recorder := postUpdate(server, testWebhookSecret, commandUpdateWithID(int(chatID), chatID, "/today")); recorder.Code != http.StatusOK {
+			rejected = chatID
+		}
+	}
+
+	release()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+
+	for _, call := range telegram.callsTo("sendMessage") {
+		if call.params["chat_id"] == fmt.Sprint(rejected) {
+			t.Errorf("rejected update for chat %d was handled", rejected)
+		}
+	}
+}
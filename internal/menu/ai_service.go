@@ -48,6 +48,12 @@ func (s *MenuAIService) GenerateDescriptions(ctx context.Context, menu *Menu) er
 
 			menuItem.Description = parsedItem.Description
 			menuItem.Spiciness = parsedItem.Spiciness
+			menuItem.Category = parsedItem.Category
+			menuItem.Ingredients = parsedItem.Ingredients
+			menuItem.Nutrition = parsedItem.Nutrition
+			if known, ok := KnownNutrition(menuItem.Name); ok {
+				menuItem.Nutrition = &known
+			}
 		}(i, item)
 	}
 
@@ -75,9 +81,16 @@ func (s *MenuAIService) parseSingleItem(ctx context.Context, item *MenuItem) (*M
 {
   "name": "название блюда",
   "description": "короткое, аппетитное и информативное описание блюда на русском языке",
-  "spiciness": число от 0 до 5
+  "spiciness": число от 0 до 5,
+  "category": "категория блюда",
+  "ingredients": ["основной ингредиент", "..."],
+  "nutrition": {"calories": число, "protein": число, "carbs": число, "fat": число}
 }
 
+Категория (category) — одно слово из списка: суп, горячее, рис, лапша, гарнир, салат, кимчи, закуска, десерт, напиток.
+Ингредиенты (ingredients) — до 5 главных ингредиентов, строчными буквами, в именительном падеже, например: ["свинина", "кимчи", "тофу"].
+Пищевая ценность (nutrition) — оценка для одной стандартной порции в корейской университетской столовой: калории в ккал, белки, углеводы и жиры в граммах, целые числа.
+
 Уровни остроты (spiciness):
 0 - Нет остроты (молочные продукты, десерты, фрукты, каши, йогурт)
 1 - Очень слабая острота (базовые блюда, мягкие травы, лёгкая приправа)
@@ -3,8 +3,10 @@ package menu
 import "time"
 
 // Clock abstracts time retrieval so services can share the same notion of "now".
+// Schedulers also wait on it, so a fake clock runs a schedule without real delays.
 type Clock interface {
 	Now() time.Time
+	After(d time.Duration) <-chan time.Time
 }
 
 type locationClock struct {
@@ -31,3 +33,24 @@ func (c *locationClock) Now() time.Time {
 	}
 	return time.Now().In(c.location)
 }
+
+func (c *locationClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// StartOfDay returns midnight of the calendar day t falls on in t's location.
+// Menus are keyed by this day, so callers convert to the clock's timezone first.
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns Monday midnight of the week containing t; the cafeteria pages run Monday to Friday.
+func StartOfWeek(t time.Time) time.Time {
+	day := StartOfDay(t)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// InCurrentWeek reports whether date falls in the Monday-based week containing now, in now's timezone.
+func InCurrentWeek(date, now time.Time) bool {
+	return StartOfWeek(date.In(now.Location())).Equal(StartOfWeek(now))
+}
@@ -0,0 +1,57 @@
+package menu
+
+import (
+	"slices"
+	"strings"
+)
+
+type DietTag string
+
+const (
+	DietPork       DietTag = "pork"
+	DietBeef       DietTag = "beef"
+	DietChicken    DietTag = "chicken"
+	DietSeafood    DietTag = "seafood"
+	DietVegetarian DietTag = "vegetarian"
+)
+
+// MeatTags lists the tags a vegetarian avoids.
+var MeatTags = []DietTag{DietPork, DietBeef, DietChicken, DietSeafood}
+
+// Menus carry no structured dietary data, so tags are guessed from dish names, descriptions and ingredients.
+var dietKeywords = []struct {
+	tag      DietTag
+	keywords []string
+}{
+	{tag: DietPork, keywords: []string{"돼지", "돈까스", "돈가스", "제육", "삼겹", "수육", "보쌈", "햄", "베이컨", "свинин", "бекон", "ветчин"}},
+	{tag: DietBeef, keywords: []string{"소고기", "쇠고기", "불고기", "우육", "육개장", "갈비탕", "говядин", "говяж"}},
+	{tag: DietChicken, keywords: []string{"닭", "치킨", "계육", "куриц", "курин"}},
+	{tag: DietSeafood, keywords: []string{"생선", "고등어", "연어", "참치", "새우", "오징어", "해물", "어묵", "명태", "рыб", "лосос", "тунец", "кревет", "кальмар", "морепродукт"}},
+}
+
+var vegetarianKeywords = []string{"채소", "야채", "나물", "샐러드", "두부", "овощ", "салат", "тофу", "вегетариан"}
+
+// DietTags reports which meats a dish contains, or DietVegetarian when it looks meat-free and vegetable-based.
+// Dishes that match nothing get no tags.
+func (i *MenuItem) DietTags() []DietTag {
+	text := strings.ToLower(i.Name + " " + i.Description + " " + strings.Join(i.Ingredients, " "))
+
+	var tags []DietTag
+	for _, diet := range dietKeywords {
+		if containsAny(text, diet.keywords) {
+			tags = append(tags, diet.tag)
+		}
+	}
+
+	if len(tags) == 0 && containsAny(text, vegetarianKeywords) {
+		tags = append(tags, DietVegetarian)
+	}
+
+	return tags
+}
+
+func containsAny(text string, keywords []string) bool {
+	return slices.ContainsFunc(keywords, func(keyword string) bool {
+		return strings.Contains(text, keyword)
+	})
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/http/fetcher"
 )
 
 type MenuSource interface {
-	FetchMenu(ctx context.Context) (*Menu, error)
+	FetchMenu(ctx context.Context, date time.Time) (*Menu, error)
 }
 
 type MenuValidator interface {
@@ -23,6 +27,16 @@ type MenuFetcherService struct {
 	validator MenuValidator
 	enricher  MenuEnricher
 	clock     Clock
+
+	mu sync.Mutex
+	// unpublished remembers the last page that yielded no menu, so polling does not validate it again.
+	unpublished *unpublishedPage
+}
+
+type unpublishedPage struct {
+	date string
+	hash string
+	menu *Menu
 }
 
 func NewMenuFetcherService(url string, aiService AIService, clock Clock) *MenuFetcherService {
@@ -33,8 +47,16 @@ func NewMenuFetcherService(url string, aiService AIService, clock Clock) *MenuFe
 	parser := NewMenuParser(url, clock)
 	source := &parserMenuSource{parser: parser}
 
-	aiProcessor := &aiMenuProcessor{service: NewMenuAIService(aiService)}
+	return NewAIMenuFetcher(source, aiService, clock)
+}
 
+// NewAIMenuFetcher validates and enriches menus from source with aiService; a nil aiService skips both.
+func NewAIMenuFetcher(source MenuSource, aiService AIService, clock Clock) *MenuFetcherService {
+	if aiService == nil {
+		return NewMenuFetcherPipeline(source, nil, nil, clock)
+	}
+
+	aiProcessor := &aiMenuProcessor{service: NewMenuAIService(aiService)}
 	return NewMenuFetcherPipeline(source, aiProcessor, aiProcessor, clock)
 }
 
@@ -55,48 +77,113 @@ func NewMenuFetcherPipeline(source MenuSource, validator MenuValidator, enricher
 	}
 }
 
+// EnableSnapshots stores the raw pages behind menus fetched from the cafeteria website.
+// Fetchers built from another MenuSource have no pages to store.
+func (s *MenuFetcherService) EnableSnapshots(store fetcher.SnapshotStore) {
+	if source, ok := s.source.(*parserMenuSource); ok {
+		source.parser.EnableSnapshots(store)
+	}
+}
+
 func (s *MenuFetcherService) FetchMenu() (*Menu, error) {
 	return s.FetchMenuWithContext(context.Background())
 }
 
 func (s *MenuFetcherService) FetchMenuWithContext(ctx context.Context) (*Menu, error) {
+	return s.FetchMenuForDate(ctx, s.clock.Now())
+}
+
+func (s *MenuFetcherService) FetchMenuForDate(ctx context.Context, date time.Time) (*Menu, error) {
+	return s.RefetchMenuForDate(ctx, date, nil)
+}
+
+// RefetchMenuForDate returns previous itself when the page still lists its dishes, skipping validation
+// and enrichment. Pages without a menu are remembered the same way, so polling a late menu stays cheap;
+// a page that failed to process is not, so the next attempt processes it again.
+func (s *MenuFetcherService) RefetchMenuForDate(ctx context.Context, date time.Time, previous *Menu) (*Menu, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	menu, err := s.source.FetchMenu(ctx)
+	menu, err := s.source.FetchMenu(ctx, date)
 	if err != nil {
 		slog.Error("Failed to fetch menu from source", "error", err)
 		return nil, fmt.Errorf("failed to fetch menu: %w", err)
 	}
 
+	hash := menu.DishHash()
+	if previous != nil && len(previous.Items) > 1 && previous.DishHash() == hash {
+		slog.Debug("Menu unchanged, skipping AI processing", "item_count", len(previous.Items))
+		return previous, nil
+	}
+	if cached := s.unpublishedMenu(date, hash); cached != nil {
+		slog.Debug("Menu still not published, skipping AI processing")
+		return cached, nil
+	}
+
+	processed, unpublished, err := s.process(ctx, menu, date)
+	if err != nil {
+		return nil, err
+	}
+
+	if unpublished {
+		s.rememberUnpublished(date, hash, processed)
+	}
+	return processed, nil
+}
+
+// process validates and enriches menu. It reports the page as unpublished when it lists no dishes or the
+// validator rejects it; only such pages are worth remembering.
+func (s *MenuFetcherService) process(ctx context.Context, menu *Menu, date time.Time) (*Menu, bool, error) {
+	unpublished := len(menu.Items) == 0
+
 	if s.validator != nil {
 		validation, err := s.validator.Validate(ctx, menu)
 		if err != nil {
 			slog.Error("Failed to validate menu", "error", err)
-			return s.handleValidationFailure(ctx, menu)
+			processed, err := s.handleValidationFailure(ctx, menu, date)
+			return processed, unpublished, err
 		}
 
 		if !validation.IsValid {
 			slog.Info("Menu validation failed", "reason", validation.Reason)
-			return s.createEmptyMenu(validation.Message), nil
+			return s.createEmptyMenu(validation.Message, date), true, nil
 		}
 	}
 
 	if s.enricher != nil {
 		if err := s.enricher.Enrich(ctx, menu); err != nil {
 			slog.Error("Failed to enrich menu", "error", err)
-			return nil, fmt.Errorf("failed to enrich menu: %w", err)
+			return nil, false, fmt.Errorf("failed to enrich menu: %w", err)
 		}
 	}
 
 	slog.Debug("Successfully processed menu", "item_count", len(menu.Items))
-	return menu, nil
+	return menu, unpublished, nil
+}
+
+func (s *MenuFetcherService) unpublishedMenu(date time.Time, hash string) *Menu {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.unpublished == nil || s.unpublished.date != date.Format("2006-01-02") || s.unpublished.hash != hash {
+		return nil
+	}
+	return s.unpublished.menu
 }
 
-func (s *MenuFetcherService) handleValidationFailure(ctx context.Context, menu *Menu) (*Menu, error) {
+func (s *MenuFetcherService) rememberUnpublished(date time.Time, hash string, menu *Menu) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unpublished = &unpublishedPage{date: date.Format("2006-01-02"), hash: hash, menu: menu}
+}
+
+// handleValidationFailure enriches a menu the validator could not check. A page without dishes needs no
+// check to be reported as unpublished.
+func (s *MenuFetcherService) handleValidationFailure(ctx context.Context, menu *Menu, date time.Time) (*Menu, error) {
 	if len(menu.Items) == 0 {
-		return s.createEmptyMenu("Не удалось проверить меню"), nil
+		return s.createEmptyMenu("", date), nil
 	}
 
 	if s.enricher == nil {
@@ -109,18 +196,17 @@ func (s *MenuFetcherService) handleValidationFailure(ctx context.Context, menu *
 
 	if err := s.enricher.Enrich(ctx, menu); err != nil {
 		slog.Error("Failed to enrich menu during fallback", "error", err)
-		return s.createEmptyMenu("Ошибка при обработке меню"), nil
+		return nil, fmt.Errorf("failed to enrich unvalidated menu: %w", err)
 	}
 
 	return menu, nil
 }
 
-func (s *MenuFetcherService) createEmptyMenu(message string) *Menu {
+func (s *MenuFetcherService) createEmptyMenu(message string, date time.Time) *Menu {
 	if message == "" {
 		message = "Сегодня меню недоступно"
 	}
 
-	now := s.clock.Now()
 	return &Menu{
 		Items: []*MenuItem{
 			{
@@ -129,7 +215,7 @@ func (s *MenuFetcherService) createEmptyMenu(message string) *Menu {
 				Spiciness:   0,
 			},
 		},
-		Time: &now,
+		Time: &date,
 	}
 }
 
@@ -137,8 +223,22 @@ type parserMenuSource struct {
 	parser *MenuParser
 }
 
-func (p *parserMenuSource) FetchMenu(ctx context.Context) (*Menu, error) {
-	return p.parser.ParseMenu(ctx)
+func (p *parserMenuSource) FetchMenu(ctx context.Context, date time.Time) (*Menu, error) {
+	return p.parser.ParseMenuForDate(ctx, date)
+}
+
+// NewHTMLMenuSource serves menus from a weekly page fetched earlier, to replay the pipeline offline.
+func NewHTMLMenuSource(body string, clock Clock) MenuSource {
+	return &htmlMenuSource{parser: NewMenuParser("", clock), body: body}
+}
+
+type htmlMenuSource struct {
+	parser *MenuParser
+	body   string
+}
+
+func (s *htmlMenuSource) FetchMenu(ctx context.Context, date time.Time) (*Menu, error) {
+	return s.parser.ParseMenuFromHTML(s.body, date)
 }
 
 type aiMenuProcessor struct {
@@ -0,0 +1,98 @@
+package menu
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyAI fails the first few validations and enrichments, as an AI service does during an outage.
+type flakyAI struct {
+	mu          sync.Mutex
+	failures    int
+	validations int
+	enrichments int
+}
+
+func (a *flakyAI) Validate(ctx context.Context, menu *Menu) (*MenuValidationResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.validations++
+	if a.validations <= a.failures {
+		return nil, errors.New("ai unavailable")
+	}
+	return &MenuValidationResponse{IsValid: true}, nil
+}
+
+func (a *flakyAI) Enrich(ctx context.Context, menu *Menu) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.enrichments++
+	if a.enrichments <= a.failures {
+		return errors.New("ai unavailable")
+	}
+	for _, item := range menu.Items {
+		item.Description = "Описание"
+	}
+	return nil
+}
+
+func TestFetcherRetriesPageAfterAIFailure(t *testing.T) {
+	date := time.Date(2025, time.October, 22, 6, 0, 0, 0, time.FixedZone("KST", 9*60*60))
+	source := &pageSource{pages: [][]string{{"김치찌개", "쌀밥"}}}
+	ai := &flakyAI{failures: 1}
+	fetcher := NewMenuFetcherPipeline(source, ai, ai, fixedClock(date))
+
+	if menu, err := fetcher.FetchMenuForDate(context.Background(), date); err == nil {
+		t.Fatalf("FetchMenuForDate() = %+v, want an error while the AI is down", menu)
+	}
+
+	// The page is unchanged, but the failed attempt left nothing behind, so it is processed again.
+	menu, err := fetcher.FetchMenuForDate(context.Background(), date)
+	if err != nil {
+		t.Fatalf("FetchMenuForDate() error = %v", err)
+	}
+	if len(menu.Items) != 2 || menu.Items[0].Description != "Описание" {
+		t.Errorf("menu items = %+v, want the two enriched dishes", menu.Items)
+	}
+	if ai.validations != 2 {
+		t.Errorf("validated %d times, want 2", ai.validations)
+	}
+}
+
+func TestFetcherRemembersUnpublishedPage(t *testing.T) {
+	date := time.Date(2025, time.October, 22, 6, 0, 0, 0, time.FixedZone("KST", 9*60*60))
+
+	tests := []struct {
+		name     string
+		failures int
+	}{
+		{name: "validated", failures: 0},
+		{name: "ai unavailable", failures: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &pageSource{pages: [][]string{{}}}
+			ai := &flakyAI{failures: tt.failures}
+			fetcher := NewMenuFetcherPipeline(source, ai, ai, fixedClock(date))
+
+			for i := range 2 {
+				menu, err := fetcher.FetchMenuForDate(context.Background(), date)
+				if err != nil {
+					t.Fatalf("fetch %d: error = %v", i+1, err)
+				}
+				if len(menu.Items) > 1 {
+					t.Fatalf("fetch %d: menu items = %+v, want no menu", i+1, menu.Items)
+				}
+			}
+			if ai.validations != 1 {
+				t.Errorf("validated %d times, want the empty page checked once", ai.validations)
+			}
+		})
+	}
+}
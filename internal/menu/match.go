@@ -0,0 +1,139 @@
+package menu
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	hangulBase      = 0xAC00
+	hangulLast      = 0xD7A3
+	hangulMedials   = 21
+	hangulFinals    = 28
+	minFuzzyPattern = 3
+)
+
+var (
+	hangulInitials = []string{"g", "kk", "n", "d", "tt", "r", "m", "b", "pp", "s", "ss", "", "j", "jj", "ch", "k", "t", "p", "h"}
+	hangulVowels   = []string{"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae", "oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui", "i"}
+	hangulTails    = []string{"", "k", "k", "k", "n", "n", "n", "t", "l", "k", "m", "l", "l", "l", "p", "l", "m", "p", "p", "t", "t", "ng", "t", "t", "k", "t", "p", "t"}
+
+	cyrillicLatin = map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+		'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+		'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+		'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+		'я': "ya",
+	}
+
+	// Consonant groups that sound alike across Korean romanization and common transliterations.
+	skeletonReplacer = strings.NewReplacer(
+		"shch", "s", "kh", "k", "ch", "j", "zh", "j", "ts", "j", "sh", "s",
+		"g", "k", "c", "k", "q", "k", "d", "t", "b", "p", "r", "l", "z", "j", "f", "p", "v", "p",
+	)
+)
+
+// Romanize converts Hangul (Revised Romanization) and Cyrillic text to lowercase Latin letters.
+func Romanize(text string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r >= hangulBase && r <= hangulLast:
+			index := int(r - hangulBase)
+			builder.WriteString(hangulInitials[index/(hangulMedials*hangulFinals)])
+			builder.WriteString(hangulVowels[(index%(hangulMedials*hangulFinals))/hangulFinals])
+			builder.WriteString(hangulTails[index%hangulFinals])
+		case cyrillicLatin[r] != "" || r == 'ъ' || r == 'ь':
+			builder.WriteString(cyrillicLatin[r])
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// MatchDish reports whether a user query refers to the dish name, tolerating
+// Korean, Latin and Cyrillic spellings of the same dish.
+func MatchDish(query, name string) bool {
+	query = strings.TrimSpace(strings.ToLower(query))
+	name = strings.ToLower(name)
+	if query == "" {
+		return false
+	}
+
+	if strings.Contains(name, query) {
+		return true
+	}
+
+	romanQuery := compact(Romanize(query))
+	romanName := compact(Romanize(name))
+	if romanQuery == "" {
+		return false
+	}
+	if strings.Contains(romanName, romanQuery) {
+		return true
+	}
+
+	pattern := skeleton(romanQuery)
+	if len(pattern) < minFuzzyPattern {
+		return false
+	}
+
+	return substringDistance(pattern, skeleton(romanName)) <= len(pattern)/4
+}
+
+func compact(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, text)
+}
+
+// skeleton keeps the consonant outline of a romanized word, which is what
+// differs least between "donkkaseu", "donkatsu" and "тонкацу".
+func skeleton(text string) string {
+	text = skeletonReplacer.Replace(text)
+
+	var builder strings.Builder
+	var last rune
+	for _, r := range text {
+		if strings.ContainsRune("aeiouyw", r) {
+			continue
+		}
+		if r == last {
+			continue
+		}
+		builder.WriteRune(r)
+		last = r
+	}
+	return builder.String()
+}
+
+// substringDistance returns the smallest edit distance between pattern and any substring of text.
+func substringDistance(pattern, text string) int {
+	p := []rune(pattern)
+	t := []rune(text)
+
+	previous := make([]int, len(t)+1)
+	current := make([]int, len(t)+1)
+
+	for i := 1; i <= len(p); i++ {
+		current[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if p[i-1] == t[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j-1]+cost, previous[j]+1, current[j-1]+1)
+		}
+		previous, current = current, previous
+	}
+
+	best := len(p)
+	for _, distance := range previous {
+		best = min(best, distance)
+	}
+	return best
+}
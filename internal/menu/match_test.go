@@ -0,0 +1,69 @@
+package menu
+
+import "testing"
+
+func TestRomanize(t *testing.T) {
+	tests := map[string]string{
+		"김치찌개":      "gimchijjigae",
+		"돈까스":       "donkkaseu",
+		"된장국":       "doenjangguk",
+		"닭갈비":       "dakgalbi",
+		"Борщ":      "borshch",
+		"Щи и ёжик": "shchi i yozhik",
+		"Fish 2":    "fish 2",
+		"":          "",
+	}
+
+	for text, want := range tests {
+		if got := Romanize(text); got != want {
+			t.Errorf("Romanize(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestMatchDish(t *testing.T) {
+	tests := []struct {
+		query string
+		name  string
+		want  bool
+	}{
+		{query: "돈까스", name: "치즈돈까스", want: true},
+		{query: "donkkaseu", name: "돈까스", want: true},
+		{query: "donkatsu", name: "돈까스", want: true},
+		{query: "тонкацу", name: "돈까스", want: true},
+		{query: "Kimchi", name: "김치찌개", want: true},
+		{query: "кимчи", name: "김치볶음밥", want: true},
+		{query: "bibimbap", name: "비빔밥", want: true},
+		{query: "  ", name: "비빔밥", want: false},
+		{query: "борщ", name: "된장국", want: false},
+		{query: "돈까스", name: "된장국", want: false},
+		{query: "ab", name: "김밥", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchDish(tt.query, tt.name); got != tt.want {
+			t.Errorf("MatchDish(%q, %q) = %v, want %v", tt.query, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSubstringDistance(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		want    int
+	}{
+		{pattern: "tnkts", text: "tnkts", want: 0},
+		{pattern: "tnk", text: "jstnkts", want: 0},
+		{pattern: "tnkj", text: "tnks", want: 1},
+		{pattern: "pl", text: "", want: 2},
+		{pattern: "", text: "abc", want: 0},
+		{pattern: "xyz", text: "abc", want: 3},
+	}
+
+	for _, tt := range tests {
+		if got := substringDistance(tt.pattern, tt.text); got != tt.want {
+			t.Errorf("substringDistance(%q, %q) = %d, want %d", tt.pattern, tt.text, got, tt.want)
+		}
+	}
+}
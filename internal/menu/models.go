@@ -1,6 +1,8 @@
 package menu
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"time"
 )
@@ -22,9 +24,20 @@ type Menu struct {
 }
 
 type MenuItem struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Spiciness   int    `json:"spiciness"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Spiciness   int         `json:"spiciness"`
+	Category    string      `json:"category,omitempty"`
+	Ingredients []string    `json:"ingredients,omitempty"`
+	Nutrition   *Nutrition  `json:"nutrition,omitempty"`
+	Rating      *DishRating `json:"rating,omitempty"`
+}
+
+// DishRating aggregates the scores users gave to a dish.
+// It is attached when menus are shown; ratings live in their own table, not in the stored menu.
+type DishRating struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
 }
 
 func NewMenu(items []*MenuItem, time *time.Time) *Menu {
@@ -54,9 +67,8 @@ func (m *Menu) Date() *time.Time {
 		return nil
 	}
 	kst, _ := time.LoadLocation("Asia/Seoul")
-	koreanTime := m.Time.In(kst)
-	truncatedDate := koreanTime.Truncate(24 * time.Hour)
-	return &truncatedDate
+	date := StartOfDay(m.Time.In(kst))
+	return &date
 }
 
 func (m *Menu) String() string {
@@ -71,6 +83,36 @@ func (m *Menu) String() string {
 	return str
 }
 
+// HasSameDishes reports whether both menus list the same dishes in the same order.
+func (m *Menu) HasSameDishes(other *Menu) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+
+	if len(m.Items) != len(other.Items) {
+		return false
+	}
+
+	for i, item := range m.Items {
+		if item.Name != other.Items[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// DishHash fingerprints the dish list, so a page that still lists the same dishes can skip AI processing.
+func (m *Menu) DishHash() string {
+	hash := sha256.New()
+	if m != nil {
+		for _, item := range m.Items {
+			hash.Write([]byte(item.Name))
+			hash.Write([]byte{0})
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
 func (i *MenuItem) AddDescription(description string) {
 	i.Description = description
 }
@@ -0,0 +1,72 @@
+package menu
+
+import "strings"
+
+// Nutrition is an estimate for one standard cafeteria portion, never a measured value.
+type Nutrition struct {
+	Calories int `json:"calories"`
+	Protein  int `json:"protein"`
+	Carbs    int `json:"carbs"`
+	Fat      int `json:"fat"`
+}
+
+func (n Nutrition) Add(other Nutrition) Nutrition {
+	return Nutrition{
+		Calories: n.Calories + other.Calories,
+		Protein:  n.Protein + other.Protein,
+		Carbs:    n.Carbs + other.Carbs,
+		Fat:      n.Fat + other.Fat,
+	}
+}
+
+// knownNutrition covers staples that appear almost daily, so their estimates stay stable
+// instead of varying with every AI answer. Only a dish named exactly like a key gets its value:
+// 돼지김치찌개 is not 김치찌개, and its own AI estimate is better than the plain stew's.
+var knownNutrition = map[string]Nutrition{
+	"잡곡밥":  {Calories: 310, Protein: 7, Carbs: 66, Fat: 2},
+	"쌀밥":   {Calories: 300, Protein: 5, Carbs: 66, Fat: 1},
+	"백미밥":  {Calories: 300, Protein: 5, Carbs: 66, Fat: 1},
+	"배추김치": {Calories: 15, Protein: 1, Carbs: 3, Fat: 0},
+	"깍두기":  {Calories: 20, Protein: 1, Carbs: 4, Fat: 0},
+	"단무지":  {Calories: 10, Protein: 0, Carbs: 3, Fat: 0},
+	"된장국":  {Calories: 60, Protein: 5, Carbs: 6, Fat: 2},
+	"미역국":  {Calories: 50, Protein: 4, Carbs: 3, Fat: 2},
+	"김치찌개": {Calories: 250, Protein: 16, Carbs: 10, Fat: 15},
+	"된장찌개": {Calories: 150, Protein: 11, Carbs: 10, Fat: 7},
+	"돈까스":  {Calories: 550, Protein: 25, Carbs: 40, Fat: 32},
+	"돈가스":  {Calories: 550, Protein: 25, Carbs: 40, Fat: 32},
+	"제육볶음": {Calories: 380, Protein: 22, Carbs: 15, Fat: 25},
+	"불고기":  {Calories: 330, Protein: 24, Carbs: 15, Fat: 18},
+	"비빔밥":  {Calories: 560, Protein: 18, Carbs: 90, Fat: 14},
+	"떡볶이":  {Calories: 380, Protein: 8, Carbs: 75, Fat: 5},
+}
+
+// KnownNutrition returns the table estimate for a staple dish; spaces in the name are ignored.
+func KnownNutrition(name string) (Nutrition, bool) {
+	known, ok := knownNutrition[strings.ReplaceAll(strings.TrimSpace(name), " ", "")]
+	return known, ok
+}
+
+// TrayNutrition sums the estimates of a typical tray, which in both cafeterias holds one portion of every dish.
+// Dishes without an estimate are skipped; the result is nil when no dish has one.
+func (m *Menu) TrayNutrition() *Nutrition {
+	if m == nil || len(m.Items) <= 1 {
+		return nil
+	}
+
+	var (
+		total Nutrition
+		found bool
+	)
+	for _, item := range m.Items {
+		if item.Nutrition != nil {
+			total = total.Add(*item.Nutrition)
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return &total
+}
@@ -0,0 +1,65 @@
+package menu
+
+import "testing"
+
+func TestKnownNutrition(t *testing.T) {
+	tests := []struct {
+		name     string
+		calories int
+		ok       bool
+	}{
+		{name: "쌀밥", calories: 300, ok: true},
+		{name: " 쌀 밥 ", calories: 300, ok: true},
+		{name: "돼지김치찌개", ok: false},
+		{name: "김치볶음밥", ok: false},
+		{name: "잡곡밥", calories: 310, ok: true},
+		{name: "오징어볶음", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := KnownNutrition(tt.name)
+			if ok != tt.ok || got.Calories != tt.calories {
+				t.Errorf("KnownNutrition(%q) = %d kcal, %v; want %d kcal, %v", tt.name, got.Calories, ok, tt.calories, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTrayNutrition(t *testing.T) {
+	tests := []struct {
+		name string
+		menu *Menu
+		want *Nutrition
+	}{
+		{
+			name: "sums dishes with estimates",
+			menu: NewMenu([]*MenuItem{
+				{Name: "국", Nutrition: &Nutrition{Calories: 60, Protein: 5, Carbs: 6, Fat: 2}},
+				{Name: "밥", Nutrition: &Nutrition{Calories: 300, Protein: 5, Carbs: 66, Fat: 1}},
+				{Name: "과일"},
+			}, nil),
+			want: &Nutrition{Calories: 360, Protein: 10, Carbs: 72, Fat: 3},
+		},
+		{
+			name: "no estimates",
+			menu: NewMenu([]*MenuItem{{Name: "국"}, {Name: "밥"}}, nil),
+		},
+		{
+			name: "closed",
+			menu: NewMenu([]*MenuItem{{Name: "휴무", Nutrition: &Nutrition{Calories: 1}}}, nil),
+		},
+		{
+			name: "nil menu",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.menu.TrayNutrition()
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("TrayNutrition() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,13 +7,24 @@ import (
 	"log/slog"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/artyom-kalman/kbu-daily-menu/internal/http/fetcher"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/retry"
 )
 
+// ErrDateOutsideWeek is returned for days the weekly page does not cover.
+var ErrDateOutsideWeek = errors.New("date is outside the week shown on the menu page")
+
 type MenuParser struct {
 	fetcher *fetcher.HTTPFetcher
 	clock   Clock
+
+	mu sync.Mutex
+	// parsed caches the dishes per weekday of the last page, so an unchanged page is not parsed again.
+	parsedHash string
+	parsed     map[int][]string
 }
 
 func NewMenuParser(url string, clock Clock) *MenuParser {
@@ -27,19 +38,58 @@ func NewMenuParser(url string, clock Clock) *MenuParser {
 }
 
 func (p *MenuParser) ParseMenu(ctx context.Context) (*Menu, error) {
+	return p.ParseMenuForDate(ctx, p.clock.Now())
+}
+
+// ParseMenuForDate extracts the menu of the given day from the weekly menu page.
+// The page only lists the current week, so other dates fail with ErrDateOutsideWeek.
+func (p *MenuParser) ParseMenuForDate(ctx context.Context, date time.Time) (*Menu, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	body, err := p.fetcher.FetchWithContext(ctx)
+	if !InCurrentWeek(date, p.clock.Now()) {
+		return nil, retry.Mark(retry.Client, fmt.Errorf("%w: %s", ErrDateOutsideWeek, date.Format("2006-01-02")))
+	}
+
+	page, err := p.fetcher.FetchPage(ctx)
 	if err != nil {
 		slog.Error("Failed to fetch HTML content", "error", err)
 		return nil, fmt.Errorf("failed to fetch menu: %w", err)
 	}
 
-	now := p.clock.Now()
+	weekday := int(date.Weekday())
+	if dishes, ok := p.cachedDishes(page.Hash, weekday); ok {
+		slog.Debug("Page unchanged, reusing parsed menu", "weekday", weekday)
+		return NewMenuFromDishes(dishes, &date), nil
+	}
+
+	foodItems, err := p.parseDishes(page.Body, weekday)
+	if err != nil {
+		return nil, err
+	}
+	p.cacheDishes(page.Hash, weekday, foodItems)
+
+	return NewMenuFromDishes(foodItems, &date), nil
+}
+
+// ParseMenuFromHTML extracts the menu of the given day from a weekly page that was fetched earlier,
+// such as a stored snapshot.
+func (p *MenuParser) ParseMenuFromHTML(body string, date time.Time) (*Menu, error) {
+	foodItems, err := p.parseDishes(body, int(date.Weekday()))
+	if err != nil {
+		return nil, err
+	}
+	return NewMenuFromDishes(foodItems, &date), nil
+}
+
+// EnableSnapshots stores every distinct page the parser downloads.
+func (p *MenuParser) EnableSnapshots(store fetcher.SnapshotStore) {
+	p.fetcher.EnableSnapshots(store)
+}
 
-	foodList, err := p.extractFoodList(body, int(now.Weekday()))
+func (p *MenuParser) parseDishes(body string, weekday int) ([]string, error) {
+	foodList, err := p.extractFoodList(body, weekday)
 	if err != nil {
 		return nil, err
 	}
@@ -49,10 +99,29 @@ func (p *MenuParser) ParseMenu(ctx context.Context) (*Menu, error) {
 		slog.Error("Failed to extract menu items", "error", err)
 		return nil, fmt.Errorf("failed to extract menu items: %w", err)
 	}
+	return foodItems, nil
+}
+
+func (p *MenuParser) cachedDishes(hash string, weekday int) ([]string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if hash != p.parsedHash {
+		return nil, false
+	}
+	dishes, ok := p.parsed[weekday]
+	return dishes, ok
+}
 
-	menu := NewMenuFromDishes(foodItems, &now)
+func (p *MenuParser) cacheDishes(hash string, weekday int, dishes []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	return menu, nil
+	if hash != p.parsedHash {
+		p.parsedHash = hash
+		p.parsed = make(map[int][]string)
+	}
+	p.parsed[weekday] = dishes
 }
 
 func (p *MenuParser) extractFoodList(body string, dayOfWeek int) (string, error) {
@@ -77,7 +146,7 @@ func (p *MenuParser) extractFoodList(body string, dayOfWeek int) (string, error)
 		slog.Debug("Found foodList elements, but need day",
 			"found", len(matches),
 			"target_day", targetDay)
-		return "", errors.New("error parsing body")
+		return "", retry.Mark(retry.Parse, errors.New("error parsing body"))
 	}
 
 	return matches[targetDay-1][1], nil
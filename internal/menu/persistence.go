@@ -22,8 +22,12 @@ func NewMenuPersistenceService(repo *MenuRepository, clock Clock) *MenuPersisten
 }
 
 func (p *MenuPersistenceService) LoadMenu(cafeteria Cafeteria) (*Menu, error) {
-	koreanToday := p.clock.Now().Truncate(24 * time.Hour)
-	dishes, err := p.repo.GetMenu(string(cafeteria), koreanToday)
+	return p.LoadMenuForDate(cafeteria, p.clock.Now())
+}
+
+func (p *MenuPersistenceService) LoadMenuForDate(cafeteria Cafeteria, date time.Time) (*Menu, error) {
+	menuDate := p.menuDate(date)
+	dishes, err := p.repo.GetMenu(string(cafeteria), menuDate)
 	if err != nil {
 		slog.Error("Failed to load menu from database",
 			"error", err,
@@ -37,22 +41,43 @@ func (p *MenuPersistenceService) LoadMenu(cafeteria Cafeteria) (*Menu, error) {
 
 	menuItems := make([]*MenuItem, len(dishes))
 	for i, dish := range dishes {
+		// Menus stored before nutrition was estimated still get the table values for staples.
+		if known, ok := KnownNutrition(dish.Name); ok && dish.Nutrition == nil {
+			dish.Nutrition = &known
+		}
+
 		menuItems[i] = &MenuItem{
 			Name:        dish.Name,
 			Description: dish.Description,
 			Spiciness:   dish.Spiciness,
+			Category:    dish.Category,
+			Ingredients: dish.Ingredients,
+			Nutrition:   dish.Nutrition,
 		}
 	}
 
 	return &Menu{
 		Items: menuItems,
-		Time:  &koreanToday,
+		Time:  &menuDate,
 	}, nil
 }
 
+// LoadDishesSince returns every dish stored for either cafeteria from date onwards, oldest first.
+func (p *MenuPersistenceService) LoadDishesSince(date time.Time) ([]*MenuItem, error) {
+	dishes, err := p.repo.GetDishesSince(p.menuDate(date))
+	if err != nil {
+		return nil, fmt.Errorf("load dish history: %w", err)
+	}
+	return dishes, nil
+}
+
 func (p *MenuPersistenceService) SaveMenu(cafeteria Cafeteria, menu *Menu) error {
-	koreanToday := p.clock.Now().Truncate(24 * time.Hour)
-	err := p.repo.SaveMenu(string(cafeteria), menu.Items, koreanToday)
+	return p.SaveMenuForDate(cafeteria, menu, p.clock.Now())
+}
+
+func (p *MenuPersistenceService) SaveMenuForDate(cafeteria Cafeteria, menu *Menu, date time.Time) error {
+	menuDate := p.menuDate(date)
+	err := p.repo.SaveMenu(string(cafeteria), menu.Items, menuDate, p.clock.Now())
 	if err != nil {
 		slog.Error("Failed to save menu to database",
 			"error", err,
@@ -60,7 +85,25 @@ func (p *MenuPersistenceService) SaveMenu(cafeteria Cafeteria, menu *Menu) error
 		return fmt.Errorf("database update failed for %s: %w", string(cafeteria), err)
 	}
 
-	menu.Time = &koreanToday
+	menu.Time = &menuDate
 
 	return nil
 }
+
+func (p *MenuPersistenceService) LoadRevisionsForDate(cafeteria Cafeteria, date time.Time) ([]MenuRevision, error) {
+	revisions, err := p.repo.GetRevisions(string(cafeteria), p.menuDate(date))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s menu revisions: %w", string(cafeteria), err)
+	}
+	return revisions, nil
+}
+
+// IsToday reports whether date falls on the same stored menu day as the clock's current time.
+func (p *MenuPersistenceService) IsToday(date time.Time) bool {
+	return p.menuDate(date).Equal(p.menuDate(p.clock.Now()))
+}
+
+// menuDate is the calendar day of date in the clock's timezone, which keys stored menus.
+func (p *MenuPersistenceService) menuDate(date time.Time) time.Time {
+	return StartOfDay(date.In(p.clock.Now().Location()))
+}
@@ -0,0 +1,74 @@
+package menu
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPersistenceKeysMenusByClockDay(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	repo := newTestRepository(t)
+	// 06:00 KST is still the previous day in UTC.
+	persistence := NewMenuPersistenceService(repo, fixedClock(time.Date(2025, time.October, 22, 6, 0, 0, 0, kst)))
+
+	midnight := time.Date(2025, time.October, 22, 0, 0, 0, 0, kst)
+	if err := persistence.SaveMenuForDate(PEONY, NewMenuFromDishes([]string{"김치찌개"}, nil), midnight); err != nil {
+		t.Fatalf("SaveMenuForDate() error = %v", err)
+	}
+
+	var stored string
+	if err := repo.db.Conn.QueryRow("SELECT date FROM menu WHERE cafeteria = ?", string(PEONY)).Scan(&stored); err != nil {
+		t.Fatalf("query stored date: %v", err)
+	}
+	if stored[:10] != "2025-10-22" {
+		t.Fatalf("stored date = %q, want 2025-10-22", stored)
+	}
+
+	for _, date := range []time.Time{midnight, midnight.Add(23 * time.Hour), midnight.UTC()} {
+		menu, err := persistence.LoadMenuForDate(PEONY, date)
+		if err != nil || menu == nil {
+			t.Errorf("LoadMenuForDate(%v) = %v, %v; want the stored menu", date, menu, err)
+		}
+		if !persistence.IsToday(date) {
+			t.Errorf("IsToday(%v) = false", date)
+		}
+	}
+	if menu, _ := persistence.LoadMenuForDate(PEONY, midnight.Add(-time.Minute)); menu != nil {
+		t.Errorf("LoadMenuForDate() found the menu a day early")
+	}
+}
+
+func TestInCurrentWeek(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	// A Wednesday, early in the morning.
+	now := time.Date(2025, time.October, 22, 1, 0, 0, 0, kst)
+
+	tests := []struct {
+		date time.Time
+		want bool
+	}{
+		{date: time.Date(2025, time.October, 20, 0, 0, 0, 0, kst), want: true},
+		{date: time.Date(2025, time.October, 26, 23, 59, 0, 0, kst), want: true},
+		{date: time.Date(2025, time.October, 19, 23, 59, 0, 0, kst), want: false},
+		{date: time.Date(2025, time.October, 27, 0, 0, 0, 0, kst), want: false},
+		// Sunday 16:00 UTC is already Monday in Seoul.
+		{date: time.Date(2025, time.October, 26, 16, 0, 0, 0, time.UTC), want: false},
+	}
+	for _, tt := range tests {
+		if got := InCurrentWeek(tt.date, now); got != tt.want {
+			t.Errorf("InCurrentWeek(%v) = %v, want %v", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestParserRejectsDatesOutsideWeek(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	parser := NewMenuParser("http://127.0.0.1:0/menu", fixedClock(time.Date(2025, time.October, 22, 11, 0, 0, 0, kst)))
+
+	_, err := parser.ParseMenuForDate(context.Background(), time.Date(2025, time.October, 28, 0, 0, 0, 0, kst))
+	if !errors.Is(err, ErrDateOutsideWeek) {
+		t.Fatalf("ParseMenuForDate(next week) error = %v, want ErrDateOutsideWeek", err)
+	}
+}
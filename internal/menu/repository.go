@@ -1,7 +1,10 @@
 package menu
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/artyom-kalman/kbu-daily-menu/internal/database"
@@ -48,21 +51,141 @@ func (r *MenuRepository) GetMenu(cafeteria string, targetDate time.Time) ([]*Men
 	return dishes, nil
 }
 
-func (r *MenuRepository) SaveMenu(cafeteria string, dishes []*MenuItem, targetDate time.Time) error {
+func (r *MenuRepository) GetDishesSince(from time.Time) ([]*MenuItem, error) {
+	selectQuery := "SELECT dishes FROM menu WHERE date >= $1 ORDER BY date"
+	rows, err := r.db.Conn.Query(selectQuery, from.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*MenuItem
+	for rows.Next() {
+		var dishesJson string
+		if err := rows.Scan(&dishesJson); err != nil {
+			return nil, err
+		}
+
+		var dishes []*MenuItem
+		if err := json.Unmarshal([]byte(dishesJson), &dishes); err != nil {
+			return nil, err
+		}
+		history = append(history, dishes...)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// SaveMenu replaces the stored menu and, when dishes were added or removed, appends a revision with the diff.
+// The same dishes in another order are not a change.
+func (r *MenuRepository) SaveMenu(cafeteria string, dishes []*MenuItem, targetDate, savedAt time.Time) error {
 	dishesJSON, err := json.Marshal(dishes)
 	if err != nil {
 		return err
 	}
 
+	tx, err := r.db.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	date := targetDate.Format("2006-01-02")
+
+	var (
+		previousJSON string
+		previous     []*MenuItem
+		isFirst      bool
+	)
+	err = tx.QueryRow("SELECT dishes FROM menu WHERE cafeteria = $1 AND date = $2", cafeteria, date).Scan(&previousJSON)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		isFirst = true
+	case err != nil:
+		return fmt.Errorf("load previous menu: %w", err)
+	default:
+		if err := json.Unmarshal([]byte(previousJSON), &previous); err != nil {
+			return fmt.Errorf("decode previous menu: %w", err)
+		}
+	}
+
 	insertQuery := `
 		INSERT INTO menu (date, cafeteria, dishes)
 		VALUES ($1, $2, $3)
 		ON CONFLICT(date, cafeteria) DO UPDATE SET dishes = excluded.dishes
 	`
-	_, err = r.db.Conn.Exec(insertQuery, targetDate.Format("2006-01-02"), cafeteria, string(dishesJSON))
-	if err != nil {
+	if _, err := tx.Exec(insertQuery, date, cafeteria, string(dishesJSON)); err != nil {
 		return err
 	}
 
-	return nil
+	added, removed := DiffDishes(previous, dishes)
+	if isFirst || len(added) > 0 || len(removed) > 0 {
+		if err := insertRevision(tx, cafeteria, date, dishes, added, removed, savedAt); err != nil {
+			return fmt.Errorf("record menu revision: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func insertRevision(tx *sql.Tx, cafeteria, date string, current []*MenuItem, added, removed []string, savedAt time.Time) error {
+	insertQuery := `
+		INSERT INTO menu_revisions (date, cafeteria, dishes, added, removed, revised_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := tx.Exec(insertQuery, date, cafeteria, encodeNames(dishNames(current)), encodeNames(added), encodeNames(removed), savedAt)
+	return err
+}
+
+// encodeNames stores an empty list as [] rather than null, so revisions decode the same way in any client.
+func encodeNames(names []string) string {
+	if names == nil {
+		names = []string{}
+	}
+	data, _ := json.Marshal(names)
+	return string(data)
+}
+
+// GetRevisions returns the revisions of a day's menu, oldest first.
+func (r *MenuRepository) GetRevisions(cafeteria string, targetDate time.Time) ([]MenuRevision, error) {
+	selectQuery := `
+		SELECT dishes, added, removed, revised_at FROM menu_revisions
+		WHERE cafeteria = $1 AND date = $2
+		ORDER BY id
+	`
+	rows, err := r.db.Conn.Query(selectQuery, cafeteria, targetDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []MenuRevision
+	for rows.Next() {
+		var (
+			revision               MenuRevision
+			dishes, added, removed string
+		)
+		if err := rows.Scan(&dishes, &added, &removed, &revision.RevisedAt); err != nil {
+			return nil, err
+		}
+		for _, field := range []struct {
+			data   string
+			target *[]string
+		}{{dishes, &revision.Dishes}, {added, &revision.Added}, {removed, &revision.Removed}} {
+			if err := json.Unmarshal([]byte(field.data), field.target); err != nil {
+				return nil, err
+			}
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
 }
@@ -0,0 +1,54 @@
+package menu
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// MenuRevision records one save that changed a day's dish list. The first save of a day adds every dish.
+type MenuRevision struct {
+	RevisedAt time.Time `json:"revised_at"`
+	Dishes    []string  `json:"dishes"`
+	Added     []string  `json:"added"`
+	Removed   []string  `json:"removed"`
+}
+
+// IsCorrection reports whether the revision changed a menu that was already published,
+// as opposed to the first publication or a late menu replacing the "no menu" placeholder.
+func (r MenuRevision) IsCorrection(previous *MenuRevision) bool {
+	return previous != nil && len(previous.Dishes) > 1 && len(r.Dishes) > 1
+}
+
+// Summary lists the changes as "+김치찌개, −된장국".
+func (r MenuRevision) Summary() string {
+	changes := make([]string, 0, len(r.Added)+len(r.Removed))
+	for _, dish := range r.Added {
+		changes = append(changes, "+"+dish)
+	}
+	for _, dish := range r.Removed {
+		changes = append(changes, "−"+dish)
+	}
+	return strings.Join(changes, ", ")
+}
+
+// DiffDishes compares dish lists by name; a dish listed twice must disappear twice to count as removed.
+func DiffDishes(previous, current []*MenuItem) (added, removed []string) {
+	remaining := dishNames(previous)
+	for _, name := range dishNames(current) {
+		if i := slices.Index(remaining, name); i >= 0 {
+			remaining = slices.Delete(remaining, i, i+1)
+			continue
+		}
+		added = append(added, name)
+	}
+	return added, remaining
+}
+
+func dishNames(items []*MenuItem) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
@@ -0,0 +1,78 @@
+package menu
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiffDishes(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []string
+		current  []string
+		added    []string
+		removed  []string
+	}{
+		{name: "first publication", current: []string{"쌀밥", "된장국"}, added: []string{"쌀밥", "된장국"}},
+		{name: "swapped soup", previous: []string{"쌀밥", "된장국"}, current: []string{"쌀밥", "김치찌개"}, added: []string{"김치찌개"}, removed: []string{"된장국"}},
+		{name: "reordered", previous: []string{"쌀밥", "된장국"}, current: []string{"된장국", "쌀밥"}},
+		{name: "duplicate dropped", previous: []string{"김치", "쌀밥", "김치"}, current: []string{"김치", "쌀밥"}, removed: []string{"김치"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed := DiffDishes(NewMenuFromDishes(tt.previous, nil).Items, NewMenuFromDishes(tt.current, nil).Items)
+			if !slices.Equal(added, tt.added) || !slices.Equal(removed, tt.removed) {
+				t.Errorf("DiffDishes() = +%q −%q, want +%q −%q", added, removed, tt.added, tt.removed)
+			}
+		})
+	}
+}
+
+func TestMenuRevisionsRecordChanges(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	repo := newTestRepository(t)
+	date := time.Date(2025, time.October, 22, 0, 0, 0, 0, time.UTC)
+
+	saves := []struct {
+		at     time.Time
+		dishes []string
+	}{
+		{at: time.Date(2025, time.October, 22, 6, 0, 0, 0, kst), dishes: []string{"쌀밥", "된장국"}},
+		// Re-enriching the same dishes is not a revision.
+		{at: time.Date(2025, time.October, 22, 9, 30, 0, 0, kst), dishes: []string{"쌀밥", "된장국"}},
+		// Nor is listing them in another order.
+		{at: time.Date(2025, time.October, 22, 9, 45, 0, 0, kst), dishes: []string{"된장국", "쌀밥"}},
+		{at: time.Date(2025, time.October, 22, 10, 42, 0, 0, kst), dishes: []string{"쌀밥", "김치찌개"}},
+	}
+	for _, save := range saves {
+		if err := repo.SaveMenu(string(PEONY), NewMenuFromDishes(save.dishes, nil).Items, date, save.at); err != nil {
+			t.Fatalf("SaveMenu() error = %v", err)
+		}
+	}
+
+	revisions, err := repo.GetRevisions(string(PEONY), date)
+	if err != nil {
+		t.Fatalf("GetRevisions() error = %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("got %d revisions, want 2", len(revisions))
+	}
+
+	latest := revisions[1]
+	if got := latest.Summary(); got != "+김치찌개, −된장국" {
+		t.Errorf("Summary() = %q", got)
+	}
+	if !latest.RevisedAt.Equal(saves[3].at) {
+		t.Errorf("RevisedAt = %v, want %v", latest.RevisedAt, saves[3].at)
+	}
+	if !latest.IsCorrection(&revisions[0]) || revisions[0].IsCorrection(nil) {
+		t.Error("only the second revision is a correction")
+	}
+
+	other, err := repo.GetRevisions(string(AZILEA), date)
+	if err != nil || len(other) != 0 {
+		t.Errorf("azilea revisions = %v, %v; want none", other, err)
+	}
+}
@@ -2,15 +2,30 @@ package menu
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/robfig/cron/v3"
 )
 
+// DefaultSchedule refreshes a menu early in the morning, then re-checks before and right at lunch
+// in case the cafeteria published or corrected it late.
+var DefaultSchedule = []string{"0 6 * * *", "30 9 * * *", "0 11 * * *"}
+
+type scheduledRun struct {
+	cafeteria Cafeteria
+	spec      string
+	schedule  cron.Schedule
+}
+
 type MenuScheduler struct {
 	updater   *MenuUpdater
 	clock     Clock
 	location  *time.Location
+	runs      []scheduledRun
 	isRunning bool
 	mu        sync.RWMutex
 	ctx       context.Context
@@ -30,13 +45,47 @@ func NewMenuScheduler(updater *MenuUpdater, clock Clock) *MenuScheduler {
 		location = time.FixedZone("KST", 9*60*60)
 	}
 
-	return &MenuScheduler{
+	s := &MenuScheduler{
 		updater:  updater,
 		clock:    clock,
 		location: location,
 		ctx:      ctx,
 		cancel:   cancel,
 	}
+	for _, cafeteria := range []Cafeteria{PEONY, AZILEA} {
+		// DefaultSchedule is known to parse.
+		_ = s.SetSchedule(cafeteria, DefaultSchedule)
+	}
+	return s
+}
+
+// SetSchedule replaces the cron expressions of one cafeteria. Expressions use the standard
+// five fields and are evaluated in the clock's timezone. Must be called before Start.
+func (s *MenuScheduler) SetSchedule(cafeteria Cafeteria, specs []string) error {
+	if len(specs) == 0 {
+		return fmt.Errorf("empty schedule for %s", cafeteria)
+	}
+
+	runs := make([]scheduledRun, 0, len(specs))
+	for _, spec := range specs {
+		schedule, err := cron.ParseStandard(spec)
+		if err != nil {
+			return fmt.Errorf("parse %s schedule %q: %w", cafeteria, spec, err)
+		}
+		runs = append(runs, scheduledRun{cafeteria: cafeteria, spec: spec, schedule: schedule})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.runs[:0]
+	for _, run := range s.runs {
+		if run.cafeteria != cafeteria {
+			kept = append(kept, run)
+		}
+	}
+	s.runs = append(kept, runs...)
+	return nil
 }
 
 func (s *MenuScheduler) Start() error {
@@ -47,11 +96,14 @@ func (s *MenuScheduler) Start() error {
 		return nil
 	}
 
+	runs := make([]scheduledRun, len(s.runs))
+	copy(runs, s.runs)
+
 	s.wg.Add(1)
-	go s.runScheduler()
+	go s.runScheduler(runs)
 	s.isRunning = true
 	slog.Info("Menu scheduler started",
-		"run_time", "06:00",
+		"schedule", s.describeSchedule(),
 		"timezone", s.location.String())
 
 	go s.warmup()
@@ -73,24 +125,83 @@ func (s *MenuScheduler) Stop() error {
 	return nil
 }
 
-func (s *MenuScheduler) runScheduler() {
+func (s *MenuScheduler) runScheduler(runs []scheduledRun) {
 	defer s.wg.Done()
 
+	var last time.Time
 	for {
-		nextRun := s.getNextRunTime()
-		timer := time.NewTimer(time.Until(nextRun))
+		// Computing from the previous run as well as now keeps a timer that fires early from repeating a run.
+		from := s.clock.Now().In(s.location)
+		if from.Before(last) {
+			from = last.In(s.location)
+		}
 
+		nextRun, due := nextRuns(runs, from)
+		if len(due) == 0 {
+			slog.Warn("Menu scheduler has nothing to run")
+			return
+		}
 		select {
 		case <-s.ctx.Done():
-			timer.Stop()
 			return
-		case <-timer.C:
-			slog.Info("Starting scheduled menu update")
-			if err := s.updater.UpdateAll(s.ctx); err != nil {
-				slog.Error("Scheduled update failed", "error", err)
+		case <-s.clock.After(nextRun.Sub(s.clock.Now())):
+			last = nextRun
+			s.runDue(due)
+		}
+	}
+}
+
+// runDue starts a refresh for every cafeteria due at the same moment without waiting for it, so a
+// refresh that polls for hours never holds up the next run. Stop waits for them through s.wg, and the
+// updater skips a cafeteria that is still being polled.
+func (s *MenuScheduler) runDue(due []scheduledRun) {
+	for _, run := range due {
+		s.wg.Add(1)
+		go func(run scheduledRun) {
+			defer s.wg.Done()
+			slog.Info("Starting scheduled menu update", "cafeteria", string(run.cafeteria), "schedule", run.spec)
+			if err := s.updater.UpdateCafeteria(s.ctx, run.cafeteria); err != nil {
+				slog.Error("Scheduled update failed", "cafeteria", string(run.cafeteria), "error", err)
 			}
+		}(run)
+	}
+}
+
+// nextRuns returns the earliest run after from and the cafeterias due then, one entry per cafeteria.
+func nextRuns(runs []scheduledRun, from time.Time) (time.Time, []scheduledRun) {
+	var (
+		next time.Time
+		due  []scheduledRun
+	)
+	for _, run := range runs {
+		at := run.schedule.Next(from)
+		switch {
+		case at.IsZero():
+			continue
+		case next.IsZero() || at.Before(next):
+			next, due = at, []scheduledRun{run}
+		case at.Equal(next) && !containsCafeteria(due, run.cafeteria):
+			due = append(due, run)
 		}
 	}
+	return next, due
+}
+
+func containsCafeteria(runs []scheduledRun, cafeteria Cafeteria) bool {
+	for _, run := range runs {
+		if run.cafeteria == cafeteria {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MenuScheduler) describeSchedule() string {
+	parts := make([]string, 0, len(s.runs))
+	for _, run := range s.runs {
+		parts = append(parts, fmt.Sprintf("%s@%s", run.cafeteria, run.spec))
+	}
+	return strings.Join(parts, ", ")
 }
 
 func (s *MenuScheduler) warmup() {
@@ -112,17 +223,3 @@ func (s *MenuScheduler) warmup() {
 
 	slog.Info("Service warmup initiated")
 }
-
-func (s *MenuScheduler) getNextRunTime() time.Time {
-	now := s.clock.Now().In(s.location)
-
-	// Get today at 6:00 AM KST
-	today6AM := time.Date(now.Year(), now.Month(), now.Day(), 6, 0, 0, 0, s.location)
-
-	// If today's 6:00 AM has passed, schedule for tomorrow
-	if now.After(today6AM) {
-		return today6AM.Add(24 * time.Hour)
-	}
-
-	return today6AM
-}
@@ -0,0 +1,244 @@
+package menu
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// After fires at once: a fixed clock never moves, so there is nothing to wait for.
+func (c fixedClock) After(time.Duration) <-chan time.Time {
+	fired := make(chan time.Time, 1)
+	fired <- time.Time(c)
+	return fired
+}
+
+// manualClock hands every wait to the test, which moves the clock and fires it.
+type manualClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits chan clockWait
+}
+
+type clockWait struct {
+	d    time.Duration
+	fire chan time.Time
+}
+
+func newManualClock(now time.Time) *manualClock {
+	return &manualClock{now: now, waits: make(chan clockWait, 8)}
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *manualClock) After(d time.Duration) <-chan time.Time {
+	fire := make(chan time.Time, 1)
+	c.waits <- clockWait{d: d, fire: fire}
+	return fire
+}
+
+// step takes the next wait, checks that it ends at want and fires it.
+func (c *manualClock) step(t *testing.T, want time.Time) {
+	t.Helper()
+
+	select {
+	case wait := <-c.waits:
+		c.mu.Lock()
+		c.now = c.now.Add(wait.d)
+		now := c.now
+		c.mu.Unlock()
+
+		if !now.Equal(want) {
+			t.Fatalf("wait ends at %v, want %v", now, want)
+		}
+		wait.fire <- now
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no wait started, want one ending at %v", want)
+	}
+}
+
+// timedSource reports when each fetch happened on the clock; a hanging source then blocks until the
+// fetch is cancelled.
+type timedSource struct {
+	clock   Clock
+	hang    bool
+	fetched chan time.Time
+}
+
+func newTimedSource(clock Clock, hang bool) *timedSource {
+	return &timedSource{clock: clock, hang: hang, fetched: make(chan time.Time, 8)}
+}
+
+func (s *timedSource) FetchMenu(ctx context.Context, date time.Time) (*Menu, error) {
+	s.fetched <- s.clock.Now()
+	if s.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return NewMenuFromDishes([]string{"김치찌개", "쌀밥"}, &date), nil
+}
+
+func (s *timedSource) expectFetch(t *testing.T, cafeteria Cafeteria, want time.Time) {
+	t.Helper()
+
+	select {
+	case at := <-s.fetched:
+		if !at.Equal(want) {
+			t.Errorf("%s fetched at %v, want %v", cafeteria, at, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s not fetched, want a fetch at %v", cafeteria, want)
+	}
+}
+
+// startScheduler runs the loop of a scheduler serving peony and azilea until the test ends.
+func startScheduler(t *testing.T, clock Clock, peony, azilea MenuSource, azileaSchedule []string) *MenuScheduler {
+	t.Helper()
+
+	service := NewMenuService(NewMenuPersistenceService(newTestRepository(t), clock), map[Cafeteria]*MenuFetcherService{
+		PEONY:  NewMenuFetcherPipeline(peony, nil, nil, clock),
+		AZILEA: NewMenuFetcherPipeline(azilea, nil, nil, clock),
+	})
+	scheduler := NewMenuScheduler(NewMenuUpdater(service, clock), clock)
+	if err := scheduler.SetSchedule(AZILEA, azileaSchedule); err != nil {
+		t.Fatalf("SetSchedule() error = %v", err)
+	}
+
+	scheduler.wg.Add(1)
+	go scheduler.runScheduler(scheduler.runs)
+	t.Cleanup(func() {
+		scheduler.cancel()
+		scheduler.wg.Wait()
+	})
+	return scheduler
+}
+
+func TestSchedulerFiresDueRuns(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.October, day, hour, minute, 0, 0, kst)
+	}
+
+	clock := newManualClock(at(22, 5, 0))
+	peony, azilea := newTimedSource(clock, false), newTimedSource(clock, false)
+	startScheduler(t, clock, peony, azilea, []string{"0 6 * * *", "0 10 * * *"})
+
+	// The day runs 06:00 for both, then Peony 09:30, Azilea 10:00 and Peony 11:00.
+	clock.step(t, at(22, 6, 0))
+	peony.expectFetch(t, PEONY, at(22, 6, 0))
+	azilea.expectFetch(t, AZILEA, at(22, 6, 0))
+
+	clock.step(t, at(22, 9, 30))
+	peony.expectFetch(t, PEONY, at(22, 9, 30))
+
+	clock.step(t, at(22, 10, 0))
+	azilea.expectFetch(t, AZILEA, at(22, 10, 0))
+
+	clock.step(t, at(22, 11, 0))
+	peony.expectFetch(t, PEONY, at(22, 11, 0))
+
+	clock.step(t, at(23, 6, 0))
+	peony.expectFetch(t, PEONY, at(23, 6, 0))
+	azilea.expectFetch(t, AZILEA, at(23, 6, 0))
+}
+
+func TestSchedulerRunsPastHangingUpdate(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.October, day, hour, minute, 0, 0, kst)
+	}
+
+	clock := newManualClock(at(22, 5, 0))
+	peony, azilea := newTimedSource(clock, true), newTimedSource(clock, false)
+	scheduler := startScheduler(t, clock, peony, azilea, []string{"0 6 * * *", "0 10 * * *"})
+
+	clock.step(t, at(22, 6, 0))
+	peony.expectFetch(t, PEONY, at(22, 6, 0))
+	azilea.expectFetch(t, AZILEA, at(22, 6, 0))
+
+	// Peony's update is still hanging, but the loop goes on to Azilea's 10:00 run.
+	clock.step(t, at(22, 9, 30))
+	clock.step(t, at(22, 10, 0))
+	azilea.expectFetch(t, AZILEA, at(22, 10, 0))
+
+	stopped := make(chan struct{})
+	go func() {
+		scheduler.cancel()
+		scheduler.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler did not stop with Peony's update hanging")
+	}
+}
+
+func TestSchedulerNextRuns(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.October, day, hour, minute, 0, 0, kst)
+	}
+
+	scheduler := NewMenuScheduler(nil, fixedClock(at(22, 5, 0)))
+	if err := scheduler.SetSchedule(AZILEA, []string{"0 6 * * *", "0 10 * * 1-5"}); err != nil {
+		t.Fatalf("SetSchedule() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		from  time.Time
+		want  time.Time
+		cafes []Cafeteria
+	}{
+		{name: "morning fetch for both", from: at(22, 5, 0), want: at(22, 6, 0), cafes: []Cafeteria{PEONY, AZILEA}},
+		{name: "peony re-check", from: at(22, 6, 0), want: at(22, 9, 30), cafes: []Cafeteria{PEONY}},
+		{name: "azilea weekday re-check", from: at(22, 9, 30), want: at(22, 10, 0), cafes: []Cafeteria{AZILEA}},
+		{name: "peony final", from: at(22, 10, 0), want: at(22, 11, 0), cafes: []Cafeteria{PEONY}},
+		{name: "next day", from: at(22, 11, 0), want: at(23, 6, 0), cafes: []Cafeteria{PEONY, AZILEA}},
+		// October 25th is a Saturday, so the weekday-only run is skipped.
+		{name: "weekend", from: at(25, 9, 30), want: at(25, 11, 0), cafes: []Cafeteria{PEONY}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, due := nextRuns(scheduler.runs, tt.from)
+			if !got.Equal(tt.want) {
+				t.Fatalf("nextRuns() = %v, want %v", got, tt.want)
+			}
+			if len(due) != len(tt.cafes) {
+				t.Fatalf("due = %d runs, want %v", len(due), tt.cafes)
+			}
+			for _, cafeteria := range tt.cafes {
+				if !containsCafeteria(due, cafeteria) {
+					t.Errorf("%s is not due at %v", cafeteria, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulerSetScheduleRejectsInvalidSpecs(t *testing.T) {
+	scheduler := NewMenuScheduler(nil, nil)
+
+	for _, specs := range [][]string{nil, {"every morning"}, {"0 6 * * *", "61 6 * * *"}} {
+		if err := scheduler.SetSchedule(PEONY, specs); err == nil {
+			t.Errorf("SetSchedule(%q) succeeded, want an error", specs)
+		}
+	}
+
+	// A rejected schedule leaves the previous one in place.
+	if got, _ := nextRuns(scheduler.runs, time.Date(2025, time.October, 22, 7, 0, 0, 0, time.UTC)); got.Hour() != 9 {
+		t.Errorf("next run = %v, want the default 09:30 re-check", got)
+	}
+}
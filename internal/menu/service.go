@@ -5,11 +5,20 @@ import (
 	"fmt"
 	"log/slog"
 	"maps"
+	"sync"
+	"time"
 )
 
+// MenuChangeHandler is notified when a refresh replaces a stored menu with a different dish list.
+// Handlers run on the refreshing goroutine and should hand slow work off to their own.
+type MenuChangeHandler func(ctx context.Context, cafeteria Cafeteria, previous, current *Menu)
+
 type MenuService struct {
 	persistence *MenuPersistenceService
 	fetchers    map[Cafeteria]*MenuFetcherService
+
+	mu             sync.RWMutex
+	changeHandlers []MenuChangeHandler
 }
 
 func NewMenuService(persistence *MenuPersistenceService, fetchers map[Cafeteria]*MenuFetcherService) *MenuService {
@@ -22,12 +31,24 @@ func NewMenuService(persistence *MenuPersistenceService, fetchers map[Cafeteria]
 	}
 }
 
+func (s *MenuService) OnMenuChange(handler MenuChangeHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.changeHandlers = append(s.changeHandlers, handler)
+}
+
 func (s *MenuService) GetMenuWithContext(ctx context.Context, cafeteria Cafeteria) (*Menu, error) {
+	return s.GetMenuForDate(ctx, cafeteria, s.persistence.clock.Now())
+}
+
+// GetMenuForDate returns the stored menu for the date, fetching it from the weekly page when missing.
+func (s *MenuService) GetMenuForDate(ctx context.Context, cafeteria Cafeteria, date time.Time) (*Menu, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	menu, err := s.persistence.LoadMenu(cafeteria)
+	menu, err := s.persistence.LoadMenuForDate(cafeteria, date)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +64,12 @@ func (s *MenuService) GetMenuWithContext(ctx context.Context, cafeteria Cafeteri
 		return nil, err
 	}
 
-	return s.RefreshMenuWithContext(ctx, cafeteria)
+	return s.RefreshMenuForDate(ctx, cafeteria, date)
+}
+
+// GetStoredMenuForDate returns the menu stored for the date without fetching; nil when none is stored.
+func (s *MenuService) GetStoredMenuForDate(cafeteria Cafeteria, date time.Time) (*Menu, error) {
+	return s.persistence.LoadMenuForDate(cafeteria, date)
 }
 
 func (s *MenuService) GetMenu(cafeteria Cafeteria) (*Menu, error) {
@@ -55,6 +81,10 @@ func (s *MenuService) RefreshMenu(cafeteria Cafeteria) (*Menu, error) {
 }
 
 func (s *MenuService) RefreshMenuWithContext(ctx context.Context, cafeteria Cafeteria) (*Menu, error) {
+	return s.RefreshMenuForDate(ctx, cafeteria, s.persistence.clock.Now())
+}
+
+func (s *MenuService) RefreshMenuForDate(ctx context.Context, cafeteria Cafeteria, date time.Time) (*Menu, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -65,9 +95,17 @@ func (s *MenuService) RefreshMenuWithContext(ctx context.Context, cafeteria Cafe
 	}
 
 	slog.Info("Fetching fresh menu from external source",
-		"cafeteria", string(cafeteria))
+		"cafeteria", string(cafeteria),
+		"date", date.Format("2006-01-02"))
+
+	previous, err := s.persistence.LoadMenuForDate(cafeteria, date)
+	if err != nil {
+		slog.Warn("Failed to load previous menu for change detection",
+			"error", err,
+			"cafeteria", string(cafeteria))
+	}
 
-	menu, err := fetcher.FetchMenuWithContext(ctx)
+	menu, err := fetcher.RefetchMenuForDate(ctx, date, previous)
 	if err != nil {
 		slog.Error("Failed to fetch menu from external source",
 			"error", err,
@@ -75,20 +113,101 @@ func (s *MenuService) RefreshMenuWithContext(ctx context.Context, cafeteria Cafe
 		return nil, fmt.Errorf("menu fetch failed for %s: %w", string(cafeteria), err)
 	}
 
+	if menu == previous {
+		slog.Info("Menu unchanged since last fetch",
+			"cafeteria", string(cafeteria),
+			"item_count", len(menu.Items))
+		return menu, nil
+	}
+
 	slog.Info("Successfully fetched menu",
 		"cafeteria", string(cafeteria),
 		"item_count", len(menu.Items))
 
-	if err := s.persistence.SaveMenu(cafeteria, menu); err != nil {
+	if err := s.persistence.SaveMenuForDate(cafeteria, menu, date); err != nil {
 		slog.Error("Failed to update database with new menu",
 			"error", err,
 			"cafeteria", string(cafeteria))
 		return nil, fmt.Errorf("database update failed for %s: %w", string(cafeteria), err)
 	}
 
+	if previous != nil && !previous.HasSameDishes(menu) && s.persistence.IsToday(date) {
+		slog.Info("Detected menu change",
+			"cafeteria", string(cafeteria),
+			"previous_count", len(previous.Items),
+			"current_count", len(menu.Items))
+		s.notifyMenuChange(ctx, cafeteria, previous, menu)
+	}
+
+	return menu, nil
+}
+
+// EditDish corrects the stored name or description of today's dish at the 1-based position.
+// Empty values keep the current ones. Renaming a dish notifies change handlers like a refresh would.
+func (s *MenuService) EditDish(ctx context.Context, cafeteria Cafeteria, position int, name, description string) (*Menu, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	date := s.persistence.clock.Now()
+	menu, err := s.persistence.LoadMenuForDate(cafeteria, date)
+	if err != nil {
+		return nil, err
+	}
+	if menu == nil {
+		return nil, fmt.Errorf("no stored menu for %s today", string(cafeteria))
+	}
+	if position < 1 || position > len(menu.Items) {
+		return nil, fmt.Errorf("dish %d is out of range, %s has %d dishes", position, string(cafeteria), len(menu.Items))
+	}
+
+	previousItems := make([]*MenuItem, len(menu.Items))
+	for i, item := range menu.Items {
+		copied := *item
+		previousItems[i] = &copied
+	}
+	previous := NewMenu(previousItems, menu.Time)
+
+	item := menu.Items[position-1]
+	if name != "" {
+		item.Name = name
+	}
+	if description != "" {
+		item.Description = description
+	}
+
+	if err := s.persistence.SaveMenuForDate(cafeteria, menu, date); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Edited stored dish",
+		"cafeteria", string(cafeteria),
+		"position", position,
+		"name", item.Name)
+
+	if !previous.HasSameDishes(menu) {
+		s.notifyMenuChange(ctx, cafeteria, previous, menu)
+	}
+
 	return menu, nil
 }
 
+func (s *MenuService) notifyMenuChange(ctx context.Context, cafeteria Cafeteria, previous, current *Menu) {
+	s.mu.RLock()
+	handlers := make([]MenuChangeHandler, len(s.changeHandlers))
+	copy(handlers, s.changeHandlers)
+	s.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx, cafeteria, previous, current)
+	}
+}
+
+// GetRevisionsForDate returns how the day's menu changed over time, oldest revision first.
+func (s *MenuService) GetRevisionsForDate(cafeteria Cafeteria, date time.Time) ([]MenuRevision, error) {
+	return s.persistence.LoadRevisionsForDate(cafeteria, date)
+}
+
 func (s *MenuService) GetPeonyMenu() (*Menu, error) {
 	return s.GetMenu(PEONY)
 }
@@ -110,3 +229,17 @@ func (s *MenuService) GetMenus() (*Menu, *Menu, error) {
 
 	return peony, azilea, nil
 }
+
+func (s *MenuService) GetMenusForDate(ctx context.Context, date time.Time) (*Menu, *Menu, error) {
+	peony, err := s.GetMenuForDate(ctx, PEONY, date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	azilea, err := s.GetMenuForDate(ctx, AZILEA, date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return peony, azilea, nil
+}
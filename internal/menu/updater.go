@@ -6,22 +6,57 @@ import (
 	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/retry"
 )
 
+// MenuUpdateHandler is called after a cafeteria menu was successfully refreshed.
+type MenuUpdateHandler func(ctx context.Context, cafeteria Cafeteria, menu *Menu)
+
 type MenuUpdater struct {
 	menuService *MenuService
+	clock       Clock
 	retryCount  int
 	retryDelay  time.Duration
+
+	// Polling is off while pollInterval is zero; pollCutoff is a time of day.
+	pollInterval time.Duration
+	pollCutoff   time.Duration
+
+	mu       sync.RWMutex
+	handlers []MenuUpdateHandler
+	polling  map[Cafeteria]bool
 }
 
-func NewMenuUpdater(menuService *MenuService) *MenuUpdater {
+// NewMenuUpdater refreshes menus through menuService; clock decides the polling cutoff and paces the polls.
+func NewMenuUpdater(menuService *MenuService, clock Clock) *MenuUpdater {
+	if clock == nil {
+		clock = NewKSTClock()
+	}
+
 	return &MenuUpdater{
 		menuService: menuService,
+		clock:       clock,
 		retryCount:  3,
 		retryDelay:  5 * time.Minute,
+		polling:     make(map[Cafeteria]bool),
 	}
 }
 
+// EnablePolling makes every update re-fetch the menu each interval until the cafeteria publishes it
+// or the cutoff, a time of day in the clock's timezone such as 13*time.Hour, passes.
+func (u *MenuUpdater) EnablePolling(interval, cutoff time.Duration) {
+	u.pollInterval = interval
+	u.pollCutoff = cutoff
+}
+
+func (u *MenuUpdater) OnUpdate(handler MenuUpdateHandler) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.handlers = append(u.handlers, handler)
+}
+
 func (u *MenuUpdater) UpdateAll(ctx context.Context) error {
 	if ctx == nil {
 		ctx = context.Background()
@@ -76,41 +111,107 @@ func (u *MenuUpdater) updateCafeteria(ctx context.Context, cafeteria Cafeteria)
 		ctx = context.Background()
 	}
 
-	var lastErr error
+	if u.pollInterval <= 0 {
+		_, err := u.refreshWithRetries(ctx, cafeteria)
+		return err
+	}
 
-	for attempt := 1; attempt <= u.retryCount; attempt++ {
-		if err := ctx.Err(); err != nil {
-			return err
+	// A scheduled re-check may start while an earlier run is still waiting for the menu.
+	if !u.startPolling(cafeteria) {
+		slog.Info("Menu polling already in progress", "cafeteria", string(cafeteria))
+		return nil
+	}
+	defer u.stopPolling(cafeteria)
+
+	for {
+		menu, err := u.refreshWithRetries(ctx, cafeteria)
+		if err == nil && len(menu.Items) > 1 {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
 		}
 
-		if attempt > 1 {
-			slog.Info("Retry attempt",
-				"attempt", attempt,
+		now := u.clock.Now()
+		if !now.Add(u.pollInterval).Before(u.cutoffFor(now)) {
+			slog.Info("Menu was not published before the polling cutoff",
 				"cafeteria", string(cafeteria))
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(u.retryDelay):
-			}
+			return err
 		}
 
-		_, err := u.menuService.RefreshMenuWithContext(ctx, cafeteria)
-		if err == nil {
-			slog.Info("Successfully updated",
-				"cafeteria", string(cafeteria))
-			return nil
+		slog.Info("Menu not published yet, polling again",
+			"cafeteria", string(cafeteria),
+			"interval", u.pollInterval.String())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-u.clock.After(u.pollInterval):
 		}
+	}
+}
+
+func (u *MenuUpdater) cutoffFor(now time.Time) time.Time {
+	year, month, day := now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location()).Add(u.pollCutoff)
+}
+
+func (u *MenuUpdater) startPolling(cafeteria Cafeteria) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
 
-		lastErr = err
-		slog.Error("Update attempt failed",
-			"error", err,
-			"attempt", attempt,
-			"cafeteria", string(cafeteria))
+	if u.polling[cafeteria] {
+		return false
 	}
+	u.polling[cafeteria] = true
+	return true
+}
 
-	if err := ctx.Err(); err != nil {
+func (u *MenuUpdater) stopPolling(cafeteria Cafeteria) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	delete(u.polling, cafeteria)
+}
+
+// refreshWithRetries retries transient failures on a scale of minutes. The fetcher and the AI client already
+// retry quick blips and trip their circuit breakers on outages, so an open breaker or a parse error ends it early.
+func (u *MenuUpdater) refreshWithRetries(ctx context.Context, cafeteria Cafeteria) (*Menu, error) {
+	policy := retry.Policy{
+		Name:      "update " + string(cafeteria),
+		Attempts:  u.retryCount,
+		BaseDelay: u.retryDelay,
+		MaxDelay:  4 * u.retryDelay,
+	}
+
+	var menu *Menu
+	err := retry.Do(ctx, policy, func(ctx context.Context) error {
+		var err error
+		menu, err = u.menuService.RefreshMenuWithContext(ctx, cafeteria)
+		if err != nil {
+			slog.Error("Update attempt failed",
+				"error", err,
+				"class", retry.Classify(err).String(),
+				"cafeteria", string(cafeteria))
+		}
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return lastErr
+	slog.Info("Successfully updated",
+		"cafeteria", string(cafeteria))
+	u.notifyUpdate(ctx, cafeteria, menu)
+	return menu, nil
+}
+
+func (u *MenuUpdater) notifyUpdate(ctx context.Context, cafeteria Cafeteria, menu *Menu) {
+	u.mu.RLock()
+	handlers := make([]MenuUpdateHandler, len(u.handlers))
+	copy(handlers, u.handlers)
+	u.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx, cafeteria, menu)
+	}
 }
@@ -0,0 +1,118 @@
+package menu
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database/databasetest"
+)
+
+// pageSource serves the dish lists of consecutive fetches and keeps serving the last one.
+type pageSource struct {
+	mu    sync.Mutex
+	pages [][]string
+	calls int
+}
+
+func (s *pageSource) FetchMenu(ctx context.Context, date time.Time) (*Menu, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	page := s.pages[min(s.calls, len(s.pages)-1)]
+	s.calls++
+	return NewMenuFromDishes(page, &date), nil
+}
+
+type countingEnricher struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (e *countingEnricher) Enrich(ctx context.Context, menu *Menu) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.calls++
+	for _, item := range menu.Items {
+		item.Description = "Описание"
+	}
+	return nil
+}
+
+func newTestRepository(t *testing.T) *MenuRepository {
+	t.Helper()
+
+	return NewMenuRepository(databasetest.New(t))
+}
+
+func newPollingUpdater(t *testing.T, now time.Time, source *pageSource, enricher *countingEnricher) *MenuUpdater {
+	t.Helper()
+
+	clock := fixedClock(now)
+	service := NewMenuService(NewMenuPersistenceService(newTestRepository(t), clock), map[Cafeteria]*MenuFetcherService{
+		PEONY: NewMenuFetcherPipeline(source, nil, enricher, clock),
+	})
+
+	updater := NewMenuUpdater(service, clock)
+	updater.EnablePolling(time.Millisecond, 13*time.Hour)
+	return updater
+}
+
+func TestUpdaterPollsUntilMenuIsPublished(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	source := &pageSource{pages: [][]string{{}, {}, {"김치찌개", "쌀밥"}}}
+	enricher := &countingEnricher{}
+	updater := newPollingUpdater(t, time.Date(2025, time.October, 22, 6, 0, 0, 0, kst), source, enricher)
+
+	if err := updater.UpdateCafeteria(context.Background(), PEONY); err != nil {
+		t.Fatalf("UpdateCafeteria() error = %v", err)
+	}
+	if source.calls != 3 {
+		t.Errorf("source fetched %d times, want 3", source.calls)
+	}
+	// The repeated empty page is recognised, so only the first empty page and the real menu are processed.
+	if enricher.calls != 2 {
+		t.Errorf("enricher ran %d times, want 2", enricher.calls)
+	}
+
+	stored, err := updater.menuService.GetMenuWithContext(context.Background(), PEONY)
+	if err != nil || stored == nil || len(stored.Items) != 2 || stored.Items[0].Description != "Описание" {
+		t.Fatalf("stored menu = %+v, %v; want the enriched menu", stored, err)
+	}
+
+	// A re-check that finds the same dishes reuses the stored menu instead of enriching it again.
+	if err := updater.UpdateCafeteria(context.Background(), PEONY); err != nil {
+		t.Fatalf("UpdateCafeteria() error = %v", err)
+	}
+	if source.calls != 4 || enricher.calls != 2 {
+		t.Errorf("re-check: source %d, enricher %d calls; want 4 and 2", source.calls, enricher.calls)
+	}
+}
+
+func TestUpdaterStopsPollingAtCutoff(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	source := &pageSource{pages: [][]string{{}}}
+	updater := newPollingUpdater(t, time.Date(2025, time.October, 22, 13, 30, 0, 0, kst), source, &countingEnricher{})
+
+	if err := updater.UpdateCafeteria(context.Background(), PEONY); err != nil {
+		t.Fatalf("UpdateCafeteria() error = %v", err)
+	}
+	if source.calls != 1 {
+		t.Errorf("source fetched %d times after the cutoff, want 1", source.calls)
+	}
+}
+
+func TestMenuDishHash(t *testing.T) {
+	a := NewMenuFromDishes([]string{"김치찌개", "쌀밥"}, nil)
+	b := NewMenu([]*MenuItem{{Name: "김치찌개", Description: "Суп"}, {Name: "쌀밥"}}, nil)
+	joined := NewMenuFromDishes([]string{"김치찌개쌀밥"}, nil)
+
+	if a.DishHash() != b.DishHash() {
+		t.Error("descriptions changed the dish hash")
+	}
+	if a.DishHash() == joined.DishHash() {
+		t.Error("different dish lists share a hash")
+	}
+}
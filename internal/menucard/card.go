@@ -0,0 +1,271 @@
+// Package menucard draws the daily menu of both cafeterias as a shareable PNG card.
+package menucard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"time"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/webp"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+// The card is laid out in base units and drawn at scale, so text is rasterized at its final size.
+const (
+	scale        = 2
+	baseWidth    = 400
+	padding      = 12
+	columnGap    = 12
+	lineHeight   = 16
+	headerHeight = 44
+	stickerSize  = 36
+	spicyDotSize = 5
+	maxSpiciness = 5
+
+	headerTitleSize = 14
+	headerDateSize  = 11
+	columnTitleSize = 12
+	dishSize        = 11
+
+	headerTitle   = "Меню столовых KBU"
+	closedMessage = "Выходной"
+)
+
+var (
+	backgroundColor = color.RGBA{R: 0xfd, G: 0xf8, B: 0xf0, A: 0xff}
+	headerColor     = color.RGBA{R: 0x2f, G: 0x3e, B: 0x46, A: 0xff}
+	headerTextColor = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	titleColor      = color.RGBA{R: 0xc0, G: 0x39, B: 0x2b, A: 0xff}
+	dishColor       = color.RGBA{R: 0x33, G: 0x33, B: 0x33, A: 0xff}
+	mutedColor      = color.RGBA{R: 0x99, G: 0x99, B: 0x99, A: 0xff}
+	spicyColor      = color.RGBA{R: 0xe7, G: 0x4c, B: 0x3c, A: 0xff}
+)
+
+var (
+	weekdayNames = [...]string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"}
+	monthNames   = [...]string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"}
+)
+
+type Renderer struct {
+	sticker image.Image
+}
+
+// NewRenderer loads the sticker drawn in the card header from assets. A missing sticker only removes the decoration.
+func NewRenderer(assets fs.FS, stickerPath string) *Renderer {
+	renderer := &Renderer{}
+
+	sticker, err := loadSticker(assets, stickerPath)
+	if err != nil {
+		slog.Warn("Failed to load menu card sticker", "path", stickerPath, "error", err)
+		return renderer
+	}
+
+	renderer.sticker = sticker
+	return renderer
+}
+
+func loadSticker(assets fs.FS, path string) (image.Image, error) {
+	file, err := assets.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return webp.Decode(file)
+}
+
+type column struct {
+	title string
+	lines []dishLine
+}
+
+type dishLine struct {
+	text      string
+	spiciness int
+	muted     bool
+}
+
+// faces holds the faces of one Render call; opentype faces must not be shared between goroutines.
+type faces struct {
+	headerTitle, headerDate, columnTitle, dish font.Face
+}
+
+func newFaces() faces {
+	return faces{
+		headerTitle: newFace(boldFont, headerTitleSize*scale),
+		headerDate:  newFace(regularFont, headerDateSize*scale),
+		columnTitle: newFace(boldFont, columnTitleSize*scale),
+		dish:        newFace(regularFont, dishSize*scale),
+	}
+}
+
+func (f faces) Close() {
+	for _, face := range []font.Face{f.headerTitle, f.headerDate, f.columnTitle, f.dish} {
+		face.Close()
+	}
+}
+
+// Render draws the menus for date and encodes the card as PNG.
+func (r *Renderer) Render(date time.Time, peony, azilea *menu.Menu) ([]byte, error) {
+	faces := newFaces()
+	defer faces.Close()
+
+	columnWidth := (baseWidth - 2*padding - columnGap) / 2
+	columns := []column{
+		buildColumn("Peony (верхняя)", peony, faces.dish, columnWidth*scale),
+		buildColumn("Azilea (нижняя)", azilea, faces.dish, columnWidth*scale),
+	}
+
+	rows := 0
+	for _, c := range columns {
+		rows = max(rows, len(c.lines))
+	}
+	height := headerHeight + padding + lineHeight*(rows+2) + padding
+
+	card := image.NewRGBA(image.Rect(0, 0, baseWidth*scale, height*scale))
+	fill(card, card.Bounds(), backgroundColor)
+	fill(card, image.Rect(0, 0, baseWidth*scale, headerHeight*scale), headerColor)
+
+	drawText(card, faces.headerTitle, padding, 19, headerTextColor, headerTitle)
+	drawText(card, faces.headerDate, padding, 36, headerTextColor, formatDate(date))
+
+	for i, c := range columns {
+		x := padding + i*(columnWidth+columnGap)
+		y := headerHeight + padding + lineHeight
+		drawText(card, faces.columnTitle, x, y, titleColor, c.title)
+
+		for _, line := range c.lines {
+			y += lineHeight
+			textColor := dishColor
+			if line.muted {
+				textColor = mutedColor
+			}
+			end := drawText(card, faces.dish, x, y, textColor, line.text)
+			drawSpiciness(card, end+4*scale, (y-spicyDotSize-3)*scale, line.spiciness)
+		}
+	}
+
+	if r.sticker != nil {
+		size := stickerSize * scale
+		margin := (headerHeight*scale - size) / 2
+		target := image.Rect(card.Bounds().Dx()-size-margin, margin, card.Bounds().Dx()-margin, margin+size)
+		draw.CatmullRom.Scale(card, target, r.sticker, r.sticker.Bounds(), draw.Over, nil)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, card); err != nil {
+		return nil, fmt.Errorf("encode menu card: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatDate reads like "среда, 22 октября 2025".
+func formatDate(date time.Time) string {
+	return fmt.Sprintf("%s, %d %s %d", weekdayNames[date.Weekday()], date.Day(), monthNames[date.Month()-1], date.Year())
+}
+
+// buildColumn wraps dish names to width pixels of face.
+func buildColumn(title string, cafeteriaMenu *menu.Menu, face font.Face, width int) column {
+	c := column{title: title}
+
+	if cafeteriaMenu == nil || len(cafeteriaMenu.Items) <= 1 {
+		c.lines = append(c.lines, dishLine{text: closedMessage, muted: true})
+		return c
+	}
+
+	// Leave room for the spiciness dots after the last line of every dish.
+	dotsWidth := (maxSpiciness*(spicyDotSize+1) + 4) * scale
+	for i, item := range cafeteriaMenu.Items {
+		wrapped := wrap(face, fmt.Sprintf("%d. %s", i+1, strings.Join(strings.Fields(item.Name), " ")), width-dotsWidth)
+		for j, text := range wrapped {
+			line := dishLine{text: text}
+			if j > 0 {
+				line.text = "   " + text
+			}
+			if j == len(wrapped)-1 {
+				line.spiciness = item.Spiciness
+			}
+			c.lines = append(c.lines, line)
+		}
+	}
+	return c
+}
+
+// wrap breaks text into lines no wider than width pixels, splitting words that do not fit on a line of their own.
+func wrap(face font.Face, text string, width int) []string {
+	fits := func(s string) bool {
+		return font.MeasureString(face, s).Ceil() <= width
+	}
+
+	var (
+		lines   []string
+		current string
+	)
+	for _, word := range strings.Fields(text) {
+		for word != "" && !fits(word) {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			runes := []rune(word)
+			cut := 1
+			for cut < len(runes) && fits(string(runes[:cut+1])) {
+				cut++
+			}
+			lines = append(lines, string(runes[:cut]))
+			word = string(runes[cut:])
+		}
+		if word == "" {
+			continue
+		}
+
+		switch {
+		case current == "":
+			current = word
+		case fits(current + " " + word):
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// drawText writes text with its baseline at y, both in base units, and returns the pixel x coordinate where it ends.
+func drawText(dst draw.Image, face font.Face, x, y int, textColor color.Color, text string) int {
+	drawer := font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(textColor),
+		Face: face,
+		Dot:  fixed.P(x*scale, y*scale),
+	}
+	drawer.DrawString(text)
+	return drawer.Dot.X.Round()
+}
+
+// drawSpiciness draws up to maxSpiciness dots starting at the pixel coordinates x and y.
+func drawSpiciness(dst *image.RGBA, x, y, spiciness int) {
+	size := spicyDotSize * scale
+	for i := range min(spiciness, maxSpiciness) {
+		left := x + i*(size+scale)
+		fill(dst, image.Rect(left, y, left+size, y+size), spicyColor)
+	}
+}
+
+func fill(dst *image.RGBA, rect image.Rectangle, fillColor color.Color) {
+	draw.Draw(dst, rect, image.NewUniform(fillColor), image.Point{}, draw.Src)
+}
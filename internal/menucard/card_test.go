@@ -0,0 +1,133 @@
+package menucard
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+
+	kbudailymenu "github.com/artyom-kalman/kbu-daily-menu"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+func TestRender(t *testing.T) {
+	renderer := NewRenderer(kbudailymenu.Assets(""), "web/static/stickers/kto.webp")
+	if renderer.sticker == nil {
+		t.Fatal("sticker was not loaded")
+	}
+
+	date := time.Date(2025, time.October, 22, 0, 0, 0, 0, time.UTC)
+	short := menu.NewMenu([]*menu.MenuItem{{Name: "김치찌개", Spiciness: 4}, {Name: "밥"}}, nil)
+	long := menu.NewMenu([]*menu.MenuItem{
+		{Name: "김치찌개"}, {Name: "돈까스"}, {Name: "비빔밥"}, {Name: "된장국"},
+		{Name: "제육볶음"}, {Name: "계란말이"}, {Name: "잡채"}, {Name: "깍두기"},
+	}, nil)
+
+	shortCard := decode(t, renderer, date, short, nil)
+	longCard := decode(t, renderer, date, short, long)
+
+	if shortCard.Dx() != baseWidth*scale {
+		t.Errorf("card width = %d, want %d", shortCard.Dx(), baseWidth*scale)
+	}
+	if longCard.Dy() <= shortCard.Dy() {
+		t.Errorf("card with more dishes is %dpx high, want more than %dpx", longCard.Dy(), shortCard.Dy())
+	}
+}
+
+func decode(t *testing.T, renderer *Renderer, date time.Time, peony, azilea *menu.Menu) image.Rectangle {
+	t.Helper()
+
+	data, err := renderer.Render(date, peony, azilea)
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	card, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode rendered card: %v", err)
+	}
+	return card.Bounds()
+}
+
+func TestFacesCoverMenuScripts(t *testing.T) {
+	var buf sfnt.Buffer
+	for _, r := range "Fish & chips Борщ со сметаной Выходной" {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		if index, err := regularFont.GlyphIndex(&buf, r); err != nil || index == 0 {
+			t.Errorf("regular font has no glyph for %q", r)
+		}
+	}
+
+	face := newFace(regularFont, dishSize*scale)
+	defer face.Close()
+
+	masks := map[string]rune{}
+	for _, r := range "김치찌개돈까스닭갈비덮밥왜의값" {
+		_, mask, _, advance, ok := face.Glyph(fixed.P(0, 20), r)
+		if !ok || advance <= 0 {
+			t.Fatalf("Glyph(%q) ok = %v, advance = %v", r, ok, advance)
+		}
+
+		alpha := mask.(*image.Alpha)
+		inked := 0
+		for _, a := range alpha.Pix {
+			if a > 0 {
+				inked++
+			}
+		}
+		if inked == 0 {
+			t.Errorf("Glyph(%q) draws nothing", r)
+		}
+
+		key := string(alpha.Pix)
+		if other, ok := masks[key]; ok {
+			t.Errorf("Glyph(%q) looks exactly like %q", r, other)
+		}
+		masks[key] = r
+	}
+}
+
+func TestWrap(t *testing.T) {
+	face := newFace(regularFont, dishSize*scale)
+	defer face.Close()
+
+	tests := []struct {
+		text  string
+		width int
+		want  int
+	}{
+		{text: "1. 돈까스 정식 with rice", width: 1000, want: 1},
+		{text: "1. 돈까스 정식 with rice", width: 120, want: 3},
+		{text: "닭갈비덮밥과된장국", width: 100, want: 3},
+	}
+
+	for _, tt := range tests {
+		lines := wrap(face, tt.text, tt.width)
+		if len(lines) != tt.want {
+			t.Errorf("wrap(%q, %d) = %q, want %d lines", tt.text, tt.width, lines, tt.want)
+		}
+		if strings.ReplaceAll(strings.Join(lines, ""), " ", "") != strings.ReplaceAll(tt.text, " ", "") {
+			t.Errorf("wrap(%q, %d) = %q lost text", tt.text, tt.width, lines)
+		}
+		for _, line := range lines {
+			if width := font.MeasureString(face, line).Ceil(); width > tt.width {
+				t.Errorf("line %q is %dpx wide, want at most %d", line, width, tt.width)
+			}
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2025, time.October, 22, 0, 0, 0, 0, time.UTC)
+	if got, want := formatDate(date), "среда, 22 октября 2025"; got != want {
+		t.Errorf("formatDate() = %q, want %q", got, want)
+	}
+}
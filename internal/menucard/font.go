@@ -0,0 +1,72 @@
+package menucard
+
+import (
+	"image"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// The Go fonts are compiled into the binary and cover Latin and Cyrillic; Hangul comes from hangulFace.
+var (
+	regularFont = mustParseFont(goregular.TTF)
+	boldFont    = mustParseFont(gobold.TTF)
+)
+
+func mustParseFont(data []byte) *opentype.Font {
+	parsed, err := opentype.Parse(data)
+	if err != nil {
+		panic("menucard: parse embedded font: " + err.Error())
+	}
+	return parsed
+}
+
+// newFace returns a face of size pixels that draws Hangul syllables with hangulFace and everything else with typeface.
+func newFace(typeface *opentype.Font, size float64) font.Face {
+	primary, err := opentype.NewFace(typeface, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		panic("menucard: create font face: " + err.Error())
+	}
+	return &fallbackFace{
+		primary: primary,
+		hangul:  &hangulFace{size: size, metrics: primary.Metrics()},
+	}
+}
+
+type fallbackFace struct {
+	primary font.Face
+	hangul  *hangulFace
+}
+
+func (f *fallbackFace) face(r rune) font.Face {
+	if isHangul(r) {
+		return f.hangul
+	}
+	return f.primary
+}
+
+func (f *fallbackFace) Close() error { return f.primary.Close() }
+
+func (f *fallbackFace) Metrics() font.Metrics { return f.primary.Metrics() }
+
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	if isHangul(r0) || isHangul(r1) {
+		return 0
+	}
+	return f.primary.Kern(r0, r1)
+}
+
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return f.face(r).Glyph(dot, r)
+}
+
+func (f *fallbackFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return f.face(r).GlyphBounds(r)
+}
+
+func (f *fallbackFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return f.face(r).GlyphAdvance(r)
+}
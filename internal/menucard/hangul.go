@@ -0,0 +1,245 @@
+package menucard
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// Precomposed Hangul syllables are composed from an initial, a medial and an optional final jamo:
+// syllable = 0xAC00 + (initial*21 + medial)*28 + final.
+const (
+	hangulFirst  = 0xAC00
+	hangulLast   = 0xD7A3
+	medialCount  = 21
+	finalCount   = 28
+	hangulStroke = 0.05 // stroke half-width in ems
+)
+
+var (
+	hangulInitials = []string{"ㄱ", "ㄱㄱ", "ㄴ", "ㄷ", "ㄷㄷ", "ㄹ", "ㅁ", "ㅂ", "ㅂㅂ", "ㅅ", "ㅅㅅ", "ㅇ", "ㅈ", "ㅈㅈ", "ㅊ", "ㅋ", "ㅌ", "ㅍ", "ㅎ"}
+	hangulFinals   = []string{"", "ㄱ", "ㄱㄱ", "ㄱㅅ", "ㄴ", "ㄴㅈ", "ㄴㅎ", "ㄷ", "ㄹ", "ㄹㄱ", "ㄹㅁ", "ㄹㅂ", "ㄹㅅ", "ㄹㅌ", "ㄹㅍ", "ㄹㅎ", "ㅁ", "ㅂ", "ㅂㅅ", "ㅅ", "ㅅㅅ", "ㅇ", "ㅈ", "ㅊ", "ㅋ", "ㅌ", "ㅍ", "ㅎ"}
+
+	// hangulMedials splits every vowel into the part drawn below the initial and the part drawn to its right.
+	hangulMedials = []struct{ below, right string }{
+		{"", "ㅏ"}, {"", "ㅐ"}, {"", "ㅑ"}, {"", "ㅒ"}, {"", "ㅓ"}, {"", "ㅔ"}, {"", "ㅕ"}, {"", "ㅖ"},
+		{"ㅗ", ""}, {"ㅗ", "ㅏ"}, {"ㅗ", "ㅐ"}, {"ㅗ", "ㅣ"}, {"ㅛ", ""},
+		{"ㅜ", ""}, {"ㅜ", "ㅓ"}, {"ㅜ", "ㅔ"}, {"ㅜ", "ㅣ"}, {"ㅠ", ""},
+		{"ㅡ", ""}, {"ㅡ", "ㅣ"}, {"", "ㅣ"},
+	}
+)
+
+// jamo is drawn in a unit box: polylines are stroked, rings are ellipses around a center.
+type jamo struct {
+	lines [][]float64
+	rings [][3]float64
+}
+
+func lines(points ...[]float64) jamo { return jamo{lines: points} }
+
+var jamoShapes = map[string]jamo{
+	"ㄱ": lines([]float64{0.1, 0.15, 0.85, 0.15, 0.85, 0.95}),
+	"ㄴ": lines([]float64{0.15, 0.08, 0.15, 0.85, 0.92, 0.85}),
+	"ㄷ": lines([]float64{0.88, 0.15, 0.15, 0.15, 0.15, 0.85, 0.9, 0.85}),
+	"ㄹ": lines([]float64{0.12, 0.12, 0.85, 0.12, 0.85, 0.48, 0.15, 0.48, 0.15, 0.86, 0.9, 0.86}),
+	"ㅁ": lines([]float64{0.15, 0.15, 0.85, 0.15, 0.85, 0.85, 0.15, 0.85, 0.15, 0.15}),
+	"ㅂ": lines([]float64{0.15, 0.08, 0.15, 0.88, 0.85, 0.88, 0.85, 0.08}, []float64{0.15, 0.48, 0.85, 0.48}),
+	"ㅅ": lines([]float64{0.5, 0.08, 0.08, 0.92}, []float64{0.4, 0.3, 0.92, 0.92}),
+	"ㅇ": {rings: [][3]float64{{0.5, 0.5, 0.38}}},
+	"ㅈ": lines([]float64{0.12, 0.15, 0.88, 0.15}, []float64{0.5, 0.15, 0.1, 0.92}, []float64{0.44, 0.4, 0.92, 0.92}),
+	"ㅊ": lines([]float64{0.5, 0.02, 0.5, 0.18}, []float64{0.12, 0.3, 0.88, 0.3}, []float64{0.5, 0.3, 0.1, 0.94}, []float64{0.45, 0.52, 0.92, 0.94}),
+	"ㅋ": lines([]float64{0.1, 0.15, 0.85, 0.15, 0.85, 0.95}, []float64{0.1, 0.52, 0.85, 0.52}),
+	"ㅌ": lines([]float64{0.88, 0.12, 0.15, 0.12, 0.15, 0.86, 0.9, 0.86}, []float64{0.15, 0.49, 0.85, 0.49}),
+	"ㅍ": lines([]float64{0.08, 0.15, 0.92, 0.15}, []float64{0.32, 0.15, 0.32, 0.85}, []float64{0.68, 0.15, 0.68, 0.85}, []float64{0.08, 0.85, 0.92, 0.85}),
+	"ㅎ": {
+		lines: [][]float64{{0.5, 0.0, 0.5, 0.14}, {0.15, 0.24, 0.85, 0.24}},
+		rings: [][3]float64{{0.5, 0.64, 0.27}},
+	},
+
+	// Vowels drawn to the right of the initial, in a tall box.
+	"ㅏ": lines([]float64{0.35, 0, 0.35, 1}, []float64{0.35, 0.5, 0.9, 0.5}),
+	"ㅑ": lines([]float64{0.35, 0, 0.35, 1}, []float64{0.35, 0.38, 0.9, 0.38}, []float64{0.35, 0.62, 0.9, 0.62}),
+	"ㅓ": lines([]float64{0.65, 0, 0.65, 1}, []float64{0.1, 0.5, 0.65, 0.5}),
+	"ㅕ": lines([]float64{0.65, 0, 0.65, 1}, []float64{0.1, 0.38, 0.65, 0.38}, []float64{0.1, 0.62, 0.65, 0.62}),
+	"ㅐ": lines([]float64{0.3, 0, 0.3, 1}, []float64{0.3, 0.5, 0.62, 0.5}, []float64{0.85, 0, 0.85, 1}),
+	"ㅒ": lines([]float64{0.3, 0, 0.3, 1}, []float64{0.3, 0.38, 0.62, 0.38}, []float64{0.3, 0.62, 0.62, 0.62}, []float64{0.85, 0, 0.85, 1}),
+	"ㅔ": lines([]float64{0.05, 0.5, 0.4, 0.5}, []float64{0.4, 0, 0.4, 1}, []float64{0.85, 0, 0.85, 1}),
+	"ㅖ": lines([]float64{0.05, 0.38, 0.4, 0.38}, []float64{0.05, 0.62, 0.4, 0.62}, []float64{0.4, 0, 0.4, 1}, []float64{0.85, 0, 0.85, 1}),
+	"ㅣ": lines([]float64{0.5, 0, 0.5, 1}),
+
+	// Vowels drawn below the initial, in a wide box.
+	"ㅡ": lines([]float64{0.05, 0.6, 0.95, 0.6}),
+	"ㅗ": lines([]float64{0.5, 0.25, 0.5, 0.75}, []float64{0.05, 0.75, 0.95, 0.75}),
+	"ㅛ": lines([]float64{0.35, 0.25, 0.35, 0.75}, []float64{0.65, 0.25, 0.65, 0.75}, []float64{0.05, 0.75, 0.95, 0.75}),
+	"ㅜ": lines([]float64{0.05, 0.3, 0.95, 0.3}, []float64{0.5, 0.3, 0.5, 0.85}),
+	"ㅠ": lines([]float64{0.05, 0.3, 0.95, 0.3}, []float64{0.35, 0.3, 0.35, 0.85}, []float64{0.65, 0.3, 0.65, 0.85}),
+}
+
+// box is a rectangle in ems, relative to the top left corner of the syllable square.
+type box struct{ x0, y0, x1, y1 float64 }
+
+// syllableLayout places the jamo of a syllable the way Hangul fonts do:
+// the initial sits left of a vertical vowel and above a horizontal one, and a final takes the bottom row.
+func syllableLayout(below, right string, hasFinal bool) (initial, belowBox, rightBox, final box) {
+	final = box{0.14, 0.64, 0.86, 0.97}
+
+	switch {
+	case below == "":
+		initial, rightBox = box{0.06, 0.18, 0.56, 0.82}, box{0.56, 0.04, 0.94, 0.96}
+		if hasFinal {
+			initial, rightBox = box{0.06, 0.06, 0.56, 0.56}, box{0.56, 0.02, 0.94, 0.6}
+		}
+	case right == "":
+		initial, belowBox = box{0.2, 0.05, 0.8, 0.5}, box{0.04, 0.5, 0.96, 0.9}
+		if hasFinal {
+			initial, belowBox = box{0.22, 0.03, 0.78, 0.32}, box{0.04, 0.3, 0.96, 0.6}
+		}
+	default:
+		initial, belowBox, rightBox = box{0.06, 0.05, 0.56, 0.48}, box{0.04, 0.46, 0.72, 0.92}, box{0.7, 0.02, 0.96, 0.98}
+		if hasFinal {
+			initial, belowBox, rightBox = box{0.06, 0.03, 0.5, 0.3}, box{0.04, 0.3, 0.72, 0.6}, box{0.7, 0.02, 0.96, 0.6}
+		}
+	}
+	return initial, belowBox, rightBox, final
+}
+
+// hangulFace draws precomposed Hangul syllables from jamo strokes. The Go fonts cover Latin and Cyrillic
+// but not Hangul, and composing the syllables keeps the card free of a multi-megabyte CJK font.
+type hangulFace struct {
+	size    float64
+	metrics font.Metrics
+}
+
+func isHangul(r rune) bool {
+	return r >= hangulFirst && r <= hangulLast
+}
+
+func (f *hangulFace) Close() error { return nil }
+
+func (f *hangulFace) Metrics() font.Metrics { return f.metrics }
+
+func (f *hangulFace) Kern(r0, r1 rune) fixed.Int26_6 { return 0 }
+
+func (f *hangulFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	if !isHangul(r) {
+		return 0, false
+	}
+	return fixed.Int26_6(math.Round(f.size * 64)), true
+}
+
+// square returns the syllable square relative to the dot, in pixels.
+func (f *hangulFace) square() (left, top, side float64) {
+	return 0.05 * f.size, -0.8 * f.size, 0.9 * f.size
+}
+
+func (f *hangulFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	advance, ok := f.GlyphAdvance(r)
+	if !ok {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+	left, top, side := f.square()
+	bounds := fixed.Rectangle26_6{
+		Min: fixed.Point26_6{X: fixed.Int26_6(left * 64), Y: fixed.Int26_6(top * 64)},
+		Max: fixed.Point26_6{X: fixed.Int26_6((left + side) * 64), Y: fixed.Int26_6((top + side) * 64)},
+	}
+	return bounds, advance, true
+}
+
+func (f *hangulFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	advance, ok := f.GlyphAdvance(r)
+	if !ok {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+
+	left, top, side := f.square()
+	origin := image.Pt(dot.X.Round()+int(math.Floor(left)), dot.Y.Round()+int(math.Floor(top)))
+	size := int(math.Ceil(side)) + 1
+
+	index := int(r - hangulFirst)
+	medial := hangulMedials[index/finalCount%medialCount]
+	finalJamo := hangulFinals[index%finalCount]
+	initialBox, belowBox, rightBox, finalBox := syllableLayout(medial.below, medial.right, finalJamo != "")
+
+	glyph := &syllable{raster: vector.NewRasterizer(size, size), side: side, halfWidth: max(hangulStroke*f.size, 0.6)}
+	glyph.consonants(initialBox, hangulInitials[index/(medialCount*finalCount)])
+	glyph.jamo(belowBox, medial.below)
+	glyph.jamo(rightBox, medial.right)
+	glyph.consonants(finalBox, finalJamo)
+
+	mask := image.NewAlpha(image.Rect(0, 0, size, size))
+	glyph.raster.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+
+	return mask.Bounds().Add(origin), mask, image.Point{}, advance, true
+}
+
+type syllable struct {
+	raster    *vector.Rasterizer
+	side      float64
+	halfWidth float64
+}
+
+// consonants draws one consonant, or two side by side for double initials and final clusters.
+func (s *syllable) consonants(b box, text string) {
+	parts := []rune(text)
+	width := (b.x1 - b.x0) / float64(max(len(parts), 1))
+	for i, part := range parts {
+		x0 := b.x0 + float64(i)*width
+		s.jamo(box{x0, b.y0, x0 + width, b.y1}, string(part))
+	}
+}
+
+func (s *syllable) jamo(b box, name string) {
+	shape := jamoShapes[name]
+	point := func(x, y float64) (float64, float64) {
+		return (b.x0 + x*(b.x1-b.x0)) * s.side, (b.y0 + y*(b.y1-b.y0)) * s.side
+	}
+
+	for _, line := range shape.lines {
+		for i := 0; i+3 < len(line); i += 2 {
+			x0, y0 := point(line[i], line[i+1])
+			x1, y1 := point(line[i+2], line[i+3])
+			s.segment(x0, y0, x1, y1)
+		}
+	}
+	for _, ring := range shape.rings {
+		cx, cy := point(ring[0], ring[1])
+		s.ring(cx, cy, ring[2]*(b.x1-b.x0)*s.side, ring[2]*(b.y1-b.y0)*s.side)
+	}
+}
+
+// segment fills a rectangle around the line with square caps, so joined segments leave no notches.
+// Every shape winds the same way, which lets overlapping strokes add up instead of cancelling out.
+func (s *syllable) segment(x0, y0, x1, y1 float64) {
+	length := math.Hypot(x1-x0, y1-y0)
+	if length == 0 {
+		return
+	}
+	dx, dy := (x1-x0)/length*s.halfWidth, (y1-y0)/length*s.halfWidth
+
+	s.raster.MoveTo(float32(x0-dx-dy), float32(y0-dy+dx))
+	s.raster.LineTo(float32(x1+dx-dy), float32(y1+dy+dx))
+	s.raster.LineTo(float32(x1+dx+dy), float32(y1+dy-dx))
+	s.raster.LineTo(float32(x0-dx+dy), float32(y0-dy-dx))
+	s.raster.ClosePath()
+}
+
+// ring strokes an ellipse: the outer edge winds like segment and the inner edge the other way to leave a hole.
+func (s *syllable) ring(cx, cy, rx, ry float64) {
+	const steps = 32
+	for _, edge := range []struct{ offset, direction float64 }{{s.halfWidth, -1}, {-s.halfWidth, 1}} {
+		for i := 0; i <= steps; i++ {
+			angle := edge.direction * 2 * math.Pi * float64(i) / steps
+			x := float32(cx + (rx+edge.offset)*math.Cos(angle))
+			y := float32(cy + (ry+edge.offset)*math.Sin(angle))
+			if i == 0 {
+				s.raster.MoveTo(x, y)
+			} else {
+				s.raster.LineTo(x, y)
+			}
+		}
+		s.raster.ClosePath()
+	}
+}
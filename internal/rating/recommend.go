@@ -0,0 +1,201 @@
+package rating
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+const (
+	// historyWindow bounds how far back dish features are looked up for rated dishes.
+	historyWindow = 180 * 24 * time.Hour
+
+	// A recommendation needs enough evidence and a clearly positive predicted preference.
+	minSimilarity = 0.5
+	minPreference = 0.25
+)
+
+type DishHistory interface {
+	LoadDishesSince(date time.Time) ([]*menu.MenuItem, error)
+}
+
+type Recommendation struct {
+	Cafeteria menu.Cafeteria
+	DishName  string
+	// LikedDish is the rated dish the recommendation is most similar to.
+	LikedDish  string
+	Preference float64
+}
+
+// Recommender predicts which of today's dishes a rater will like from the dishes they rated before.
+// Dishes are compared by the category and ingredients the AI enrichment stored with the menu.
+type Recommender struct {
+	repo    *Repository
+	history DishHistory
+	clock   menu.Clock
+}
+
+func NewRecommender(repo *Repository, history DishHistory, clock menu.Clock) *Recommender {
+	if clock == nil {
+		clock = menu.NewKSTClock()
+	}
+	return &Recommender{
+		repo:    repo,
+		history: history,
+		clock:   clock,
+	}
+}
+
+// Recommend picks at most one dish per rater from today's menus. Raters without a confident match are omitted.
+func (r *Recommender) Recommend(raters []string, menus map[menu.Cafeteria]*menu.Menu) (map[string]Recommendation, error) {
+	recommendations := make(map[string]Recommendation)
+
+	ratings, err := r.repo.RatingsBy(raters)
+	if err != nil {
+		return nil, err
+	}
+	if len(ratings) == 0 {
+		return recommendations, nil
+	}
+
+	dishes, err := r.history.LoadDishesSince(r.clock.Now().Add(-historyWindow))
+	if err != nil {
+		return nil, fmt.Errorf("load dish history: %w", err)
+	}
+
+	catalogue := make(map[string]dishFeatures, len(dishes))
+	for _, dish := range dishes {
+		catalogue[dishKey(dish.Name)] = featuresOf(dish)
+	}
+
+	for rater, rated := range ratings {
+		if recommendation, ok := recommend(preferencesOf(rated, catalogue), menus); ok {
+			recommendations[rater] = recommendation
+		}
+	}
+
+	return recommendations, nil
+}
+
+type dishFeatures struct {
+	key  string
+	tags map[string]bool
+}
+
+func featuresOf(item *menu.MenuItem) dishFeatures {
+	features := dishFeatures{key: dishKey(item.Name), tags: make(map[string]bool)}
+	if item.Category != "" {
+		features.tags["category:"+strings.ToLower(strings.TrimSpace(item.Category))] = true
+	}
+	for _, ingredient := range item.Ingredients {
+		if ingredient = strings.ToLower(strings.TrimSpace(ingredient)); ingredient != "" {
+			features.tags[ingredient] = true
+		}
+	}
+	return features
+}
+
+// similarity is 1 for the same dish and the Jaccard index of the features otherwise.
+func similarity(a, b dishFeatures) float64 {
+	if a.key == b.key {
+		return 1
+	}
+
+	shared := 0
+	for tag := range a.tags {
+		if b.tags[tag] {
+			shared++
+		}
+	}
+	union := len(a.tags) + len(b.tags) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+type preference struct {
+	name     string
+	features dishFeatures
+	// weight maps the 1..5 score onto -1..1, so disliked dishes push similar ones down.
+	weight float64
+}
+
+// preferencesOf keeps the latest score per dish; ratings arrive oldest first.
+func preferencesOf(ratings []Rating, catalogue map[string]dishFeatures) []preference {
+	latest := make(map[string]preference)
+	var order []string
+
+	for _, rating := range ratings {
+		key := dishKey(rating.DishName)
+		features, ok := catalogue[key]
+		if !ok {
+			features = dishFeatures{key: key}
+		}
+
+		if _, seen := latest[key]; !seen {
+			order = append(order, key)
+		}
+		latest[key] = preference{
+			name:     rating.DishName,
+			features: features,
+			weight:   float64(rating.Score-3) / 2,
+		}
+	}
+
+	preferences := make([]preference, 0, len(order))
+	for _, key := range order {
+		preferences = append(preferences, latest[key])
+	}
+	return preferences
+}
+
+func recommend(preferences []preference, menus map[menu.Cafeteria]*menu.Menu) (Recommendation, bool) {
+	var (
+		best  Recommendation
+		found bool
+	)
+
+	for _, cafeteria := range []menu.Cafeteria{menu.PEONY, menu.AZILEA} {
+		cafeteriaMenu := menus[cafeteria]
+		if cafeteriaMenu == nil || len(cafeteriaMenu.Items) <= 1 {
+			continue
+		}
+
+		for _, item := range cafeteriaMenu.Items {
+			candidate := featuresOf(item)
+
+			var (
+				weighted, total, strongest float64
+				liked                      string
+			)
+			for _, p := range preferences {
+				sim := similarity(candidate, p.features)
+				weighted += sim * p.weight
+				total += sim
+				if sim*p.weight > strongest {
+					strongest = sim * p.weight
+					liked = p.name
+				}
+			}
+			if total < minSimilarity || liked == "" {
+				continue
+			}
+
+			score := weighted / total
+			if score >= minPreference && (!found || score > best.Preference) {
+				best = Recommendation{
+					Cafeteria:  cafeteria,
+					DishName:   item.Name,
+					LikedDish:  liked,
+					Preference: score,
+				}
+				found = true
+			}
+		}
+	}
+
+	return best, found
+}
@@ -0,0 +1,90 @@
+package rating
+
+import (
+	"testing"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+type stubHistory []*menu.MenuItem
+
+func (h stubHistory) LoadDishesSince(date time.Time) ([]*menu.MenuItem, error) {
+	return h, nil
+}
+
+func TestSimilarity(t *testing.T) {
+	kimchiStew := featuresOf(&menu.MenuItem{Name: "김치찌개", Category: "Суп", Ingredients: []string{"кимчи", "свинина", "тофу"}})
+
+	tests := []struct {
+		name string
+		dish *menu.MenuItem
+		want float64
+	}{
+		{name: "same dish", dish: &menu.MenuItem{Name: " 김치찌개 "}, want: 1},
+		{name: "shared features", dish: &menu.MenuItem{Name: "된장찌개", Category: "суп", Ingredients: []string{"тофу", "кабачок", "свинина"}}, want: 0.6},
+		{name: "nothing in common", dish: &menu.MenuItem{Name: "식혜", Category: "напиток", Ingredients: []string{"рис"}}, want: 0},
+		{name: "no features", dish: &menu.MenuItem{Name: "빵"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := similarity(kimchiStew, featuresOf(tt.dish)); got != tt.want {
+				t.Errorf("similarity = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	service := newTestService(t)
+
+	history := stubHistory{
+		{Name: "제육볶음", Category: "горячее", Ingredients: []string{"свинина", "острый перец", "лук"}},
+		{Name: "생선까스", Category: "горячее", Ingredients: []string{"рыба", "панировка"}},
+		{Name: "비빔밥", Category: "рис", Ingredients: []string{"рис", "овощи", "яйцо"}},
+	}
+	today := map[menu.Cafeteria]*menu.Menu{
+		menu.PEONY: menu.NewMenu([]*menu.MenuItem{
+			{Name: "돈까스", Category: "горячее", Ingredients: []string{"свинина", "панировка", "лук"}},
+			{Name: "미역국", Category: "суп", Ingredients: []string{"водоросли"}},
+		}, nil),
+		menu.AZILEA: menu.NewMenu([]*menu.MenuItem{
+			{Name: "비빔밥", Category: "рис", Ingredients: []string{"рис", "овощи", "яйцо"}},
+			{Name: "단무지"},
+		}, nil),
+	}
+
+	ratings := []Rating{
+		{DishName: "제육볶음", Rater: "tg:1", Score: 5},
+		{DishName: "생선까스", Rater: "tg:1", Score: 2},
+		{DishName: "비빔밥", Rater: "tg:2", Score: 1},
+		{DishName: "비빔밥", Rater: "tg:3", Score: 4},
+	}
+	for _, rating := range ratings {
+		rating.Cafeteria = menu.PEONY
+		rating.MenuDate = testNow.AddDate(0, 0, -7)
+		if err := service.repo.Save(rating); err != nil {
+			t.Fatalf("save rating: %v", err)
+		}
+	}
+
+	recommender := NewRecommender(service.repo, history, fixedClock{now: testNow})
+	recommendations, err := recommender.Recommend([]string{"tg:1", "tg:2", "tg:3", "tg:4"}, today)
+	if err != nil {
+		t.Fatalf("recommend: %v", err)
+	}
+
+	if got, ok := recommendations["tg:1"]; !ok || got.DishName != "돈까스" || got.Cafeteria != menu.PEONY || got.LikedDish != "제육볶음" {
+		t.Errorf("tg:1 recommendation = %+v, want 돈까스 because of 제육볶음", got)
+	}
+	if got, ok := recommendations["tg:2"]; ok {
+		t.Errorf("tg:2 disliked the only similar dish but got %+v", got)
+	}
+	if got := recommendations["tg:3"]; got.DishName != "비빔밥" || got.Cafeteria != menu.AZILEA {
+		t.Errorf("tg:3 recommendation = %+v, want 비빔밥 again", got)
+	}
+	if _, ok := recommendations["tg:4"]; ok {
+		t.Errorf("rater without ratings got a recommendation")
+	}
+}
@@ -0,0 +1,176 @@
+package rating
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+type Repository struct {
+	db *database.Database
+}
+
+func NewRepository(db *database.Database) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+type Rating struct {
+	DishName  string
+	Cafeteria menu.Cafeteria
+	Rater     string
+	MenuDate  time.Time
+	Score     int
+}
+
+type DishScore struct {
+	DishName  string
+	Cafeteria menu.Cafeteria
+	menu.DishRating
+}
+
+// Save records a rating; rating the same dish again on the same day replaces the earlier score.
+func (r *Repository) Save(rating Rating) error {
+	_, err := r.db.Conn.Exec(`
+		INSERT INTO dish_ratings (dish, dish_name, cafeteria, rater, menu_date, score)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(dish, rater, menu_date) DO UPDATE SET
+			score = excluded.score,
+			updated_at = CURRENT_TIMESTAMP
+	`, dishKey(rating.DishName), rating.DishName, string(rating.Cafeteria), rating.Rater,
+		rating.MenuDate.Format("2006-01-02"), rating.Score)
+	if err != nil {
+		return fmt.Errorf("save rating for %q: %w", rating.DishName, err)
+	}
+	return nil
+}
+
+// Summaries returns the aggregate rating of every listed dish that has been rated, keyed by dish name.
+func (r *Repository) Summaries(dishNames []string) (map[string]menu.DishRating, error) {
+	summaries := make(map[string]menu.DishRating, len(dishNames))
+	if len(dishNames) == 0 {
+		return summaries, nil
+	}
+
+	names := make(map[string]string, len(dishNames))
+	args := make([]any, 0, len(dishNames))
+	for _, name := range dishNames {
+		key := dishKey(name)
+		if _, seen := names[key]; !seen {
+			args = append(args, key)
+		}
+		names[key] = name
+	}
+
+	rows, err := r.db.Conn.Query(`
+		SELECT dish, AVG(score), COUNT(*) FROM dish_ratings
+		WHERE dish IN (?`+strings.Repeat(", ?", len(args)-1)+`)
+		GROUP BY dish
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query rating summaries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			key     string
+			summary menu.DishRating
+		)
+		if err := rows.Scan(&key, &summary.Average, &summary.Count); err != nil {
+			return nil, fmt.Errorf("scan rating summary: %w", err)
+		}
+		summaries[names[key]] = summary
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rating summaries: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// RatingsBy returns every rating given by the listed raters, grouped by rater.
+func (r *Repository) RatingsBy(raters []string) (map[string][]Rating, error) {
+	ratings := make(map[string][]Rating, len(raters))
+	if len(raters) == 0 {
+		return ratings, nil
+	}
+
+	args := make([]any, len(raters))
+	for i, rater := range raters {
+		args[i] = rater
+	}
+
+	rows, err := r.db.Conn.Query(`
+		SELECT dish_name, cafeteria, rater, menu_date, score FROM dish_ratings
+		WHERE rater IN (?`+strings.Repeat(", ?", len(args)-1)+`)
+		ORDER BY menu_date
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query ratings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			rating    Rating
+			cafeteria string
+		)
+		if err := rows.Scan(&rating.DishName, &cafeteria, &rating.Rater, &rating.MenuDate, &rating.Score); err != nil {
+			return nil, fmt.Errorf("scan rating: %w", err)
+		}
+		rating.Cafeteria = menu.Cafeteria(cafeteria)
+		ratings[rating.Rater] = append(ratings[rating.Rater], rating)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate ratings: %w", err)
+	}
+
+	return ratings, nil
+}
+
+// TopDishes ranks dishes served between from and to by average score, ignoring dishes with fewer than minVotes ratings.
+func (r *Repository) TopDishes(from, to time.Time, minVotes, limit int) ([]DishScore, error) {
+	rows, err := r.db.Conn.Query(`
+		SELECT MAX(dish_name), MAX(cafeteria), AVG(score), COUNT(*) FROM dish_ratings
+		WHERE menu_date BETWEEN ? AND ?
+		GROUP BY dish
+		HAVING COUNT(*) >= ?
+		ORDER BY AVG(score) DESC, COUNT(*) DESC
+		LIMIT ?
+	`, from.Format("2006-01-02"), to.Format("2006-01-02"), minVotes, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query top dishes: %w", err)
+	}
+	defer rows.Close()
+
+	var dishes []DishScore
+	for rows.Next() {
+		var (
+			dish      DishScore
+			cafeteria string
+		)
+		if err := rows.Scan(&dish.DishName, &cafeteria, &dish.Average, &dish.Count); err != nil {
+			return nil, fmt.Errorf("scan top dish: %w", err)
+		}
+		dish.Cafeteria = menu.Cafeteria(cafeteria)
+		dishes = append(dishes, dish)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate top dishes: %w", err)
+	}
+
+	return dishes, nil
+}
+
+// dishKey lets the same dish match across days despite spacing or case differences in the menu.
+func dishKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
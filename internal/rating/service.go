@@ -0,0 +1,127 @@
+package rating
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+const (
+	ScoreDislike = 1
+	ScoreLike    = 5
+
+	topDishesMinVotes = 2
+)
+
+var (
+	ErrInvalidScore = errors.New("score must be between 1 and 5")
+	ErrUnknownDish  = errors.New("dish is not on the menu")
+)
+
+// MenuSource looks up stored menus; rating a dish never fetches the cafeteria page.
+type MenuSource interface {
+	GetStoredMenuForDate(cafeteria menu.Cafeteria, date time.Time) (*menu.Menu, error)
+}
+
+type Service struct {
+	repo  *Repository
+	menus MenuSource
+	clock menu.Clock
+}
+
+func NewService(repo *Repository, menus MenuSource, clock menu.Clock) *Service {
+	if clock == nil {
+		clock = menu.NewKSTClock()
+	}
+	return &Service{
+		repo:  repo,
+		menus: menus,
+		clock: clock,
+	}
+}
+
+// Rate stores a score for a dish served on date and returns the dish's updated aggregate.
+// Only dishes that are actually on that day's menu can be rated.
+func (s *Service) Rate(ctx context.Context, cafeteria menu.Cafeteria, date time.Time, dishName, rater string, score int) (menu.DishRating, error) {
+	if score < 1 || score > 5 {
+		return menu.DishRating{}, ErrInvalidScore
+	}
+
+	cafeteriaMenu, err := s.menus.GetStoredMenuForDate(cafeteria, date)
+	if err != nil {
+		return menu.DishRating{}, fmt.Errorf("get %s menu: %w", cafeteria, err)
+	}
+	if !hasDish(cafeteriaMenu, dishName) {
+		return menu.DishRating{}, ErrUnknownDish
+	}
+
+	err = s.repo.Save(Rating{
+		DishName:  dishName,
+		Cafeteria: cafeteria,
+		Rater:     rater,
+		MenuDate:  date,
+		Score:     score,
+	})
+	if err != nil {
+		return menu.DishRating{}, err
+	}
+
+	summaries, err := s.repo.Summaries([]string{dishName})
+	if err != nil {
+		return menu.DishRating{}, err
+	}
+	return summaries[dishName], nil
+}
+
+// WithRatings returns a copy of cafeteriaMenu with aggregate ratings attached to its dishes.
+// Menus are shared through the menu cache, so the original is left untouched.
+func (s *Service) WithRatings(cafeteriaMenu *menu.Menu) (*menu.Menu, error) {
+	if cafeteriaMenu == nil {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(cafeteriaMenu.Items))
+	for _, item := range cafeteriaMenu.Items {
+		names = append(names, item.Name)
+	}
+
+	summaries, err := s.repo.Summaries(names)
+	if err != nil {
+		return nil, err
+	}
+
+	rated := &menu.Menu{
+		Items: make([]*menu.MenuItem, len(cafeteriaMenu.Items)),
+		Time:  cafeteriaMenu.Time,
+	}
+	for i, item := range cafeteriaMenu.Items {
+		ratedItem := *item
+		if summary, ok := summaries[item.Name]; ok {
+			ratedItem.Rating = &summary
+		}
+		rated.Items[i] = &ratedItem
+	}
+	return rated, nil
+}
+
+// TopDishesThisMonth ranks the dishes rated since the start of the current month.
+func (s *Service) TopDishesThisMonth(limit int) ([]DishScore, error) {
+	now := s.clock.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return s.repo.TopDishes(monthStart, now, topDishesMinVotes, limit)
+}
+
+func hasDish(cafeteriaMenu *menu.Menu, dishName string) bool {
+	if cafeteriaMenu == nil {
+		return false
+	}
+	for _, item := range cafeteriaMenu.Items {
+		if dishKey(item.Name) == dishKey(dishName) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package rating
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database/databasetest"
+	"github.com/artyom-kalman/kbu-daily-menu/internal/menu"
+)
+
+var testNow = time.Date(2025, time.October, 22, 11, 30, 0, 0, time.FixedZone("KST", 9*60*60))
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+// After fires at once: a fixed clock never moves, so there is nothing to wait for.
+func (c fixedClock) After(time.Duration) <-chan time.Time {
+	fired := make(chan time.Time, 1)
+	fired <- c.now
+	return fired
+}
+
+type stubMenus map[menu.Cafeteria]*menu.Menu
+
+func (s stubMenus) GetStoredMenuForDate(cafeteria menu.Cafeteria, date time.Time) (*menu.Menu, error) {
+	return s[cafeteria], nil
+}
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+
+	db := databasetest.New(t)
+
+	menus := stubMenus{
+		menu.PEONY:  menu.NewMenuFromDishes([]string{"김치찌개", "돈까스"}, nil),
+		menu.AZILEA: menu.NewMenuFromDishes([]string{"비빔밥", "된장국"}, nil),
+	}
+	return NewService(NewRepository(db), menus, fixedClock{now: testNow})
+}
+
+func TestRate(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := service.Rate(ctx, menu.PEONY, testNow, "돈까스", "tg:1", ScoreLike); err != nil {
+		t.Fatalf("rate: %v", err)
+	}
+	summary, err := service.Rate(ctx, menu.PEONY, testNow, "돈까스", "web:abc", 2)
+	if err != nil {
+		t.Fatalf("rate: %v", err)
+	}
+	if summary.Count != 2 || summary.Average != 3.5 {
+		t.Fatalf("summary = %+v, want average 3.5 over 2 votes", summary)
+	}
+
+	// Voting again on the same day replaces the earlier score.
+	summary, err = service.Rate(ctx, menu.PEONY, testNow, "돈까스", "tg:1", ScoreDislike)
+	if err != nil {
+		t.Fatalf("rate again: %v", err)
+	}
+	if summary.Count != 2 || summary.Average != 1.5 {
+		t.Fatalf("summary after revote = %+v, want average 1.5 over 2 votes", summary)
+	}
+}
+
+func TestRateRejectsInvalidInput(t *testing.T) {
+	service := newTestService(t)
+
+	tests := []struct {
+		name      string
+		cafeteria menu.Cafeteria
+		dish      string
+		score     int
+		wantErr   error
+	}{
+		{name: "score too low", cafeteria: menu.PEONY, dish: "돈까스", score: 0, wantErr: ErrInvalidScore},
+		{name: "score too high", cafeteria: menu.PEONY, dish: "돈까스", score: 6, wantErr: ErrInvalidScore},
+		{name: "dish from other cafeteria", cafeteria: menu.PEONY, dish: "비빔밥", score: 5, wantErr: ErrUnknownDish},
+		{name: "unknown dish", cafeteria: menu.AZILEA, dish: "pizza", score: 5, wantErr: ErrUnknownDish},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Rate(context.Background(), tt.cafeteria, testNow, tt.dish, "tg:1", tt.score)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithRatingsLeavesOriginalUntouched(t *testing.T) {
+	service := newTestService(t)
+	if _, err := service.Rate(context.Background(), menu.AZILEA, testNow, "비빔밥", "tg:1", 4); err != nil {
+		t.Fatalf("rate: %v", err)
+	}
+
+	original := menu.NewMenuFromDishes([]string{"비빔밥", "된장국"}, nil)
+	rated, err := service.WithRatings(original)
+	if err != nil {
+		t.Fatalf("with ratings: %v", err)
+	}
+
+	if got := rated.Items[0].Rating; got == nil || got.Count != 1 || got.Average != 4 {
+		t.Fatalf("rating = %+v, want 4.0 over 1 vote", got)
+	}
+	if rated.Items[1].Rating != nil {
+		t.Fatalf("unrated dish got rating %+v", rated.Items[1].Rating)
+	}
+	if original.Items[0].Rating != nil {
+		t.Fatalf("original menu was modified")
+	}
+}
+
+func TestTopDishesThisMonth(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	votes := []struct {
+		cafeteria menu.Cafeteria
+		dish      string
+		rater     string
+		score     int
+		date      time.Time
+	}{
+		{menu.PEONY, "돈까스", "tg:1", 5, testNow},
+		{menu.PEONY, "돈까스", "tg:2", 4, testNow},
+		{menu.AZILEA, "된장국", "tg:1", 3, testNow},
+		{menu.AZILEA, "된장국", "tg:2", 3, testNow.AddDate(0, 0, -1)},
+		// A single vote is not enough to make the list.
+		{menu.AZILEA, "비빔밥", "tg:1", 5, testNow},
+		// Last month's votes are ignored.
+		{menu.PEONY, "김치찌개", "tg:1", 5, testNow.AddDate(0, -1, 0)},
+		{menu.PEONY, "김치찌개", "tg:2", 5, testNow.AddDate(0, -1, 0)},
+	}
+	for _, vote := range votes {
+		if _, err := service.Rate(ctx, vote.cafeteria, vote.date, vote.dish, vote.rater, vote.score); err != nil {
+			t.Fatalf("rate %s: %v", vote.dish, err)
+		}
+	}
+
+	dishes, err := service.TopDishesThisMonth(10)
+	if err != nil {
+		t.Fatalf("top dishes: %v", err)
+	}
+
+	if len(dishes) != 2 {
+		t.Fatalf("got %d dishes, want 2: %+v", len(dishes), dishes)
+	}
+	if dishes[0].DishName != "돈까스" || dishes[0].Cafeteria != menu.PEONY || dishes[0].Average != 4.5 {
+		t.Errorf("first dish = %+v, want 돈까스 from peony with 4.5", dishes[0])
+	}
+	if dishes[1].DishName != "된장국" || dishes[1].Count != 2 {
+		t.Errorf("second dish = %+v, want 된장국 with 2 votes", dishes[1])
+	}
+}
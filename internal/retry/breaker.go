@@ -0,0 +1,110 @@
+package retry
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+const (
+	defaultFailureThreshold = 3
+	defaultCooldown         = 10 * time.Minute
+)
+
+// Breaker opens after consecutive transient failures and rejects calls until the cooldown passes.
+// Then it lets a single trial call through: success closes it, another failure opens it again.
+// Client and parse errors do not count, since the host answered.
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+func NewBreaker(name string, threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   = make(map[string]*Breaker)
+)
+
+// BreakerFor returns the shared breaker of a host, so every client talking to it sees the same state.
+func BreakerFor(host string) *Breaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	breaker, ok := breakers[host]
+	if !ok {
+		breaker = NewBreaker(host, defaultFailureThreshold, defaultCooldown)
+		breakers[host] = breaker
+	}
+	return breaker
+}
+
+// Allow returns ErrCircuitOpen while the breaker rejects calls. A nil breaker allows everything.
+func (b *Breaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+	if b.trial || b.now().Sub(b.openedAt) < b.cooldown {
+		return ErrCircuitOpen
+	}
+
+	b.trial = true
+	return nil
+}
+
+// Record updates the breaker with the outcome of an allowed call.
+func (b *Breaker) Record(err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasTrial := b.trial
+	b.trial = false
+
+	class := Classify(err)
+	switch {
+	case err == nil, !class.Retryable() && class != Canceled:
+		if b.failures >= b.threshold {
+			slog.Info("Circuit breaker closed", "host", b.name)
+		}
+		b.failures = 0
+	case class == Canceled:
+		// The caller gave up; that says nothing about the host.
+	default:
+		b.failures++
+		if b.failures == b.threshold || wasTrial {
+			b.openedAt = b.now()
+			slog.Warn("Circuit breaker opened",
+				"host", b.name,
+				"failures", b.failures,
+				"cooldown", b.cooldown.String())
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2025, time.October, 22, 6, 0, 0, 0, time.UTC)
+	breaker := NewBreaker("menu.example.com", 2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	outage := HTTPStatus(503, errors.New("503"))
+
+	// Answers that are not transient failures show the host is up.
+	breaker.Record(outage)
+	breaker.Record(HTTPStatus(404, errors.New("404")))
+	breaker.Record(outage)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() after non-consecutive failures = %v", err)
+	}
+
+	breaker.Record(outage)
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow() after 2 failures = %v, want ErrCircuitOpen", err)
+	}
+
+	now = now.Add(time.Minute)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("trial Allow() = %v", err)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second Allow() during the trial = %v, want ErrCircuitOpen", err)
+	}
+
+	// A failed trial starts a new cooldown.
+	breaker.Record(outage)
+	now = now.Add(30 * time.Second)
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow() after a failed trial = %v, want ErrCircuitOpen", err)
+	}
+
+	now = now.Add(time.Minute)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("second trial Allow() = %v", err)
+	}
+	breaker.Record(nil)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() after a successful trial = %v", err)
+	}
+}
+
+func TestDoStopsAtOpenBreaker(t *testing.T) {
+	breaker := NewBreaker("ai.example.com", 2, time.Hour)
+
+	calls := 0
+	err := Do(context.Background(), Policy{Name: "test", Attempts: 5, BaseDelay: time.Millisecond, Breaker: breaker}, func(ctx context.Context) error {
+		calls++
+		return Mark(Network, errors.New("connection refused"))
+	})
+
+	if calls != 2 || !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Do() made %d calls and returned %v; want 2 calls and ErrCircuitOpen", calls, err)
+	}
+	if BreakerFor("ai.example.com") != BreakerFor("ai.example.com") {
+		t.Error("BreakerFor() returned different breakers for one host")
+	}
+}
@@ -0,0 +1,180 @@
+// Package retry runs operations against flaky upstreams: the cafeteria sites and the AI API.
+// Errors are classified so that only transient failures are retried, delays grow exponentially
+// with jitter, and a per-host circuit breaker stops hammering a host that keeps failing.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"time"
+)
+
+type Class int
+
+const (
+	// Unknown errors are retried, since most unexpected failures talking to a remote host are transient.
+	Unknown Class = iota
+	Network
+	// Server covers 5xx responses and 429 Too Many Requests.
+	Server
+	// Client covers the remaining 4xx responses; repeating the same request will not help.
+	Client
+	// Parse means the response arrived but could not be understood, e.g. the page layout changed.
+	Parse
+	// AI means the AI API answered but reported a failure or returned an unusable envelope.
+	AI
+	// Canceled means the context ended or the circuit breaker rejected the call.
+	Canceled
+)
+
+func (c Class) String() string {
+	switch c {
+	case Network:
+		return "network"
+	case Server:
+		return "server"
+	case Client:
+		return "client"
+	case Parse:
+		return "parse"
+	case AI:
+		return "ai"
+	case Canceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// Retryable reports whether repeating the operation may succeed.
+func (c Class) Retryable() bool {
+	switch c {
+	case Client, Parse, Canceled:
+		return false
+	default:
+		return true
+	}
+}
+
+// Error attaches a class to an error; StatusCode is set for HTTP responses.
+type Error struct {
+	Class      Class
+	StatusCode int
+	Err        error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Mark classifies err, keeping its message; a nil err stays nil.
+func Mark(class Class, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Class: class, Err: err}
+}
+
+// HTTPStatus classifies an unexpected response status.
+func HTTPStatus(statusCode int, err error) error {
+	class := Client
+	if statusCode >= 500 || statusCode == 429 {
+		class = Server
+	}
+	return &Error{Class: class, StatusCode: statusCode, Err: err}
+}
+
+// Classify returns the class of err, recognising context and network errors that were not marked.
+func Classify(err error) Class {
+	var classified *Error
+	switch {
+	case errors.As(err, &classified):
+		return classified.Class
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded), errors.Is(err, ErrCircuitOpen):
+		return Canceled
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return Network
+	}
+	return Unknown
+}
+
+type Policy struct {
+	// Name identifies the operation in logs.
+	Name      string
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Breaker is optional; when set, every attempt goes through it.
+	Breaker *Breaker
+}
+
+// Delay returns the pause before the given retry, counting from 1: the base delay doubles per retry
+// up to MaxDelay, and half of it is random so that clients failing together do not retry together.
+func (p Policy) Delay(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// Do runs op until it succeeds, fails with an error that is not retryable, or runs out of attempts.
+func Do(ctx context.Context, policy Policy, op func(ctx context.Context) error) error {
+	attempts := max(policy.Attempts, 1)
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			delay := policy.Delay(attempt - 1)
+			slog.Warn("Retrying after error",
+				"operation", policy.Name,
+				"attempt", attempt,
+				"max_attempts", attempts,
+				"class", Classify(err).String(),
+				"delay", delay.String())
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		if err = policy.Breaker.Allow(); err != nil {
+			return err
+		}
+
+		err = op(ctx)
+		policy.Breaker.Record(err)
+		if err == nil {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if !Classify(err).Retryable() {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%s failed after %d attempts: %w", policy.Name, attempts, err)
+}
@@ -0,0 +1,98 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		want      Class
+		retryable bool
+	}{
+		{name: "marked parse", err: fmt.Errorf("parse menu: %w", Mark(Parse, errors.New("no foodList"))), want: Parse},
+		{name: "service unavailable", err: HTTPStatus(503, errors.New("status 503")), want: Server, retryable: true},
+		{name: "rate limited", err: HTTPStatus(429, errors.New("status 429")), want: Server, retryable: true},
+		{name: "not found", err: HTTPStatus(404, errors.New("status 404")), want: Client},
+		{name: "unmarked network", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: Network, retryable: true},
+		{name: "ai", err: Mark(AI, errors.New("AI request failed")), want: AI, retryable: true},
+		{name: "deadline", err: fmt.Errorf("fetch: %w", context.DeadlineExceeded), want: Canceled},
+		{name: "circuit open", err: ErrCircuitOpen, want: Canceled},
+		{name: "unknown", err: errors.New("boom"), want: Unknown, retryable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Classify(tt.err)
+			if got != tt.want || got.Retryable() != tt.retryable {
+				t.Errorf("Classify() = %s (retryable %v), want %s (retryable %v)", got, got.Retryable(), tt.want, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestPolicyDelay(t *testing.T) {
+	policy := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		retry    int
+		min, max time.Duration
+	}{
+		{retry: 1, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{retry: 2, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+		{retry: 3, min: 200 * time.Millisecond, max: 400 * time.Millisecond},
+		{retry: 10, min: 500 * time.Millisecond, max: time.Second},
+	}
+
+	for _, tt := range tests {
+		for range 50 {
+			if got := policy.Delay(tt.retry); got < tt.min || got > tt.max {
+				t.Fatalf("Delay(%d) = %v, want within [%v, %v]", tt.retry, got, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestDo(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "succeeds after transient failures", errs: []error{HTTPStatus(502, errors.New("502")), Mark(Network, errors.New("reset")), nil}, wantCalls: 3},
+		{name: "stops on parse error", errs: []error{Mark(Parse, errors.New("layout changed"))}, wantCalls: 1, wantErr: true},
+		{name: "gives up after attempts", errs: []error{errors.New("a"), errors.New("b"), errors.New("c"), nil}, wantCalls: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Do(context.Background(), Policy{Name: "test", Attempts: 3, BaseDelay: time.Millisecond}, func(ctx context.Context) error {
+				err := tt.errs[calls]
+				calls++
+				return err
+			})
+
+			if calls != tt.wantCalls || (err != nil) != tt.wantErr {
+				t.Errorf("Do() made %d calls and returned %v; want %d calls, error %v", calls, err, tt.wantCalls, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDoKeepsClassOfLastError(t *testing.T) {
+	err := Do(context.Background(), Policy{Name: "test", Attempts: 2, BaseDelay: time.Millisecond}, func(ctx context.Context) error {
+		return HTTPStatus(503, errors.New("503"))
+	})
+
+	if Classify(err) != Server {
+		t.Errorf("Classify(Do()) = %s, want server", Classify(err))
+	}
+}
@@ -0,0 +1,87 @@
+// Package snapshot stores the raw cafeteria pages the fetcher downloaded, one row per distinct body,
+// so parsing can be replayed against what the sites actually served.
+package snapshot
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database"
+)
+
+type Repository struct {
+	db *database.Database
+}
+
+func NewRepository(db *database.Database) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+type Snapshot struct {
+	ID        int64
+	URL       string
+	Hash      string
+	Body      string
+	FetchedAt time.Time
+}
+
+// SaveSnapshot stores a page body unless the same body was already stored for the URL;
+// FetchedAt then stays the time it was first seen.
+func (r *Repository) SaveSnapshot(url, hash, body string, fetchedAt time.Time) error {
+	_, err := r.db.Conn.Exec(`
+		INSERT INTO page_snapshots (url, content_hash, body, fetched_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(url, content_hash) DO NOTHING
+	`, url, hash, body, fetchedAt)
+	if err != nil {
+		return fmt.Errorf("save snapshot of %s: %w", url, err)
+	}
+	return nil
+}
+
+// List returns snapshots without their bodies, newest first. An empty url lists every page.
+func (r *Repository) List(url string, limit int) ([]Snapshot, error) {
+	rows, err := r.db.Conn.Query(`
+		SELECT id, url, content_hash, fetched_at FROM page_snapshots
+		WHERE ? = '' OR url = ?
+		ORDER BY fetched_at DESC, id DESC
+		LIMIT ?
+	`, url, url, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []Snapshot
+	for rows.Next() {
+		var snapshot Snapshot
+		if err := rows.Scan(&snapshot.ID, &snapshot.URL, &snapshot.Hash, &snapshot.FetchedAt); err != nil {
+			return nil, fmt.Errorf("scan snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// Get returns a snapshot with its body, or nil when there is no snapshot with the ID.
+func (r *Repository) Get(id int64) (*Snapshot, error) {
+	var snapshot Snapshot
+	err := r.db.Conn.QueryRow(`
+		SELECT id, url, content_hash, body, fetched_at FROM page_snapshots WHERE id = ?
+	`, id).Scan(&snapshot.ID, &snapshot.URL, &snapshot.Hash, &snapshot.Body, &snapshot.FetchedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get snapshot %d: %w", id, err)
+	}
+	return &snapshot, nil
+}
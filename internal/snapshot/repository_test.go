@@ -0,0 +1,55 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/artyom-kalman/kbu-daily-menu/internal/database/databasetest"
+)
+
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+
+	return NewRepository(databasetest.New(t))
+}
+
+func TestRepositoryKeepsDistinctPages(t *testing.T) {
+	repo := newTestRepository(t)
+	monday := time.Date(2025, time.October, 20, 6, 0, 0, 0, time.UTC)
+
+	saves := []struct {
+		url, hash, body string
+		at              time.Time
+	}{
+		{url: "https://peony.example.com", hash: "a", body: "v1", at: monday},
+		{url: "https://peony.example.com", hash: "a", body: "v1", at: monday.Add(time.Hour)},
+		{url: "https://peony.example.com", hash: "b", body: "v2", at: monday.Add(24 * time.Hour)},
+		{url: "https://azilea.example.com", hash: "a", body: "v1", at: monday},
+	}
+	for _, save := range saves {
+		if err := repo.SaveSnapshot(save.url, save.hash, save.body, save.at); err != nil {
+			t.Fatalf("SaveSnapshot() error = %v", err)
+		}
+	}
+
+	peony, err := repo.List("https://peony.example.com", 10)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(peony) != 2 || peony[0].Hash != "b" || !peony[1].FetchedAt.Equal(monday) {
+		t.Fatalf("List() = %+v, want both Peony pages newest first, keeping the first fetch time", peony)
+	}
+
+	all, err := repo.List("", 10)
+	if err != nil || len(all) != 3 {
+		t.Errorf("List(all) = %d snapshots, %v; want 3", len(all), err)
+	}
+
+	snapshot, err := repo.Get(peony[0].ID)
+	if err != nil || snapshot == nil || snapshot.Body != "v2" {
+		t.Errorf("Get() = %+v, %v; want the v2 body", snapshot, err)
+	}
+	if missing, err := repo.Get(999); err != nil || missing != nil {
+		t.Errorf("Get(999) = %+v, %v; want nil", missing, err)
+	}
+}
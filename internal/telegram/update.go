@@ -0,0 +1,53 @@
+// Package telegram holds Telegram Bot API types that tgbotapi v5.5.1 does not cover.
+package telegram
+
+import (
+	"encoding/json"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Update is a Telegram update together with the forum topic fields
+// that tgbotapi v5.5.1 predates and therefore drops while decoding.
+type Update struct {
+	tgbotapi.Update
+
+	// MessageThreadID is the forum topic of the message or of the message carrying the pressed button.
+	MessageThreadID int
+}
+
+type topicFields struct {
+	MessageThreadID int  `json:"message_thread_id"`
+	IsTopicMessage  bool `json:"is_topic_message"`
+}
+
+func (u *Update) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &u.Update); err != nil {
+		return err
+	}
+
+	var raw struct {
+		Message       *topicFields `json:"message"`
+		CallbackQuery *struct {
+			Message *topicFields `json:"message"`
+		} `json:"callback_query"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var topic *topicFields
+	switch {
+	case raw.Message != nil:
+		topic = raw.Message
+	case raw.CallbackQuery != nil:
+		topic = raw.CallbackQuery.Message
+	}
+
+	// Replies in regular supergroups carry a thread id too; only forum topics are posting targets.
+	if topic != nil && topic.IsTopicMessage {
+		u.MessageThreadID = topic.MessageThreadID
+	}
+
+	return nil
+}
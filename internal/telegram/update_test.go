@@ -0,0 +1,45 @@
+package telegram
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUpdateDecodesForumTopic(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    int
+	}{
+		{
+			name:    "topic message",
+			payload: `{"update_id":1,"message":{"message_id":5,"message_thread_id":7,"is_topic_message":true,"chat":{"id":-100,"type":"supergroup"},"text":"/today"}}`,
+			want:    7,
+		},
+		{
+			name:    "reply outside a forum",
+			payload: `{"update_id":2,"message":{"message_id":5,"message_thread_id":3,"chat":{"id":-100,"type":"supergroup"},"text":"hi"}}`,
+			want:    0,
+		},
+		{
+			name:    "button in a topic",
+			payload: `{"update_id":3,"callback_query":{"id":"q","data":"subscribe","message":{"message_id":9,"message_thread_id":11,"is_topic_message":true,"chat":{"id":-100,"type":"supergroup"}}}}`,
+			want:    11,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var update Update
+			if err := json.Unmarshal([]byte(tt.payload), &update); err != nil {
+				t.Fatalf("Unmarshal() error: %v", err)
+			}
+			if update.MessageThreadID != tt.want {
+				t.Errorf("MessageThreadID = %d, want %d", update.MessageThreadID, tt.want)
+			}
+			if update.UpdateID == 0 {
+				t.Error("embedded tgbotapi.Update was not decoded")
+			}
+		})
+	}
+}
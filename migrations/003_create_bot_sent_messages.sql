@@ -0,0 +1,8 @@
+CREATE TABLE bot_sent_messages (
+    chat_id INTEGER NOT NULL,
+    menu_date DATE NOT NULL,
+    message_id INTEGER NOT NULL,
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    PRIMARY KEY (chat_id, menu_date)
+);
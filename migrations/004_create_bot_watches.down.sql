@@ -0,0 +1,2 @@
+DROP TABLE bot_watch_alerts;
+DROP TABLE bot_watches;
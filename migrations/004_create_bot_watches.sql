@@ -0,0 +1,16 @@
+CREATE TABLE bot_watches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id INTEGER NOT NULL,
+    query TEXT NOT NULL DEFAULT '',
+    min_spiciness INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (chat_id, query, min_spiciness)
+);
+
+CREATE TABLE bot_watch_alerts (
+    watch_id INTEGER NOT NULL,
+    menu_date DATE NOT NULL,
+    cafeteria TEXT NOT NULL,
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (watch_id, menu_date, cafeteria)
+);
@@ -0,0 +1 @@
+ALTER TABLE bot_subscriptions DROP COLUMN thread_id;
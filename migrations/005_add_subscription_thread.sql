@@ -0,0 +1 @@
+ALTER TABLE bot_subscriptions ADD COLUMN thread_id INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE bot_sent_messages DROP COLUMN format;
+
+DROP TABLE bot_chat_settings;
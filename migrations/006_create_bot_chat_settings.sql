@@ -0,0 +1,7 @@
+CREATE TABLE bot_chat_settings (
+    chat_id INTEGER PRIMARY KEY,
+    menu_format TEXT NOT NULL DEFAULT 'text',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE bot_sent_messages ADD COLUMN format TEXT NOT NULL DEFAULT 'text';
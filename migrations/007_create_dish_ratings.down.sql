@@ -0,0 +1 @@
+DROP TABLE dish_ratings;
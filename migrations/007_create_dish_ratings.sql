@@ -0,0 +1,13 @@
+CREATE TABLE dish_ratings (
+    dish TEXT NOT NULL,
+    dish_name TEXT NOT NULL,
+    cafeteria TEXT NOT NULL,
+    rater TEXT NOT NULL,
+    menu_date DATE NOT NULL,
+    score INTEGER NOT NULL CHECK (score BETWEEN 1 AND 5),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (dish, rater, menu_date)
+);
+
+CREATE INDEX idx_dish_ratings_menu_date ON dish_ratings(menu_date);
@@ -0,0 +1,2 @@
+ALTER TABLE bot_chat_settings DROP COLUMN avoid;
+ALTER TABLE bot_chat_settings DROP COLUMN max_spiciness;
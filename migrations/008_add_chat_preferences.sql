@@ -0,0 +1,2 @@
+ALTER TABLE bot_chat_settings ADD COLUMN max_spiciness INTEGER NOT NULL DEFAULT 5;
+ALTER TABLE bot_chat_settings ADD COLUMN avoid TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+DROP TABLE menu_revisions;
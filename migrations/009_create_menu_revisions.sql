@@ -0,0 +1,11 @@
+CREATE TABLE menu_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    date DATE NOT NULL,
+    cafeteria TEXT NOT NULL,
+    dishes JSON NOT NULL,
+    added JSON NOT NULL,
+    removed JSON NOT NULL,
+    revised_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_menu_revisions_cafeteria_date ON menu_revisions(cafeteria, date);
@@ -0,0 +1,10 @@
+CREATE TABLE page_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url TEXT NOT NULL,
+    content_hash TEXT NOT NULL,
+    body TEXT NOT NULL,
+    fetched_at TIMESTAMP NOT NULL,
+    UNIQUE(url, content_hash)
+);
+
+CREATE INDEX idx_page_snapshots_url_fetched_at ON page_snapshots(url, fetched_at);
@@ -0,0 +1 @@
+DROP INDEX idx_menu_date_cafeteria;
@@ -0,0 +1 @@
+ALTER TABLE bot_sent_messages DROP COLUMN message_ids;
@@ -0,0 +1 @@
+ALTER TABLE bot_sent_messages ADD COLUMN message_ids TEXT NOT NULL DEFAULT '';